@@ -2,7 +2,25 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/errkind"
+	"crud_app/service"
+)
+
+const (
+	defaultPageLimit int = 50
+	maxPageLimit     int = 500
+)
+
+var (
+	errInvalidRequest = errkind.ErrInvalidRequest
+	errInvalidID      = fmt.Errorf("%w: id is not uuid", errInvalidRequest)
 )
 
 type Result struct {
@@ -10,10 +28,7 @@ type Result struct {
 	Error error
 }
 
-func (r *Result) MarshalJson() ([]byte, error) {
-	if r == nil {
-		return []byte("null"), nil
-	}
+func (r Result) MarshalJSON() ([]byte, error) {
 	rj := struct {
 		Data  any     `json:"data"`
 		Error *string `json:"error"`
@@ -35,13 +50,13 @@ func writeResponseWithJson(w http.ResponseWriter, result Result) {
 	var body []byte
 
 	if result.Error != nil {
-		status = http.StatusInternalServerError
+		status = statusFromError(result.Error)
 	}
-	if json, err := result.MarshalJson(); err != nil {
+	if data, err := json.Marshal(result); err != nil {
 		body = []byte(err.Error())
 		status = http.StatusInternalServerError
 	} else {
-		body = json
+		body = data
 	}
 
 	w.WriteHeader(status)
@@ -54,7 +69,7 @@ func writeResponse(w http.ResponseWriter, result Result) {
 
 	if result.Error != nil {
 		body = []byte(result.Error.Error())
-		status = http.StatusInternalServerError
+		status = statusFromError(result.Error)
 	} else {
 		body = []byte("Success")
 	}
@@ -62,3 +77,86 @@ func writeResponse(w http.ResponseWriter, result Result) {
 	w.WriteHeader(status)
 	w.Write([]byte(body))
 }
+
+// writeActionResponse answers a write that returns no data: "Success" when
+// it worked, and the negotiated error envelope otherwise, so a 403 reads
+// like the errors of every other handler.
+func writeActionResponse(w http.ResponseWriter, r *http.Request, result Result) {
+	if result.Error != nil {
+		writeNegotiatedResponse(w, r, result)
+		return
+	}
+
+	writeResponse(w, result)
+}
+
+// statusClientClosedRequest is the non-standard status recorded when the
+// client goes away before the response is written.
+const statusClientClosedRequest int = 499
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, errNotAcceptable):
+		return http.StatusNotAcceptable
+	case errors.Is(err, errUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, service.ErrUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+
+	switch errkind.Of(err) {
+	case errkind.Unauthenticated:
+		return http.StatusUnauthorized
+	case errkind.Forbidden:
+		return http.StatusForbidden
+	case errkind.Invalid:
+		return http.StatusBadRequest
+	case errkind.NotFound:
+		return http.StatusNotFound
+	case errkind.Unavailable:
+		return http.StatusServiceUnavailable
+	case errkind.Canceled:
+		return statusClientClosedRequest
+	case errkind.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func parsePageRequest(r *http.Request) (dto.PageRequest, error) {
+	page := dto.PageRequest{Limit: defaultPageLimit}
+	query := r.URL.Query()
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return page, fmt.Errorf("%w: limit must be between 1 and %d", errInvalidRequest, maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("%w: offset must be a non-negative integer", errInvalidRequest)
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+func parseAsOf(r *http.Request) (time.Time, bool, error) {
+	v := r.URL.Query().Get("as_of")
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+
+	asOf, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: as_of must be an RFC 3339 timestamp", errInvalidRequest)
+	}
+
+	return asOf, true, nil
+}
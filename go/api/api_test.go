@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/jobs"
+	"crud_app/repository"
+	"crud_app/service"
+)
+
+func TestResult_MarshalJSON(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    any
+		expected string
+	}
+
+	cases := []testCase{
+		{
+			name:     "data",
+			input:    Result{Data: map[string]int{"id": 1}},
+			expected: `{"data":{"id":1},"error":null}`,
+		}, {
+			name:     "error",
+			input:    Result{Error: repository.ErrNotFound},
+			expected: `{"data":null,"error":"record not found"}`,
+		}, {
+			name:     "pointer",
+			input:    &Result{Data: []int{1, 2}},
+			expected: `{"data":[1,2],"error":null}`,
+		}, {
+			name:     "nil pointer",
+			input:    (*Result)(nil),
+			expected: `null`,
+		}, {
+			name:     "nested",
+			input:    map[string]Result{"result": {Error: errInvalidID}},
+			expected: `{"result":{"data":null,"error":"invalid request: id is not uuid"}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.input)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(data))
+		})
+	}
+}
+
+func TestStatusFromError(t *testing.T) {
+	type testCase struct {
+		name     string
+		err      error
+		expected int
+	}
+
+	cases := []testCase{
+		{name: "invalid id", err: errInvalidID, expected: http.StatusBadRequest},
+		{name: "wrapped invalid request", err: fmt.Errorf("%w: limit", errInvalidRequest), expected: http.StatusBadRequest},
+		{name: "invalid filter", err: fmt.Errorf("%w: unknown field", repository.ErrInvalidFilter), expected: http.StatusBadRequest},
+		{name: "not found", err: fmt.Errorf("get: %w", repository.ErrNotFound), expected: http.StatusNotFound},
+		{name: "validation error", err: &service.ValidationError{Rule: "name_required", Message: "name is required"}, expected: http.StatusBadRequest},
+		{name: "missing user", err: &service.ValidationError{Rule: "user_exists", Message: "user with ID 9 not found", Err: repository.ErrNotFound}, expected: http.StatusNotFound},
+		{name: "queue full", err: jobs.ErrQueueFull, expected: http.StatusServiceUnavailable},
+		{name: "not acceptable", err: errNotAcceptable, expected: http.StatusNotAcceptable},
+		{name: "canceled", err: context.Canceled, expected: statusClientClosedRequest},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expected: http.StatusGatewayTimeout},
+		{name: "unknown", err: errService, expected: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, statusFromError(tc.err))
+		})
+	}
+}
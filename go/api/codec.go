@@ -0,0 +1,217 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Codec encodes responses and decodes request bodies for one media type.
+type Codec interface {
+	// Name is used in error messages, e.g. "invalid JSON format".
+	Name() string
+	MediaType() string
+	Encode(w io.Writer, result Result) error
+	Decode(r io.Reader, v any) error
+}
+
+// streamCodec is implemented by codecs that can write a collection one
+// record at a time instead of holding all of it.
+type streamCodec interface {
+	Codec
+	// NewStream starts a collection of elemType records on w.
+	NewStream(w io.Writer, elemType reflect.Type) (recordStream, error)
+}
+
+// recordStream writes the records of one collection; Close completes it.
+type recordStream interface {
+	Write(record any) error
+	Close() error
+}
+
+var (
+	errNotAcceptable        = errors.New("not acceptable")
+	errUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+var (
+	codecsMu sync.RWMutex
+	// codecs is ordered by preference for wildcard Accept ranges; JSON stays
+	// first so clients that send */* keep getting what they always got.
+	codecs = []Codec{jsonCodec{}, ndjsonCodec{}, csvCodec{}, msgpackCodec{}}
+)
+
+// RegisterCodec adds a codec, or replaces the one registered for the same
+// media type.
+func RegisterCodec(codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	i := slices.IndexFunc(codecs, func(c Codec) bool { return c.MediaType() == codec.MediaType() })
+	if i >= 0 {
+		codecs[i] = codec
+		return
+	}
+	codecs = append(codecs, codec)
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate picks the codec for an Accept header, honouring q-values and
+// wildcards. An empty header means JSON.
+func negotiate(accept string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	if strings.TrimSpace(accept) == "" {
+		return codecs[0], true
+	}
+
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b acceptRange) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, r := range ranges {
+		for _, codec := range codecs {
+			if mediaRangeMatches(r.mediaType, codec.MediaType()) {
+				return codec, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func mediaRangeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// codecForContentType picks the codec for a request body. Requests without a
+// Content-Type are read as JSON, as they always were.
+func codecForContentType(contentType string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	if contentType == "" {
+		return codecs[0], nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedMediaType, contentType)
+	}
+	for _, codec := range codecs {
+		if codec.MediaType() == mediaType {
+			return codec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errUnsupportedMediaType, mediaType)
+}
+
+func decodeRequest(r *http.Request, v any) error {
+	codec, err := codecForContentType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+
+	if err := codec.Decode(r.Body, v); err != nil {
+		return fmt.Errorf("%w: invalid %s format", errInvalidRequest, codec.Name())
+	}
+
+	return nil
+}
+
+// writeNegotiatedResponse is writeResponseWithJson for handlers that also
+// serve the other registered formats.
+func writeNegotiatedResponse(w http.ResponseWriter, r *http.Request, result Result) {
+	writeNegotiatedResponseWithStatus(w, r, http.StatusOK, result)
+}
+
+// writeNegotiatedResponseWithStatus uses status instead of 200 when result
+// carries no error.
+func writeNegotiatedResponseWithStatus(w http.ResponseWriter, r *http.Request, status int, result Result) {
+	codec, body, ok := encodeNegotiated(w, r, result)
+	if !ok {
+		return
+	}
+
+	if result.Error != nil {
+		status = statusFromError(result.Error)
+	}
+
+	w.Header().Set("Content-Type", codec.MediaType())
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// encodeNegotiated encodes result in the format r accepts. When there is
+// none, or encoding fails, it answers the request itself and reports false.
+func encodeNegotiated(w http.ResponseWriter, r *http.Request, result Result) (Codec, []byte, bool) {
+	codec, ok := negotiateResponse(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var body bytes.Buffer
+	if err := codec.Encode(&body, result); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil, nil, false
+	}
+
+	return codec, body.Bytes(), true
+}
+
+// negotiateResponse picks the codec of a response from the Accept header of
+// r. When none fits, it answers with a 406 and reports false.
+func negotiateResponse(w http.ResponseWriter, r *http.Request) (Codec, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	codec, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeResponseWithJson(w, Result{Error: fmt.Errorf("%w: %s", errNotAcceptable, r.Header.Get("Accept"))})
+		return nil, false
+	}
+
+	return codec, true
+}
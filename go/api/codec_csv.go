@@ -0,0 +1,323 @@
+package api
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+)
+
+// csvCodec writes a struct or a slice of structs as a header row followed by
+// one row per record. Columns are named by the csv tag, then the json tag,
+// then the field name; fields that are not scalars are written as JSON.
+type csvCodec struct{}
+
+func (csvCodec) Name() string      { return "CSV" }
+func (csvCodec) MediaType() string { return "text/csv" }
+
+func (csvCodec) Encode(w io.Writer, result Result) error {
+	cw := csv.NewWriter(w)
+
+	if result.Error != nil {
+		cw.Write([]string{"error"})
+		cw.Write([]string{result.Error.Error()})
+		cw.Flush()
+		return cw.Error()
+	}
+
+	rows := reflect.ValueOf(result.Data)
+	for rows.Kind() == reflect.Pointer && !rows.IsNil() {
+		rows = rows.Elem()
+	}
+	if rows.Kind() != reflect.Slice {
+		rows = reflect.Append(reflect.MakeSlice(reflect.SliceOf(rows.Type()), 0, 1), rows)
+	}
+
+	elemType := rows.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("%s cannot be written as CSV", elemType)
+	}
+
+	columns := csvColumns(elemType)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i := range rows.Len() {
+		row := rows.Index(i)
+		for row.Kind() == reflect.Pointer {
+			row = row.Elem()
+		}
+
+		record, err := csvRecord(row, columns)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// NewStream writes the header row for elemType straight away, so an empty
+// collection still has one.
+func (csvCodec) NewStream(w io.Writer, elemType reflect.Type) (recordStream, error) {
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s cannot be written as CSV", elemType)
+	}
+
+	columns := csvColumns(elemType)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvStream{cw: cw, columns: columns}, nil
+}
+
+type csvStream struct {
+	cw      *csv.Writer
+	columns []csvColumn
+}
+
+func (s *csvStream) Write(record any) error {
+	row := reflect.ValueOf(record)
+	for row.Kind() == reflect.Pointer {
+		row = row.Elem()
+	}
+
+	cells, err := csvRecord(row, s.columns)
+	if err != nil {
+		return err
+	}
+	if err := s.cw.Write(cells); err != nil {
+		return err
+	}
+
+	// csv.Writer buffers; flushing per record keeps memory flat and lets the
+	// caller decide when to flush the connection.
+	s.cw.Flush()
+	return s.cw.Error()
+}
+
+func (s *csvStream) Close() error {
+	s.cw.Flush()
+	return s.cw.Error()
+}
+
+// Decode reads a header row and exactly one record into a struct pointer.
+func (csvCodec) Decode(r io.Reader, v any) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode CSV into %T", v)
+	}
+	target = target.Elem()
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) != 2 {
+		return errors.New("expected a header row and a single record")
+	}
+
+	columns := map[string]int{}
+	for _, column := range csvColumns(target.Type()) {
+		columns[column.name] = column.index
+	}
+
+	for i, name := range records[0] {
+		index, ok := columns[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("unknown column %q", name)
+		}
+		if err := parseCSVCell(target.Field(index), records[1][i]); err != nil {
+			return fmt.Errorf("column %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+type csvColumn struct {
+	name  string
+	index int
+}
+
+func csvColumns(t reflect.Type) []csvColumn {
+	var columns []csvColumn
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			name = tag
+		} else if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" {
+			name = tag
+		}
+		if name == "-" {
+			continue
+		}
+
+		columns = append(columns, csvColumn{name: name, index: i})
+	}
+
+	return columns
+}
+
+func csvRecord(row reflect.Value, columns []csvColumn) ([]string, error) {
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		cell, err := formatCSVCell(row.Field(column.index))
+		if err != nil {
+			return nil, err
+		}
+		record[i] = cell
+	}
+
+	return record, nil
+}
+
+func formatCSVCell(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Time]():
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return escapeCSVFormula(string(text)), err
+	case v.Type().Implements(jsonMarshalerType):
+		// e.g. gorm.DeletedAt: null becomes an empty cell, a quoted string
+		// its contents.
+		data, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil || string(data) == "null" {
+			return "", err
+		}
+		if s, err := strconv.Unquote(string(data)); err == nil {
+			return escapeCSVFormula(s), nil
+		}
+		return string(data), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return escapeCSVFormula(v.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		data, err := json.Marshal(v.Interface())
+		return string(data), err
+	}
+}
+
+func parseCSVCell(v reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(unescapeCSVFormula(cell)))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(unescapeCSVFormula(cell))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(cell), v.Addr().Interface())
+	}
+
+	return nil
+}
+
+// csvFormulaPrefixes start a cell that spreadsheets would evaluate as a
+// formula. The apostrophe is there so that escaping can be undone.
+const csvFormulaPrefixes string = "=+-@\t\r'"
+
+// escapeCSVFormula keeps text cells from being run as formulas when the
+// export is opened in a spreadsheet, by prefixing them with an apostrophe,
+// which spreadsheets hide. Numbers are written as they are.
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune(csvFormulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
+
+func unescapeCSVFormula(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+
+	return cell
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/dto"
+)
+
+func TestNegotiate(t *testing.T) {
+	type testCase struct {
+		name   string
+		accept string
+		want   string
+		wantOK bool
+	}
+
+	cases := []testCase{
+		{name: "no header", accept: "", want: "application/json", wantOK: true},
+		{name: "exact", accept: "text/csv", want: "text/csv", wantOK: true},
+		{name: "any", accept: "*/*", want: "application/json", wantOK: true},
+		{name: "subtype wildcard", accept: "text/*", want: "text/csv", wantOK: true},
+		{name: "highest q wins", accept: "application/json;q=0.5, application/x-ndjson;q=0.9", want: "application/x-ndjson", wantOK: true},
+		{name: "equal q keeps header order", accept: "application/msgpack, text/csv", want: "application/msgpack", wantOK: true},
+		{name: "q of zero excludes", accept: "text/csv;q=0, */*;q=0.1", want: "application/json", wantOK: true},
+		{name: "unsupported then wildcard", accept: "application/xml, */*;q=0.1", want: "application/json", wantOK: true},
+		{name: "malformed ranges are skipped", accept: "text/;;, text/csv", want: "text/csv", wantOK: true},
+		{name: "malformed q is skipped", accept: "text/csv;q=high, application/x-ndjson", want: "application/x-ndjson", wantOK: true},
+		{name: "nothing acceptable", accept: "application/xml", wantOK: false},
+		{name: "only excluded", accept: "application/json;q=0", wantOK: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			codec, ok := negotiate(tc.accept)
+			require.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				require.Equal(t, tc.want, codec.MediaType())
+			}
+		})
+	}
+}
+
+func TestNegotiateResponse_NotAcceptable(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/list", nil)
+	r.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+
+	_, ok := negotiateResponse(rec, r)
+
+	require.False(t, ok)
+	require.Equal(t, http.StatusNotAcceptable, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.Equal(t, "Accept", rec.Header().Get("Vary"))
+}
+
+func TestCSVCodec_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name     string
+		user     dto.User
+		wantCell string
+	}
+
+	cases := []testCase{
+		{name: "plain", user: dto.User{ID: 1, Name: "Ann", Age: 34}, wantCell: "Ann"},
+		{name: "quotes and commas", user: dto.User{ID: 2, Name: `Smith, "Bo"`, Age: 17}, wantCell: `"Smith, ""Bo"""`},
+		{name: "formula", user: dto.User{ID: 3, Name: "=HYPERLINK(\"http://x\")", Age: 40}, wantCell: `"'=HYPERLINK(""http://x"")"`},
+		{name: "plus", user: dto.User{ID: 4, Name: "+1 555", Age: 40}, wantCell: "'+1 555"},
+		{name: "minus", user: dto.User{ID: 5, Name: "-2+3", Age: 40}, wantCell: "'-2+3"},
+		{name: "at", user: dto.User{ID: 6, Name: "@SUM(A1)", Age: 40}, wantCell: "'@SUM(A1)"},
+		{name: "tab", user: dto.User{ID: 7, Name: "\t=1", Age: 40}, wantCell: "'\t=1"},
+		{name: "apostrophe", user: dto.User{ID: 8, Name: "'=1", Age: 40}, wantCell: "''=1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.user.CreatedAt, tc.user.UpdatedAt = createdAt, createdAt
+
+			var body bytes.Buffer
+			require.NoError(t, csvCodec{}.Encode(&body, Result{Data: tc.user}))
+
+			lines := strings.SplitN(body.String(), "\n", 2)
+			require.Equal(t, "id,name,age,created_at,updated_at,deleted_at", lines[0])
+			require.Contains(t, lines[1], ","+tc.wantCell+",")
+
+			var got dto.User
+			require.NoError(t, csvCodec{}.Decode(&body, &got))
+			require.Equal(t, tc.user, got)
+		})
+	}
+}
+
+func TestCSVCodec_NumbersAreNotEscaped(t *testing.T) {
+	type row struct {
+		Balance int     `csv:"balance"`
+		Rate    float64 `csv:"rate"`
+	}
+
+	var body bytes.Buffer
+	require.NoError(t, csvCodec{}.Encode(&body, Result{Data: []row{{Balance: -5, Rate: -0.5}}}))
+	require.Equal(t, "balance,rate\n-5,-0.5\n", body.String())
+}
+
+func TestCSVCodec_StreamEscapesFormulas(t *testing.T) {
+	var body bytes.Buffer
+	stream, err := csvCodec{}.NewStream(&body, reflect.TypeFor[dto.User]())
+	require.NoError(t, err)
+	require.NoError(t, stream.Write(dto.User{ID: 1, Name: "=1+1"}))
+	require.NoError(t, stream.Close())
+
+	require.Contains(t, body.String(), "\n1,'=1+1,0,")
+}
+
+func TestMsgpackCodec_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+
+	type envelope[T any] struct {
+		Data  T       `json:"data"`
+		Error *string `json:"error"`
+	}
+
+	t.Run("record", func(t *testing.T) {
+		user := dto.User{ID: 1, Name: "Ann", Age: 34, CreatedAt: createdAt, UpdatedAt: createdAt}
+
+		var body bytes.Buffer
+		require.NoError(t, msgpackCodec{}.Encode(&body, Result{Data: user}))
+
+		var got envelope[dto.User]
+		require.NoError(t, msgpackCodec{}.Decode(&body, &got))
+		require.Nil(t, got.Error)
+		require.Equal(t, user.ID, got.Data.ID)
+		require.Equal(t, user.Name, got.Data.Name)
+		require.Equal(t, user.Age, got.Data.Age)
+		require.True(t, user.CreatedAt.Equal(got.Data.CreatedAt))
+		require.False(t, got.Data.DeletedAt.Valid)
+	})
+
+	t.Run("deleted record", func(t *testing.T) {
+		user := dto.User{ID: 1, Name: "Ann", CreatedAt: createdAt}
+		user.DeletedAt.Time, user.DeletedAt.Valid = createdAt.Add(time.Hour), true
+
+		var body bytes.Buffer
+		require.NoError(t, msgpackCodec{}.Encode(&body, Result{Data: user}))
+
+		var got envelope[dto.User]
+		require.NoError(t, msgpackCodec{}.Decode(&body, &got))
+		require.True(t, got.Data.DeletedAt.Valid)
+		require.True(t, user.DeletedAt.Time.Equal(got.Data.DeletedAt.Time))
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		users := []dto.User{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+
+		var body bytes.Buffer
+		require.NoError(t, msgpackCodec{}.Encode(&body, Result{Data: users}))
+
+		var got envelope[[]dto.User]
+		require.NoError(t, msgpackCodec{}.Decode(&body, &got))
+		require.Len(t, got.Data, 2)
+		require.Equal(t, "Bob", got.Data[1].Name)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var body bytes.Buffer
+		require.NoError(t, msgpackCodec{}.Encode(&body, Result{Error: errors.New("boom")}))
+
+		var got envelope[*dto.User]
+		require.NoError(t, msgpackCodec{}.Decode(&body, &got))
+		require.Nil(t, got.Data)
+		require.NotNil(t, got.Error)
+		require.Equal(t, "boom", *got.Error)
+	})
+}
+
+func TestWriteCacheableResponse_StreamsNDJSON(t *testing.T) {
+	users := make([]dto.User, 2000)
+	for i := range users {
+		users[i] = dto.User{ID: uint(i + 1), Name: "User"}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/users/list", nil)
+	r.Header.Set("Accept", "application/x-ndjson")
+	w := &countingWriter{ResponseRecorder: httptest.NewRecorder()}
+
+	writeCacheableResponse(w, r, Result{Data: users}, time.Time{})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	// One write per record rather than one for the whole body.
+	require.Equal(t, len(users), w.writes)
+
+	hash := newEntityTagHash("application/x-ndjson")
+	hash.Write(w.Body.Bytes())
+	require.Equal(t, hash.tag(), w.Header().Get("ETag"))
+}
+
+type countingWriter struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.ResponseRecorder.Write(p)
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gorm.io/gorm"
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string      { return "JSON" }
+func (jsonCodec) MediaType() string { return "application/json" }
+
+func (jsonCodec) Encode(w io.Writer, result Result) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+	return err
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// NewStream writes a JSON array, not the envelope Encode uses: once records
+// are on the wire, there is no error field left to fill in.
+func (jsonCodec) NewStream(w io.Writer, _ reflect.Type) (recordStream, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, err
+	}
+
+	return &jsonStream{w: w}, nil
+}
+
+type jsonStream struct {
+	w       io.Writer
+	written bool
+}
+
+func (s *jsonStream) Write(record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if s.written {
+		if _, err := io.WriteString(s.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	s.written = true
+
+	_, err = s.w.Write(data)
+	return err
+}
+
+func (s *jsonStream) Close() error {
+	_, err := io.WriteString(s.w, "]\n")
+	return err
+}
+
+// ndjsonCodec writes collections one item per line, so clients can process
+// them as they arrive; anything else is a single line.
+type ndjsonCodec struct{}
+
+func (ndjsonCodec) Name() string      { return "NDJSON" }
+func (ndjsonCodec) MediaType() string { return "application/x-ndjson" }
+
+func (ndjsonCodec) Encode(w io.Writer, result Result) error {
+	enc := json.NewEncoder(w)
+
+	if result.Error != nil {
+		return enc.Encode(map[string]string{"error": result.Error.Error()})
+	}
+
+	v := reflect.ValueOf(result.Data)
+	if v.Kind() != reflect.Slice {
+		return enc.Encode(result.Data)
+	}
+
+	for i := range v.Len() {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ndjsonCodec) NewStream(w io.Writer, _ reflect.Type) (recordStream, error) {
+	return ndjsonStream{enc: json.NewEncoder(w)}, nil
+}
+
+type ndjsonStream struct {
+	enc *json.Encoder
+}
+
+func (s ndjsonStream) Write(record any) error {
+	return s.enc.Encode(record)
+}
+
+func (ndjsonStream) Close() error {
+	return nil
+}
+
+// Decode reads a single record; request bodies never carry more than one.
+func (ndjsonCodec) Decode(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("expected a single record")
+	}
+
+	return nil
+}
+
+// msgpackCodec uses the json field names, so the payload has the same shape
+// as the JSON one.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string      { return "MessagePack" }
+func (msgpackCodec) MediaType() string { return "application/msgpack" }
+
+func (msgpackCodec) Encode(w io.Writer, result Result) error {
+	envelope := map[string]any{"data": result.Data, "error": nil}
+	if result.Error != nil {
+		envelope["error"] = result.Error.Error()
+	}
+
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+
+	return enc.Encode(envelope)
+}
+
+func (msgpackCodec) Decode(r io.Reader, v any) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+func init() {
+	// gorm.DeletedAt is a sql.NullTime; encode it like its JSON form, as a
+	// timestamp or nil.
+	msgpack.Register(gorm.DeletedAt{},
+		func(enc *msgpack.Encoder, v reflect.Value) error {
+			deletedAt := v.Interface().(gorm.DeletedAt)
+			if !deletedAt.Valid {
+				return enc.EncodeNil()
+			}
+			return enc.EncodeTime(deletedAt.Time)
+		},
+		func(dec *msgpack.Decoder, v reflect.Value) error {
+			var at *time.Time
+			if err := dec.Decode(&at); err != nil {
+				return err
+			}
+			var deletedAt gorm.DeletedAt
+			if at != nil {
+				deletedAt = gorm.DeletedAt{Time: *at, Valid: true}
+			}
+			v.Set(reflect.ValueOf(deletedAt))
+			return nil
+		},
+	)
+}
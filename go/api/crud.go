@@ -0,0 +1,256 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"crud_app/dto"
+	"crud_app/service"
+)
+
+// filterPrefix marks the query parameters of /list that filter on a field,
+// as in filter.name=Jane. Other parameters are not filters, so ones the
+// handler does not know, like cache busters, are ignored.
+const filterPrefix string = "filter."
+
+// asOfService is implemented by services that keep the history of their
+// records; MountCRUD serves as_of reads from it.
+type asOfService[T any] interface {
+	ListAsOf(ctx context.Context, at time.Time) ([]T, error)
+	GetAsOf(ctx context.Context, id uint, at time.Time) (*T, error)
+}
+
+// versionedService is implemented by services that know when any of their
+// records last changed, deletes included; MountCRUD sends it as the
+// Last-Modified of /list.
+type versionedService interface {
+	LastModified(ctx context.Context) (time.Time, error)
+}
+
+// MountCRUD registers the routes every entity has on router:
+//
+//	GET    /list          ?limit=&offset=&filter.<field>=<value>
+//	GET    /{id}
+//	POST   /create
+//	PUT    /update/{id}
+//	DELETE /delete/{id}
+//	POST   /restore/{id}
+//
+// /list answers with the page of matching records and their total count in
+// the X-Total-Count header. /list and /{id} support conditional requests:
+// see writeCacheableResponse.
+func MountCRUD[T any](router chi.Router, svc service.Service[T]) {
+	router.Get("/list", listHandler(svc))
+
+	router.Get("/{id}", getHandler(svc))
+
+	router.Post("/create", createHandler(svc))
+
+	router.Put("/update/{id}", updateHandler(svc))
+
+	router.Delete("/delete/{id}", deleteHandler(svc))
+
+	router.Post("/restore/{id}", restoreHandler(svc))
+}
+
+func listHandler[T any](svc service.Service[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Answer 406 before the query runs and sets X-Total-Count.
+		if _, ok := negotiate(r.Header.Get("Accept")); !ok {
+			writeNegotiatedResponse(w, r, Result{})
+			return
+		}
+
+		var result Result
+		var lastModified time.Time
+
+		if query, err := parseListQuery(r); err != nil {
+			result.Error = err
+		} else if asOf, ok, err := parseAsOf(r); err != nil {
+			result.Error = err
+		} else if ok {
+			result.Data, result.Error = listAsOf(ctx, svc, asOf, query)
+		} else {
+			// Read the version before the records: a write in between then
+			// leaves Last-Modified older than the page, which costs a later
+			// conditional request a full response instead of a wrong 304.
+			lastModified, result.Error = collectionLastModified(ctx, svc)
+			if result.Error == nil {
+				var items []T
+				var total int64
+				items, total, result.Error = svc.Find(ctx, query)
+				if result.Error == nil {
+					result.Data = items
+					w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+				}
+			}
+		}
+
+		writeCacheableResponse(w, r, result, lastModified)
+	}
+}
+
+func getHandler[T any](svc service.Service[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else if asOf, ok, err := parseAsOf(r); err != nil {
+			result.Error = err
+		} else if ok {
+			result.Data, result.Error = getAsOf(ctx, svc, uint(uuid), asOf)
+		} else {
+			result.Data, result.Error = svc.Get(ctx, uint(uuid))
+		}
+
+		writeCacheableResponse(w, r, result, lastModifiedOf(result.Data))
+	}
+}
+
+func createHandler[T any](svc service.Service[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var entity T
+		var result Result
+
+		if err := decodeRequest(r, &entity); err != nil {
+			result.Error = err
+		} else {
+			result.Data, result.Error = svc.Create(ctx, &entity)
+		}
+
+		writeNegotiatedResponse(w, r, result)
+	}
+}
+
+func updateHandler[T any](svc service.Service[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var entity T
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else if err := decodeRequest(r, &entity); err != nil {
+			result.Error = err
+		} else {
+			result.Error = svc.Update(ctx, &entity, uint(uuid))
+		}
+
+		writeActionResponse(w, r, result)
+	}
+}
+
+func deleteHandler[T any](svc service.Service[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else {
+			result.Error = svc.Delete(ctx, uint(uuid))
+		}
+
+		writeActionResponse(w, r, result)
+	}
+}
+
+func restoreHandler[T any](svc service.Service[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else {
+			result.Error = svc.Restore(ctx, uint(uuid))
+		}
+
+		writeActionResponse(w, r, result)
+	}
+}
+
+func collectionLastModified[T any](ctx context.Context, svc service.Service[T]) (time.Time, error) {
+	versioned, ok := svc.(versionedService)
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	return versioned.LastModified(ctx)
+}
+
+// listAsOf serves a historical list, which can be neither filtered nor
+// paged.
+func listAsOf[T any](ctx context.Context, svc service.Service[T], at time.Time, query dto.ListQuery) ([]T, error) {
+	history, ok := svc.(asOfService[T])
+	if !ok {
+		return nil, fmt.Errorf("%w: as_of is not supported", errInvalidRequest)
+	}
+	if len(query.Filters) > 0 || query.Limit > 0 || query.Offset > 0 {
+		return nil, fmt.Errorf("%w: as_of cannot be combined with filters or paging", errInvalidRequest)
+	}
+
+	return history.ListAsOf(ctx, at)
+}
+
+func getAsOf[T any](ctx context.Context, svc service.Service[T], id uint, at time.Time) (*T, error) {
+	history, ok := svc.(asOfService[T])
+	if !ok {
+		return nil, fmt.Errorf("%w: as_of is not supported", errInvalidRequest)
+	}
+
+	return history.GetAsOf(ctx, id, at)
+}
+
+// parseListQuery reads limit and offset like parsePageRequest, except that
+// no limit returns every match, and takes every parameter with filterPrefix
+// as a filter on the field named by the rest of it.
+func parseListQuery(r *http.Request) (dto.ListQuery, error) {
+	values := r.URL.Query()
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return dto.ListQuery{}, err
+	}
+	if !values.Has("limit") {
+		page.Limit = 0
+	}
+
+	query := dto.ListQuery{PageRequest: page, Filters: map[string]string{}}
+	for key, value := range values {
+		field, ok := strings.CutPrefix(key, filterPrefix)
+		if !ok {
+			continue
+		}
+		if len(value) > 1 {
+			return dto.ListQuery{}, fmt.Errorf("%w: filter %s is given more than once", errInvalidRequest, field)
+		}
+		query.Filters[field] = value[0]
+	}
+
+	return query, nil
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"crud_app/dto"
+	"crud_app/service"
+)
+
+// plainUser hides the history of fakeUser, like a service of an entity that
+// keeps none.
+type plainUser struct {
+	service.Service[dto.User]
+}
+
+func TestMountCRUD(t *testing.T) {
+	cases := []routeCase{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			target: "/plain/list?limit=1",
+		}, {
+			name:   "list as of not supported",
+			method: http.MethodGet,
+			target: "/plain/list?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "get as of not supported",
+			method: http.MethodGet,
+			target: "/plain/1?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "create",
+			method: http.MethodPost,
+			target: "/plain/create",
+			header: jsonBody,
+			body:   `{"Name":"Jill","Age":13}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Route("/plain", func(r chi.Router) {
+				MountCRUD[dto.User](r, plainUser{newFakes().users})
+			})
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			requireGolden(t, formatExchange(tc, rec))
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crud_app/auth"
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/repository"
+)
+
+// fixtureTime keeps timestamps in golden files stable.
+var fixtureTime = time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// fakes hold canned data for the services behind the router. A non-nil err
+// is returned by every call of that service instead.
+type fakes struct {
+	users         *fakeUser
+	feed          *fakeUserFeed
+	imports       *fakeUserImport
+	webhooks      *fakeWebhook
+	authenticator *fakeAuthenticator
+	policy        *auth.Policy
+}
+
+func newFakes() *fakes {
+	deletedAt := fixtureTime.Add(time.Hour)
+	deliveredAt := fixtureTime.Add(time.Minute)
+	active := true
+
+	return &fakes{
+		users: &fakeUser{
+			users: []dto.User{
+				{ID: 1, Name: "John", Age: 10, CreatedAt: fixtureTime, UpdatedAt: fixtureTime},
+				{ID: 2, Name: "Jane", Age: 11, CreatedAt: fixtureTime, UpdatedAt: fixtureTime},
+				{ID: 3, Name: "Jack", Age: 12, CreatedAt: fixtureTime, UpdatedAt: fixtureTime, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+			},
+			history: []dto.UserAudit{
+				{ID: 2, UserID: 1, Operation: dto.AuditOperationUpdate, Actor: "alice", RequestID: "req-2", Diff: json.RawMessage(`{"age":{"old":9,"new":10}}`), CreatedAt: fixtureTime},
+				{ID: 1, UserID: 1, Operation: dto.AuditOperationCreate, Actor: "alice", RequestID: "req-1", Diff: json.RawMessage(`{"age":{"old":null,"new":9},"name":{"old":null,"new":"John"}}`), CreatedAt: fixtureTime},
+			},
+		},
+		feed: &fakeUserFeed{
+			events: []events.Event{
+				{ID: 1, Type: events.UserCreated, TenantID: "default", AggregateID: 1, Payload: json.RawMessage(`{"id":1}`), OccurredAt: fixtureTime},
+				{ID: 2, Type: events.UserUpdated, TenantID: "default", AggregateID: 1, Payload: json.RawMessage(`{"id":1}`), OccurredAt: fixtureTime},
+				{ID: 3, Type: events.UserDeleted, TenantID: "default", AggregateID: 2, Payload: json.RawMessage(`{"id":2}`), OccurredAt: fixtureTime},
+			},
+		},
+		imports: &fakeUserImport{
+			jobs: []dto.Job{
+				{ID: 1, Kind: dto.JobKindUserImport, Status: dto.JobStatusSucceeded, CreatedBy: "alice", BytesTotal: 24, BytesRead: 24, Processed: 2, Valid: 1, Invalid: 1, Imported: 1, CreatedAt: fixtureTime, StartedAt: &fixtureTime, FinishedAt: &fixtureTime},
+			},
+			errors: []dto.JobError{
+				{JobID: 1, Line: 3, Message: "name must be at least 2 characters long"},
+			},
+		},
+		webhooks: &fakeWebhook{
+			subscriptions: []dto.WebhookSubscription{
+				{ID: 1, URL: "https://example.com/hook", EventTypes: []string{events.UserCreated}, Active: &active, CreatedAt: fixtureTime, UpdatedAt: fixtureTime},
+			},
+			deliveries: []dto.WebhookDelivery{
+				{ID: 1, SubscriptionID: 1, EventID: 1, EventType: events.UserCreated, Status: dto.WebhookDeliverySucceeded, Attempts: 1, NextAttemptAt: fixtureTime, LastStatusCode: 204, CreatedAt: fixtureTime, DeliveredAt: &deliveredAt},
+			},
+		},
+		authenticator: &fakeAuthenticator{
+			principals: map[string]*auth.Principal{
+				"acme-token":      {Subject: "alice", TenantID: "acme"},
+				"ops-token":       {Subject: "ops", Roles: []string{"operator"}},
+				"claimless-token": {Subject: "svc", Method: auth.MethodJWT},
+			},
+		},
+		policy: &auth.Policy{
+			Roles: map[string][]string{"operator": {auth.PermissionTenantsAll}},
+		},
+	}
+}
+
+// fakeAuthenticator accepts the bearer tokens of principals.
+type fakeAuthenticator struct {
+	principals map[string]*auth.Principal
+}
+
+func (a *fakeAuthenticator) Authenticate(ctx context.Context, r *http.Request) (*auth.Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing bearer token", auth.ErrUnauthenticated)
+	}
+
+	principal, ok := a.principals[token]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown token", auth.ErrUnauthenticated)
+	}
+
+	return principal, nil
+}
+
+type fakeUser struct {
+	users   []dto.User
+	history []dto.UserAudit
+	err     error
+}
+
+func (f *fakeUser) List(ctx context.Context) ([]dto.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	users := []dto.User{}
+	for _, user := range f.users {
+		if !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
+func (f *fakeUser) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	users, err := f.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for key, value := range query.Filters {
+		if key != "name" {
+			return nil, 0, repository.ErrInvalidFilter
+		}
+		users = slices.DeleteFunc(users, func(u dto.User) bool { return u.Name != value })
+	}
+	total := int64(len(users))
+
+	users = users[min(query.Offset, len(users)):]
+	if query.Limit > 0 {
+		users = users[:min(query.Limit, len(users))]
+	}
+
+	return users, total, nil
+}
+
+func (f *fakeUser) Export(ctx context.Context) iter.Seq2[dto.User, error] {
+	return func(yield func(dto.User, error) bool) {
+		users, err := f.List(ctx)
+		if err != nil {
+			yield(dto.User{}, err)
+			return
+		}
+
+		for _, user := range users {
+			if !yield(user, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (f *fakeUser) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return f.users[:1], nil
+}
+
+func (f *fakeUser) LastModified(ctx context.Context) (time.Time, error) {
+	if f.err != nil {
+		return time.Time{}, f.err
+	}
+
+	return fixtureTime, nil
+}
+
+func (f *fakeUser) Get(ctx context.Context, id uint) (*dto.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	i := slices.IndexFunc(f.users, func(u dto.User) bool { return u.ID == id && !u.DeletedAt.Valid })
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	return &f.users[i], nil
+}
+
+func (f *fakeUser) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	return f.Get(ctx, id)
+}
+
+func (f *fakeUser) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	user.ID = uint(len(f.users) + 1)
+	user.CreatedAt = fixtureTime
+	user.UpdatedAt = fixtureTime
+
+	return user, nil
+}
+
+func (f *fakeUser) Update(ctx context.Context, user *dto.User, id uint) error {
+	_, err := f.Get(ctx, id)
+	return err
+}
+
+func (f *fakeUser) Delete(ctx context.Context, id uint) error {
+	_, err := f.Get(ctx, id)
+	return err
+}
+
+func (f *fakeUser) Restore(ctx context.Context, id uint) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if !slices.ContainsFunc(f.users, func(u dto.User) bool { return u.ID == id && u.DeletedAt.Valid }) {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (f *fakeUser) History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	items := []dto.UserAudit{}
+	for _, entry := range f.history {
+		if entry.UserID == id {
+			items = append(items, entry)
+		}
+	}
+
+	return &dto.Page[dto.UserAudit]{Items: items, Total: int64(len(items)), Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (f *fakeUser) Revert(ctx context.Context, id uint, at time.Time) error {
+	_, err := f.Get(ctx, id)
+	return err
+}
+
+func (f *fakeUser) Purge(ctx context.Context, id uint) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if !slices.ContainsFunc(f.users, func(u dto.User) bool { return u.ID == id }) {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// fakeUserFeed hands out subscriptions that are already closed, so streams
+// end once the backlog is written.
+type fakeUserFeed struct {
+	events []events.Event
+	err    error
+}
+
+func (f *fakeUserFeed) Changes(ctx context.Context, afterID uint64, eventTypes []string, limit int) ([]events.Event, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	changes := []events.Event{}
+	for _, event := range f.events {
+		if event.ID > afterID && (len(eventTypes) == 0 || slices.Contains(eventTypes, event.Type)) && len(changes) < limit {
+			changes = append(changes, event)
+		}
+	}
+
+	return changes, nil
+}
+
+func (f *fakeUserFeed) Subscribe(ctx context.Context, eventTypes []string) (*events.Subscription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	broker := events.NewBroker(1)
+	broker.Close()
+
+	return broker.Subscribe("default", eventTypes), nil
+}
+
+type fakeUserImport struct {
+	jobs   []dto.Job
+	errors []dto.JobError
+	err    error
+}
+
+func (f *fakeUserImport) Start(ctx context.Context, body io.Reader, format string, dryRun bool) (*dto.Job, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Job{
+		ID:         uint64(len(f.jobs) + 1),
+		Kind:       dto.JobKindUserImport,
+		Status:     dto.JobStatusPending,
+		DryRun:     dryRun,
+		CreatedBy:  "alice",
+		BytesTotal: int64(len(data)),
+		CreatedAt:  fixtureTime,
+	}, nil
+}
+
+func (f *fakeUserImport) Job(ctx context.Context, id uint64) (*dto.Job, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	i := slices.IndexFunc(f.jobs, func(j dto.Job) bool { return j.ID == id })
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	return &f.jobs[i], nil
+}
+
+func (f *fakeUserImport) Errors(ctx context.Context, id uint64) ([]dto.JobError, error) {
+	if _, err := f.Job(ctx, id); err != nil {
+		return nil, err
+	}
+
+	jobErrors := []dto.JobError{}
+	for _, jobError := range f.errors {
+		if jobError.JobID == id {
+			jobErrors = append(jobErrors, jobError)
+		}
+	}
+
+	return jobErrors, nil
+}
+
+type fakeWebhook struct {
+	subscriptions []dto.WebhookSubscription
+	deliveries    []dto.WebhookDelivery
+	err           error
+}
+
+func (f *fakeWebhook) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return f.subscriptions, nil
+}
+
+func (f *fakeWebhook) Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	i := slices.IndexFunc(f.subscriptions, func(s dto.WebhookSubscription) bool { return s.ID == id })
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	return &f.subscriptions[i], nil
+}
+
+func (f *fakeWebhook) Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	active := true
+	subscription.ID = uint(len(f.subscriptions) + 1)
+	subscription.Active = &active
+	subscription.CreatedAt = fixtureTime
+	subscription.UpdatedAt = fixtureTime
+
+	return subscription, nil
+}
+
+func (f *fakeWebhook) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	_, err := f.Get(ctx, id)
+	return err
+}
+
+func (f *fakeWebhook) Delete(ctx context.Context, id uint) error {
+	_, err := f.Get(ctx, id)
+	return err
+}
+
+func (f *fakeWebhook) Deliveries(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.WebhookDelivery], error) {
+	if _, err := f.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &dto.Page[dto.WebhookDelivery]{Items: f.deliveries, Total: int64(len(f.deliveries)), Limit: page.Limit, Offset: page.Offset}, nil
+}
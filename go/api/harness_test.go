@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// routeCase is one request against the router; its exchange is compared
+// with testdata/<test>/<case>.golden.
+type routeCase struct {
+	name   string
+	method string
+	target string
+	header map[string]string
+	body   string
+	// setup adjusts the fakes before the request, e.g. to make a service
+	// fail.
+	setup func(*fakes)
+}
+
+func newTestRouter(f *fakes) chi.Router {
+	router := chi.NewRouter()
+	mountTestHandlers(router, f)
+
+	return router
+}
+
+// newAuthenticatedTestRouter puts the handlers behind the authentication
+// middleware the way main does.
+func newAuthenticatedTestRouter(f *fakes) chi.Router {
+	router := chi.NewRouter()
+	router.Group(func(router chi.Router) {
+		UseAuthentication(router, f.authenticator, f.policy)
+		mountTestHandlers(router, f)
+	})
+
+	return router
+}
+
+func mountTestHandlers(router chi.Router, f *fakes) {
+	SetUserHandlers(router, f.users, f.feed, f.imports)
+	SetJobHandlers(router, f.imports)
+	SetWebhookHandlers(router, f.webhooks)
+	SetUserSocketHandlers(router, f.feed, DefaultSocketConfig())
+}
+
+func runRouteCases(t *testing.T, cases []routeCase) {
+	t.Helper()
+
+	runRouteCasesWith(t, newTestRouter, cases)
+}
+
+func runRouteCasesWith(t *testing.T, newRouter func(*fakes) chi.Router, cases []routeCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFakes()
+			if tc.setup != nil {
+				tc.setup(f)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+
+			newRouter(f).ServeHTTP(rec, req)
+
+			requireGolden(t, formatExchange(tc, rec))
+		})
+	}
+}
+
+// formatExchange renders a request and its response with headers in a fixed
+// order, so golden files only change when the behaviour does.
+func formatExchange(tc routeCase, rec *httptest.ResponseRecorder) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "%s %s\n", tc.method, tc.target)
+	for _, key := range slices.Sorted(maps.Keys(tc.header)) {
+		fmt.Fprintf(&b, "%s: %s\n", key, tc.header[key])
+	}
+	if tc.body != "" {
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSuffix(tc.body, "\n"))
+	}
+
+	fmt.Fprintf(&b, "\n%d %s\n", rec.Code, http.StatusText(rec.Code))
+	header := rec.Header()
+	for _, key := range slices.Sorted(maps.Keys(header)) {
+		fmt.Fprintf(&b, "%s: %s\n", key, strings.Join(header[key], ", "))
+	}
+	fmt.Fprintf(&b, "\n%s", rec.Body.String())
+
+	return b.Bytes()
+}
+
+// requireGolden compares got with the test's golden file, or rewrites the
+// file when the tests run with -update.
+func requireGolden(t *testing.T, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden")
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing, run go test ./api -update")
+	require.Equal(t, string(want), string(got))
+}
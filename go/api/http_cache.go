@@ -0,0 +1,176 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCachePolicy lets clients keep a response but makes them revalidate
+// it, which the validators turn into a 304 when nothing changed. Responses
+// depend on the caller, so shared caches must not store them.
+const defaultCachePolicy string = "private, no-cache"
+
+var (
+	cachePoliciesMu sync.RWMutex
+	// cachePolicies maps route patterns to the Cache-Control header of
+	// their cacheable responses.
+	cachePolicies = map[string]string{}
+)
+
+// SetCachePolicy sets the Cache-Control header that the cacheable reads of
+// route send, e.g. SetCachePolicy("/users/{id}", "private, max-age=60").
+// route is the full pattern, mount prefix included.
+func SetCachePolicy(route, policy string) {
+	cachePoliciesMu.Lock()
+	defer cachePoliciesMu.Unlock()
+
+	cachePolicies[route] = policy
+}
+
+func cachePolicy(route string) string {
+	cachePoliciesMu.RLock()
+	defer cachePoliciesMu.RUnlock()
+
+	if policy, ok := cachePolicies[route]; ok {
+		return policy
+	}
+
+	return defaultCachePolicy
+}
+
+// writeCacheableResponse is writeNegotiatedResponse for reads clients may
+// cache. A successful response carries a strong ETag of its body, the
+// route's Cache-Control and, unless lastModified is zero, Last-Modified; a
+// request whose validators still match gets a 304 without the body.
+//
+// The body is encoded twice, once into the ETag hash and once onto the wire,
+// so a large collection is never held encoded in memory and streaming
+// formats such as NDJSON reach the client as they are written.
+func writeCacheableResponse(w http.ResponseWriter, r *http.Request, result Result, lastModified time.Time) {
+	if result.Error != nil {
+		writeNegotiatedResponse(w, r, result)
+		return
+	}
+
+	codec, ok := negotiateResponse(w, r)
+	if !ok {
+		return
+	}
+
+	hash := newEntityTagHash(codec.MediaType())
+	if err := codec.Encode(hash, result); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	etag := hash.tag()
+	header := w.Header()
+	header.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Cache-Control", cachePolicy(routePattern(r)))
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", codec.MediaType())
+	w.WriteHeader(http.StatusOK)
+	// The same result encoded fine a moment ago, so only the connection can
+	// fail here, and the status is already sent.
+	if err := codec.Encode(w, result); err != nil && r.Context().Err() == nil {
+		slog.WarnContext(r.Context(), "response write failed", slog.Any("error", err))
+	}
+}
+
+type entityTagHash struct {
+	hash.Hash
+}
+
+// newEntityTagHash hashes the media type ahead of the body, so every format
+// of a resource gets its own tag.
+func newEntityTagHash(mediaType string) entityTagHash {
+	h := entityTagHash{sha256.New()}
+	h.Write([]byte(mediaType))
+	h.Write([]byte{0})
+
+	return h
+}
+
+func (h entityTagHash) tag() string {
+	return `"` + base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:18]) + `"`
+}
+
+// notModified evaluates the preconditions of a GET as RFC 9110 orders them:
+// If-None-Match when it is present, If-Modified-Since otherwise. HTTP dates
+// have a resolution of one second, so the ETag is the validator to rely on.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches compares an If-None-Match list with etag the weak way, which
+// is what RFC 9110 asks of If-None-Match.
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lastModifiedOf reads the UpdatedAt field of entity, a struct or a pointer
+// to one, and is zero when there is none.
+func lastModifiedOf(entity any) time.Time {
+	v := reflect.ValueOf(entity)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return time.Time{}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return time.Time{}
+	}
+
+	field := v.FieldByName("UpdatedAt")
+	if !field.IsValid() || !field.CanInterface() {
+		return time.Time{}
+	}
+	updatedAt, _ := field.Interface().(time.Time)
+
+	return updatedAt
+}
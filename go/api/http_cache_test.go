@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/dto"
+)
+
+func TestSetCachePolicy(t *testing.T) {
+	SetCachePolicy("/users/{id}", "private, max-age=60")
+	t.Cleanup(func() {
+		cachePoliciesMu.Lock()
+		defer cachePoliciesMu.Unlock()
+		delete(cachePolicies, "/users/{id}")
+	})
+
+	type testCase struct {
+		name   string
+		target string
+		want   string
+	}
+
+	cases := []testCase{{
+		name:   "route with a policy",
+		target: "/users/1",
+		want:   "private, max-age=60",
+	}, {
+		name:   "route without one",
+		target: "/users/list",
+		want:   defaultCachePolicy,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestRouter(newFakes()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tc.want, rec.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	const etag = `"abc"`
+	lastModified := time.Date(2025, 9, 17, 12, 0, 0, 500_000_000, time.UTC)
+
+	type testCase struct {
+		name         string
+		method       string
+		header       map[string]string
+		lastModified time.Time
+		want         bool
+	}
+
+	cases := []testCase{{
+		name: "no validators",
+		want: false,
+	}, {
+		name:   "matching etag",
+		header: map[string]string{"If-None-Match": `"abc"`},
+		want:   true,
+	}, {
+		name:   "matching etag in a list",
+		header: map[string]string{"If-None-Match": `"x", W/"abc" , "y"`},
+		want:   true,
+	}, {
+		name:   "other etag",
+		header: map[string]string{"If-None-Match": `"abd"`},
+		want:   false,
+	}, {
+		name:   "wildcard",
+		header: map[string]string{"If-None-Match": "*"},
+		want:   true,
+	}, {
+		name:   "matching etag on a write",
+		method: http.MethodPost,
+		header: map[string]string{"If-None-Match": `"abc"`},
+		want:   false,
+	}, {
+		name:         "unchanged within the second",
+		header:       map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		lastModified: lastModified,
+		want:         true,
+	}, {
+		name:         "changed since",
+		header:       map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 11:59:59 GMT"},
+		lastModified: lastModified,
+		want:         false,
+	}, {
+		name:   "no last modified",
+		header: map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		want:   false,
+	}, {
+		name:         "malformed date",
+		header:       map[string]string{"If-Modified-Since": "yesterday"},
+		lastModified: lastModified,
+		want:         false,
+	}, {
+		name:         "etag takes precedence",
+		header:       map[string]string{"If-None-Match": `"abd"`, "If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		lastModified: lastModified,
+		want:         false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/", nil)
+			for key, value := range tc.header {
+				r.Header.Set(key, value)
+			}
+
+			require.Equal(t, tc.want, notModified(r, etag, tc.lastModified))
+		})
+	}
+}
+
+func TestLastModifiedOf(t *testing.T) {
+	updatedAt := time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name   string
+		entity any
+		want   time.Time
+	}
+
+	cases := []testCase{{
+		name:   "struct",
+		entity: dto.User{UpdatedAt: updatedAt},
+		want:   updatedAt,
+	}, {
+		name:   "pointer",
+		entity: &dto.User{UpdatedAt: updatedAt},
+		want:   updatedAt,
+	}, {
+		name:   "nil pointer",
+		entity: (*dto.User)(nil),
+	}, {
+		name:   "no UpdatedAt field",
+		entity: struct{ Name string }{Name: "John"},
+	}, {
+		name:   "UpdatedAt of another type",
+		entity: struct{ UpdatedAt string }{UpdatedAt: "now"},
+	}, {
+		name:   "not a struct",
+		entity: []dto.User{{UpdatedAt: updatedAt}},
+	}, {
+		name: "nil",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, lastModifiedOf(tc.entity))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"crud_app/service"
+)
+
+func SetJobHandlers(router chi.Router, userImport service.UserImport) {
+	jobRouter := chi.NewRouter()
+
+	jobRouter.Get("/{id}", getJobHandler(userImport))
+
+	jobRouter.Get("/{id}/errors", jobErrorsHandler(userImport))
+
+	router.Mount("/jobs", jobRouter)
+}
+
+func getJobHandler(userImport service.UserImport) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else {
+			result.Data, result.Error = userImport.Job(ctx, uuid)
+		}
+
+		writeNegotiatedResponse(w, r, result)
+	}
+}
+
+// jobErrorsHandler serves the rejected rows of a job; asked for as CSV, the
+// report comes as a file download.
+func jobErrorsHandler(userImport service.UserImport) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else {
+			result.Data, result.Error = userImport.Errors(ctx, uuid)
+		}
+
+		if codec, ok := negotiate(r.Header.Get("Accept")); ok && result.Error == nil && codec.MediaType() == (csvCodec{}).MediaType() {
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"job-%d-errors.csv\"", uuid))
+		}
+
+		writeNegotiatedResponse(w, r, result)
+	}
+}
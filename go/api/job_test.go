@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJobRoutes(t *testing.T) {
+	cases := []routeCase{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			target: "/jobs/1",
+		}, {
+			name:   "get not found",
+			method: http.MethodGet,
+			target: "/jobs/42",
+		}, {
+			name:   "get malformed id",
+			method: http.MethodGet,
+			target: "/jobs/abc",
+		}, {
+			name:   "get service error",
+			method: http.MethodGet,
+			target: "/jobs/1",
+			setup:  func(f *fakes) { f.imports.err = errService },
+		}, {
+			name:   "errors",
+			method: http.MethodGet,
+			target: "/jobs/1/errors",
+		}, {
+			name:   "errors csv",
+			method: http.MethodGet,
+			target: "/jobs/1/errors",
+			header: acceptCSV,
+		}, {
+			name:   "errors not found",
+			method: http.MethodGet,
+			target: "/jobs/42/errors",
+			header: acceptCSV,
+		}, {
+			name:   "errors malformed id",
+			method: http.MethodGet,
+			target: "/jobs/abc/errors",
+		},
+	}
+
+	runRouteCases(t, cases)
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/auth"
+	"crud_app/tenant"
+)
+
+func TestResolveTenant(t *testing.T) {
+	type testCase struct {
+		name       string
+		principal  *auth.Principal
+		header     string
+		wantStatus int
+		wantTenant string
+		wantError  string
+	}
+
+	policy := &auth.Policy{
+		Roles: map[string][]string{"operator": {auth.PermissionTenantsAll}},
+	}
+	operator := &auth.Principal{Subject: "ops", Roles: []string{"operator"}}
+
+	cases := []testCase{{
+		name:       "bound principal gets its tenant",
+		principal:  &auth.Principal{Subject: "alice", TenantID: "acme"},
+		wantStatus: http.StatusOK,
+		wantTenant: "acme",
+	}, {
+		name:       "bound principal may repeat its tenant",
+		principal:  &auth.Principal{Subject: "alice", TenantID: "acme"},
+		header:     "acme",
+		wantStatus: http.StatusOK,
+		wantTenant: "acme",
+	}, {
+		name:       "bound principal cannot choose another tenant",
+		principal:  &auth.Principal{Subject: "alice", TenantID: "acme"},
+		header:     "globex",
+		wantStatus: http.StatusForbidden,
+		wantError:  `forbidden: tenant "globex" is not accessible`,
+	}, {
+		name:       "unbound principal chooses the tenant",
+		principal:  operator,
+		header:     "globex",
+		wantStatus: http.StatusOK,
+		wantTenant: "globex",
+	}, {
+		name:       "unbound principal without tenants:all",
+		principal:  &auth.Principal{Subject: "svc", Method: auth.MethodAPIKey, Roles: []string{"reader"}},
+		header:     "globex",
+		wantStatus: http.StatusForbidden,
+		wantError:  "forbidden: missing permission tenants:all",
+	}, {
+		name:       "unbound principal without a tenant",
+		principal:  operator,
+		wantStatus: http.StatusBadRequest,
+		wantError:  tenant.ErrRequired.Error(),
+	}, {
+		name:       "malformed tenant",
+		principal:  operator,
+		header:     "acme/../globex",
+		wantStatus: http.StatusBadRequest,
+		wantError:  tenant.ErrInvalid.Error(),
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotTenant string
+			handler := ResolveTenant(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var err error
+				gotTenant, err = tenant.ID(r.Context())
+				require.NoError(t, err)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/users/list", nil)
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tc.principal))
+			if tc.header != "" {
+				r.Header.Set(tenantHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, r)
+
+			require.Equal(t, tc.wantStatus, rec.Code)
+			require.Equal(t, tc.wantTenant, gotTenant)
+			if tc.wantError != "" {
+				var body struct{ Error string }
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				require.Equal(t, tc.wantError, body.Error)
+			}
+		})
+	}
+}
+
+func TestSocketCredentials(t *testing.T) {
+	type testCase struct {
+		name          string
+		target        string
+		upgrade       bool
+		authorization string
+		wantAuth      string
+		wantTenant    string
+		wantQuery     string
+	}
+
+	cases := []testCase{{
+		name:       "moves credentials to headers",
+		target:     "/ws/users?access_token=secret&tenant_id=acme&types=UserCreated",
+		upgrade:    true,
+		wantAuth:   "Bearer secret",
+		wantTenant: "acme",
+		wantQuery:  "types=UserCreated",
+	}, {
+		name:          "header wins over the query",
+		target:        "/ws/users?access_token=secret&tenant_id=acme",
+		upgrade:       true,
+		authorization: "Bearer other",
+		wantAuth:      "Bearer other",
+		wantTenant:    "acme",
+		wantQuery:     "",
+	}, {
+		name:      "ignores requests that do not upgrade",
+		target:    "/users/list?access_token=secret&tenant_id=acme",
+		wantQuery: "access_token=secret&tenant_id=acme",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *http.Request
+			handler := SocketCredentials(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.upgrade {
+				r.Header.Set("Upgrade", "websocket")
+			}
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			require.NotNil(t, got)
+			require.Equal(t, tc.wantAuth, got.Header.Get("Authorization"))
+			require.Equal(t, tc.wantTenant, got.Header.Get(tenantHeader))
+			require.Equal(t, tc.wantQuery, got.URL.RawQuery)
+		})
+	}
+}
+
+func TestAuthenticatedRoutes(t *testing.T) {
+	acmeToken := map[string]string{"Authorization": "Bearer acme-token"}
+	upgrade := map[string]string{
+		"Connection":            "Upgrade",
+		"Upgrade":               "websocket",
+		"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+		"Sec-WebSocket-Version": "13",
+	}
+
+	cases := []routeCase{
+		{
+			name:   "bound principal",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: acmeToken,
+		}, {
+			name:   "without credentials",
+			method: http.MethodGet,
+			target: "/users/1",
+		}, {
+			name:   "unknown token",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"Authorization": "Bearer expired-token"},
+		}, {
+			name:   "tenant of another principal",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"Authorization": "Bearer acme-token", "X-Tenant-ID": "globex"},
+		}, {
+			name:   "token without a tenant claim",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: map[string]string{"Authorization": "Bearer claimless-token", "X-Tenant-ID": "globex"},
+		}, {
+			name:   "unbound principal without a tenant",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"Authorization": "Bearer ops-token"},
+		}, {
+			name:   "unbound principal with a malformed tenant",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"Authorization": "Bearer ops-token", "X-Tenant-ID": "acme/../globex"},
+		}, {
+			name:   "socket credentials in the query",
+			method: http.MethodGet,
+			target: "/ws/users?access_token=ops-token&tenant_id=globex",
+			header: upgrade,
+			// Failing the subscription shows the request got past
+			// authentication without a recorder having to hijack.
+			setup: func(f *fakes) { f.feed.err = errService },
+		}, {
+			name:   "socket credentials in the query of a plain request",
+			method: http.MethodGet,
+			target: "/users/1?access_token=ops-token&tenant_id=globex",
+		},
+	}
+
+	runRouteCasesWith(t, newAuthenticatedTestRouter, cases)
+}
@@ -1,8 +1,8 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -12,70 +12,123 @@ import (
 	"crud_app/service"
 )
 
-func SetUserHandlers(router *chi.Mux, userService service.User) {
+func SetUserHandlers(router chi.Router, userService service.User, userFeed service.UserFeed, userImport service.UserImport) {
 	userRouter := chi.NewRouter()
 
-	userRouter.Get("/list", listUserHandler(userService))
+	MountCRUD(userRouter, userService)
 
-	userRouter.Post("/create", createUserHandler(userService))
+	userRouter.Get("/export", exportUserHandler(userService))
 
-	userRouter.Put("/update/{id}", updateUserHandler(userService))
+	userRouter.Get("/stream", streamUserHandler(userFeed))
 
-	userRouter.Delete("/delete/{id}", deleteUserHandler(userService))
+	userRouter.Post("/import", importUserHandler(userImport))
+
+	userRouter.Get("/{id}/history", userHistoryHandler(userService))
+
+	userRouter.Post("/revert/{id}", revertUserHandler(userService))
+
+	userRouter.Delete("/purge/{id}", purgeUserHandler(userService))
 
 	router.Mount("/users", userRouter)
 }
 
-func listUserHandler(userService service.User) http.HandlerFunc {
+// importUserHandler takes a CSV or NDJSON upload and answers 202 with the
+// job importing it, which can be followed at /jobs/{id}.
+func importUserHandler(userImport service.UserImport) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		var result Result
-		result.Data, result.Error = userService.List(ctx)
 
-		writeResponseWithJson(w, result)
+		if format, err := importFormat(r.Header.Get("Content-Type")); err != nil {
+			result.Error = err
+		} else if dryRun, err := parseDryRun(r); err != nil {
+			result.Error = err
+		} else {
+			var job *dto.Job
+			job, result.Error = userImport.Start(ctx, r.Body, format, dryRun)
+			if result.Error == nil {
+				result.Data = job
+				w.Header().Set("Location", fmt.Sprintf("/jobs/%d", job.ID))
+			}
+		}
+
+		writeNegotiatedResponseWithStatus(w, r, http.StatusAccepted, result)
+	}
+}
+
+func importFormat(contentType string) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case csvCodec{}.MediaType():
+		return service.ImportFormatCSV, nil
+	case ndjsonCodec{}.MediaType():
+		return service.ImportFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: imports must be %s or %s", errUnsupportedMediaType, csvCodec{}.MediaType(), ndjsonCodec{}.MediaType())
 	}
 }
 
-func createUserHandler(userService service.User) http.HandlerFunc {
+func parseDryRun(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("dry_run")
+	if v == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%w: dry_run must be a boolean", errInvalidRequest)
+	}
+
+	return dryRun, nil
+}
+
+func userHistoryHandler(userService service.User) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
 
-		var user dto.User
 		var result Result
 
-		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-			result.Error = fmt.Errorf("invalid JSON format")
+		if err != nil {
+			result.Error = errInvalidID
+		} else if page, err := parsePageRequest(r); err != nil {
+			result.Error = err
 		} else {
-			result.Data, result.Error = userService.Create(ctx, &user)
+			result.Data, result.Error = userService.History(ctx, uint(uuid), page)
 		}
 
-		writeResponseWithJson(w, result)
+		writeNegotiatedResponse(w, r, result)
 	}
 }
 
-func updateUserHandler(userService service.User) http.HandlerFunc {
+func revertUserHandler(userService service.User) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		id := chi.URLParam(r, "id")
 		uuid, err := strconv.ParseUint(id, 10, 64)
 
-		var user dto.User
 		var result Result
 
 		if err != nil {
-			result.Error = fmt.Errorf("id is not uuid")
-		} else if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-			result.Error = fmt.Errorf("invalid JSON format")
+			result.Error = errInvalidID
+		} else if asOf, ok, err := parseAsOf(r); err != nil {
+			result.Error = err
+		} else if !ok {
+			result.Error = fmt.Errorf("%w: as_of is required", errInvalidRequest)
 		} else {
-			result.Error = userService.Update(ctx, &user, uint(uuid))
+			result.Error = userService.Revert(ctx, uint(uuid), asOf)
 		}
 
-		writeResponse(w, result)
+		writeActionResponse(w, r, result)
 	}
 }
 
-func deleteUserHandler(userService service.User) http.HandlerFunc {
+// purgeUserHandler removes a user for good; DELETE /delete/{id} is the soft
+// delete that /restore/{id} undoes.
+func purgeUserHandler(userService service.User) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		id := chi.URLParam(r, "id")
@@ -84,11 +137,11 @@ func deleteUserHandler(userService service.User) http.HandlerFunc {
 		var result Result
 
 		if err != nil {
-			result.Error = fmt.Errorf("id is not uuid")
+			result.Error = errInvalidID
 		} else {
-			result.Error = userService.Delete(ctx, uint(uuid))
+			result.Error = userService.Purge(ctx, uint(uuid))
 		}
 
-		writeResponse(w, result)
+		writeActionResponse(w, r, result)
 	}
 }
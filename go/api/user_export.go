@@ -0,0 +1,165 @@
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"crud_app/dto"
+	"crud_app/service"
+)
+
+// exportFlushEvery is the number of records written between flushes, so
+// clients see progress without a flush per row.
+const exportFlushEvery int = 1000
+
+// exportUserHandler streams every user as a JSON array, NDJSON or CSV,
+// gzipped when the client accepts it. Rows are read from a single snapshot
+// and written as they arrive; a client that goes away cancels the query.
+func exportUserHandler(userService service.User) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		codec, ok := negotiate(r.Header.Get("Accept"))
+		streamer, streams := codec.(streamCodec)
+		if !ok || !streams {
+			writeResponseWithJson(w, Result{Error: fmt.Errorf("%w: %s", errNotAcceptable, r.Header.Get("Accept"))})
+			return
+		}
+
+		var export *exportWriter
+		for user, err := range userService.Export(ctx) {
+			if err == nil && export == nil {
+				export, err = newExportWriter(w, r, streamer)
+			}
+			if err == nil {
+				err = export.Write(user)
+			}
+			if err != nil {
+				// Nothing is on the wire yet, so the error can still be
+				// answered properly.
+				if export == nil {
+					writeNegotiatedResponse(w, r, Result{Error: err})
+					return
+				}
+				abortExport(ctx, err)
+			}
+		}
+
+		if export == nil {
+			var err error
+			if export, err = newExportWriter(w, r, streamer); err != nil {
+				abortExport(ctx, err)
+			}
+		}
+		if err := export.Close(); err != nil {
+			abortExport(ctx, err)
+		}
+	}
+}
+
+// abortExport ends a response that has already started. Aborting rather than
+// returning keeps the client from taking a truncated export for a whole one.
+func abortExport(ctx context.Context, err error) {
+	if ctx.Err() == nil {
+		slog.ErrorContext(ctx, "user export failed", slog.Any("error", err))
+	}
+
+	panic(http.ErrAbortHandler)
+}
+
+type exportWriter struct {
+	rc      *http.ResponseController
+	gz      *gzip.Writer
+	stream  recordStream
+	written int
+}
+
+func newExportWriter(w http.ResponseWriter, r *http.Request, codec streamCodec) (*exportWriter, error) {
+	export := &exportWriter{rc: http.NewResponseController(w)}
+
+	w.Header().Set("Content-Type", codec.MediaType())
+	if codec.MediaType() == (csvCodec{}).MediaType() {
+		w.Header().Set("Content-Disposition", "attachment; filename=\"users.csv\"")
+	}
+
+	var body io.Writer = w
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		w.Header().Set("Content-Encoding", "gzip")
+		export.gz = gzip.NewWriter(w)
+		body = export.gz
+	}
+	w.WriteHeader(http.StatusOK)
+
+	stream, err := codec.NewStream(body, reflect.TypeFor[dto.User]())
+	if err != nil {
+		return nil, err
+	}
+	export.stream = stream
+
+	return export, nil
+}
+
+func (e *exportWriter) Write(user dto.User) error {
+	if err := e.stream.Write(user); err != nil {
+		return err
+	}
+
+	e.written++
+	if e.written%exportFlushEvery == 0 {
+		return e.flush()
+	}
+
+	return nil
+}
+
+func (e *exportWriter) Close() error {
+	if err := e.stream.Close(); err != nil {
+		return err
+	}
+	if e.gz != nil {
+		return e.gz.Close()
+	}
+
+	return nil
+}
+
+func (e *exportWriter) flush() error {
+	if e.gz != nil {
+		if err := e.gz.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return e.rc.Flush()
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either
+// by name or through a wildcard, with a non-zero q-value.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		return q > 0
+	}
+
+	return false
+}
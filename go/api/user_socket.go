@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/go-chi/chi/v5"
+
+	"crud_app/events"
+	"crud_app/service"
+)
+
+const (
+	socketSubscribe    string = "subscribe"
+	socketUnsubscribe  string = "unsubscribe"
+	socketSubscribed   string = "subscribed"
+	socketUnsubscribed string = "unsubscribed"
+	socketChange       string = "change"
+	socketError        string = "error"
+)
+
+type SocketConfig struct {
+	// OriginPatterns lists the cross-origin hosts allowed to connect; same
+	// origin requests are always accepted.
+	OriginPatterns []string
+	// SendQueue is the number of outgoing messages a connection may have
+	// pending before it is closed as too slow.
+	SendQueue        int
+	MaxSubscriptions int
+	PingInterval     time.Duration
+	WriteTimeout     time.Duration
+	ReadLimit        int64
+}
+
+func DefaultSocketConfig() SocketConfig {
+	return SocketConfig{
+		SendQueue:        256,
+		MaxSubscriptions: 32,
+		PingInterval:     30 * time.Second,
+		WriteTimeout:     10 * time.Second,
+		ReadLimit:        64 << 10,
+	}
+}
+
+// socketRequest is sent by clients. A subscription matches events of the
+// listed users and, when Filter is set, of the filter expression; both are
+// optional and an empty subscription matches every user change.
+type socketRequest struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	UserIDs []uint `json:"user_ids,omitempty"`
+	Filter  string `json:"filter,omitempty"`
+}
+
+type socketMessage struct {
+	Type          string        `json:"type"`
+	ID            string        `json:"id,omitempty"`
+	Subscriptions []string      `json:"subscriptions,omitempty"`
+	Event         *events.Event `json:"event,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+type socketSubscription struct {
+	userIDs []uint
+	filter  *events.Filter
+}
+
+func (s socketSubscription) match(event events.Event) bool {
+	if len(s.userIDs) > 0 && !slices.Contains(s.userIDs, event.AggregateID) {
+		return false
+	}
+
+	return s.filter == nil || s.filter.Match(event)
+}
+
+var errSocketSlow = errors.New("send queue full")
+
+func SetUserSocketHandlers(router chi.Router, userFeed service.UserFeed, cfg SocketConfig) {
+	router.Get("/ws/users", userSocketHandler(userFeed, cfg))
+}
+
+// userSocketHandler authenticates and authorizes on the upgrade request, so
+// failures are reported with a regular HTTP status before the handshake.
+func userSocketHandler(userFeed service.UserFeed, cfg SocketConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		feed, err := userFeed.Subscribe(ctx, nil)
+		if err != nil {
+			writeResponseWithJson(w, Result{Error: err})
+			return
+		}
+		defer feed.Close()
+
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: cfg.OriginPatterns})
+		if err != nil {
+			slog.InfoContext(ctx, "websocket upgrade failed", slog.Any("error", err))
+			return
+		}
+		conn.SetReadLimit(cfg.ReadLimit)
+
+		session := &socketSession{
+			conn:          conn,
+			cfg:           cfg,
+			send:          make(chan socketMessage, cfg.SendQueue),
+			subscriptions: make(map[string]socketSubscription),
+		}
+
+		err = session.run(ctx, feed)
+
+		status := websocket.CloseStatus(err)
+		switch {
+		case status != -1:
+		case errors.Is(err, errSocketSlow):
+			conn.Close(websocket.StatusTryAgainLater, err.Error())
+		case ctx.Err() != nil, err == nil:
+			conn.Close(websocket.StatusGoingAway, "")
+		default:
+			slog.InfoContext(ctx, "websocket closed", slog.Any("error", err))
+			conn.Close(websocket.StatusInternalError, "")
+		}
+	}
+}
+
+type socketSession struct {
+	conn *websocket.Conn
+	cfg  SocketConfig
+	send chan socketMessage
+
+	mu            sync.Mutex
+	subscriptions map[string]socketSubscription
+}
+
+// run serves the session until the feed ends or a loop fails. The loops read
+// and write with ctx rather than the session context: the websocket library
+// drops the connection when an operation is canceled, which would lose the
+// close frame the handler sends once run returns.
+func (s *socketSession) run(ctx context.Context, feed *events.Subscription) error {
+	session, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go func() { cancel(s.readLoop(ctx)) }()
+	go func() { cancel(s.writeLoop(ctx, session)) }()
+
+	ping := time.NewTicker(s.cfg.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-session.Done():
+			return context.Cause(session)
+		case event, ok := <-feed.Events():
+			if !ok {
+				if feed.Lagged() {
+					return errSocketSlow
+				}
+				return nil
+			}
+			if ids := s.matching(event); len(ids) > 0 {
+				if err := s.enqueue(socketMessage{Type: socketChange, Subscriptions: ids, Event: &event}); err != nil {
+					return err
+				}
+			}
+		case <-ping.C:
+			pingCtx, cancelPing := context.WithTimeout(session, s.cfg.WriteTimeout)
+			err := s.conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *socketSession) readLoop(ctx context.Context) error {
+	for {
+		var req socketRequest
+		if err := wsjson.Read(ctx, s.conn, &req); err != nil {
+			return err
+		}
+
+		if err := s.enqueue(s.handle(req)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *socketSession) writeLoop(ctx, session context.Context) error {
+	for {
+		select {
+		case <-session.Done():
+			return session.Err()
+		case msg := <-s.send:
+			writeCtx, cancel := context.WithTimeout(ctx, s.cfg.WriteTimeout)
+			err := wsjson.Write(writeCtx, s.conn, msg)
+			cancel()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// enqueue never blocks; a client that does not keep up is disconnected and
+// expected to reconnect and resubscribe.
+func (s *socketSession) enqueue(msg socketMessage) error {
+	select {
+	case s.send <- msg:
+		return nil
+	default:
+		return errSocketSlow
+	}
+}
+
+func (s *socketSession) handle(req socketRequest) socketMessage {
+	if req.ID == "" {
+		return socketMessage{Type: socketError, Error: "id is required"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Type {
+	case socketSubscribe:
+		if _, ok := s.subscriptions[req.ID]; !ok && len(s.subscriptions) >= s.cfg.MaxSubscriptions {
+			return socketMessage{Type: socketError, ID: req.ID, Error: fmt.Sprintf("at most %d subscriptions per connection", s.cfg.MaxSubscriptions)}
+		}
+
+		subscription := socketSubscription{userIDs: req.UserIDs}
+		if req.Filter != "" {
+			filter, err := events.ParseFilter(req.Filter)
+			if err != nil {
+				return socketMessage{Type: socketError, ID: req.ID, Error: err.Error()}
+			}
+			subscription.filter = filter
+		}
+		s.subscriptions[req.ID] = subscription
+
+		return socketMessage{Type: socketSubscribed, ID: req.ID}
+	case socketUnsubscribe:
+		delete(s.subscriptions, req.ID)
+
+		return socketMessage{Type: socketUnsubscribed, ID: req.ID}
+	default:
+		return socketMessage{Type: socketError, ID: req.ID, Error: fmt.Sprintf("unknown message type %q", req.Type)}
+	}
+}
+
+func (s *socketSession) matching(event events.Event) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for id, subscription := range s.subscriptions {
+		if subscription.match(event) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	return ids
+}
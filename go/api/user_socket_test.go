@@ -0,0 +1,358 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"crud_app/events"
+)
+
+// brokerUserFeed subscribes to a real broker, so a test can publish while a
+// client is connected, and hands every subscription to the test as well.
+type brokerUserFeed struct {
+	fakeUserFeed
+	broker        *events.Broker
+	subscriptions chan *events.Subscription
+}
+
+func (f *brokerUserFeed) Subscribe(ctx context.Context, eventTypes []string) (*events.Subscription, error) {
+	subscription := f.broker.Subscribe("default", eventTypes)
+	f.subscriptions <- subscription
+
+	return subscription, nil
+}
+
+type socketClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+	feed *brokerUserFeed
+}
+
+// dialUserSocket connects to /ws/users on a real server and waits until the
+// handler has subscribed to the feed.
+func dialUserSocket(t *testing.T, cfg SocketConfig, buffer int) *socketClient {
+	feed := &brokerUserFeed{broker: events.NewBroker(buffer), subscriptions: make(chan *events.Subscription, 1)}
+	router := chi.NewRouter()
+	SetUserSocketHandlers(router, feed, cfg)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws/users", nil)
+	require.NoError(t, err)
+	conn.SetReadLimit(1 << 20)
+	t.Cleanup(func() { conn.CloseNow() })
+
+	client := &socketClient{t: t, conn: conn, feed: feed}
+	client.subscription()
+
+	return client
+}
+
+// subscription returns the feed subscription of the connection.
+func (c *socketClient) subscription() *events.Subscription {
+	select {
+	case subscription := <-c.feed.subscriptions:
+		c.feed.subscriptions <- subscription
+		return subscription
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("socket did not subscribe to the feed")
+		return nil
+	}
+}
+
+func (c *socketClient) send(req socketRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(c.t, wsjson.Write(ctx, c.conn, req))
+}
+
+func (c *socketClient) receive() socketMessage {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var msg socketMessage
+	require.NoError(c.t, wsjson.Read(ctx, c.conn, &msg))
+
+	return msg
+}
+
+// closeStatus reads until the server closes the connection.
+func (c *socketClient) closeStatus() websocket.StatusCode {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for {
+		if _, _, err := c.conn.Read(ctx); err != nil {
+			require.NoError(c.t, ctx.Err())
+			return websocket.CloseStatus(err)
+		}
+	}
+}
+
+func testSocketEvent(id uint64, eventType string, userID uint, age int) events.Event {
+	return events.Event{
+		ID:          id,
+		Type:        eventType,
+		TenantID:    "default",
+		AggregateID: userID,
+		Payload:     json.RawMessage(fmt.Sprintf(`{"id":%d,"age":%d}`, userID, age)),
+		OccurredAt:  fixtureTime,
+	}
+}
+
+func TestUserSocket_Requests(t *testing.T) {
+	type testCase struct {
+		name string
+		req  socketRequest
+		want socketMessage
+	}
+
+	cases := []testCase{
+		{
+			name: "subscribe",
+			req:  socketRequest{Type: socketSubscribe, ID: "a", UserIDs: []uint{1}, Filter: "age >= 18"},
+			want: socketMessage{Type: socketSubscribed, ID: "a"},
+		}, {
+			name: "unsubscribe unknown id",
+			req:  socketRequest{Type: socketUnsubscribe, ID: "a"},
+			want: socketMessage{Type: socketUnsubscribed, ID: "a"},
+		}, {
+			name: "missing id",
+			req:  socketRequest{Type: socketSubscribe},
+			want: socketMessage{Type: socketError, Error: "id is required"},
+		}, {
+			name: "invalid filter",
+			req:  socketRequest{Type: socketSubscribe, ID: "a", Filter: "age >="},
+			want: socketMessage{Type: socketError, ID: "a", Error: "invalid filter: unexpected end of expression"},
+		}, {
+			name: "unknown type",
+			req:  socketRequest{Type: "publish", ID: "a"},
+			want: socketMessage{Type: socketError, ID: "a", Error: `unknown message type "publish"`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := dialUserSocket(t, DefaultSocketConfig(), 16)
+
+			client.send(tc.req)
+
+			require.Equal(t, tc.want, client.receive())
+		})
+	}
+}
+
+func TestUserSocket_Changes(t *testing.T) {
+	type change struct {
+		eventID       uint64
+		subscriptions []string
+	}
+
+	type testCase struct {
+		name     string
+		requests []socketRequest
+		events   []events.Event
+		want     []change
+	}
+
+	subscribe := func(id string, userIDs []uint, filter string) socketRequest {
+		return socketRequest{Type: socketSubscribe, ID: id, UserIDs: userIDs, Filter: filter}
+	}
+
+	cases := []testCase{
+		{
+			name:     "empty subscription matches every change",
+			requests: []socketRequest{subscribe("all", nil, "")},
+			events: []events.Event{
+				testSocketEvent(1, events.UserCreated, 1, 10),
+				testSocketEvent(2, events.UserDeleted, 2, 20),
+			},
+			want: []change{{1, []string{"all"}}, {2, []string{"all"}}},
+		}, {
+			name:     "user ids",
+			requests: []socketRequest{subscribe("some", []uint{1, 3}, "")},
+			events: []events.Event{
+				testSocketEvent(1, events.UserCreated, 1, 10),
+				testSocketEvent(2, events.UserCreated, 2, 10),
+				testSocketEvent(3, events.UserCreated, 3, 10),
+			},
+			want: []change{{1, []string{"some"}}, {3, []string{"some"}}},
+		}, {
+			name:     "filter",
+			requests: []socketRequest{subscribe("adults", nil, "age >= 18")},
+			events: []events.Event{
+				testSocketEvent(1, events.UserUpdated, 1, 17),
+				testSocketEvent(2, events.UserUpdated, 1, 18),
+			},
+			want: []change{{2, []string{"adults"}}},
+		}, {
+			name:     "user ids and filter must both match",
+			requests: []socketRequest{subscribe("updates", []uint{1}, `type == "UserUpdated"`)},
+			events: []events.Event{
+				testSocketEvent(1, events.UserCreated, 1, 10),
+				testSocketEvent(2, events.UserUpdated, 2, 10),
+				testSocketEvent(3, events.UserUpdated, 1, 10),
+			},
+			want: []change{{3, []string{"updates"}}},
+		}, {
+			name:     "change lists every matching subscription",
+			requests: []socketRequest{subscribe("john", []uint{1}, ""), subscribe("all", nil, "")},
+			events: []events.Event{
+				testSocketEvent(1, events.UserUpdated, 1, 10),
+				testSocketEvent(2, events.UserUpdated, 2, 10),
+			},
+			want: []change{{1, []string{"all", "john"}}, {2, []string{"all"}}},
+		}, {
+			name: "subscribing again replaces the subscription",
+			requests: []socketRequest{
+				subscribe("a", []uint{1}, ""),
+				subscribe("a", []uint{2}, ""),
+			},
+			events: []events.Event{
+				testSocketEvent(1, events.UserUpdated, 1, 10),
+				testSocketEvent(2, events.UserUpdated, 2, 10),
+			},
+			want: []change{{2, []string{"a"}}},
+		}, {
+			name: "unsubscribe",
+			requests: []socketRequest{
+				subscribe("a", nil, ""),
+				subscribe("b", nil, ""),
+				{Type: socketUnsubscribe, ID: "a"},
+			},
+			events: []events.Event{testSocketEvent(1, events.UserUpdated, 1, 10)},
+			want:   []change{{1, []string{"b"}}},
+		}, {
+			name:     "no subscription",
+			requests: nil,
+			events:   []events.Event{testSocketEvent(1, events.UserUpdated, 1, 10)},
+			want:     []change{},
+		},
+	}
+
+	// The sentinel subscription matches the last event, so once its change
+	// arrives every earlier event has been dealt with.
+	const sentinelUserID uint = 999
+	sentinel := testSocketEvent(1000, events.UserUpdated, sentinelUserID, 10)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := dialUserSocket(t, DefaultSocketConfig(), 16)
+
+			for _, req := range append([]socketRequest{subscribe("sentinel", []uint{sentinelUserID}, "")}, tc.requests...) {
+				client.send(req)
+				reply := client.receive()
+				require.Equal(t, req.ID, reply.ID)
+				require.NotEqual(t, socketError, reply.Type, reply.Error)
+			}
+
+			for _, event := range append(tc.events, sentinel) {
+				client.feed.broker.Publish(event)
+			}
+
+			got := []change{}
+			for {
+				msg := client.receive()
+				require.Equal(t, socketChange, msg.Type)
+				if msg.Event.ID == sentinel.ID {
+					break
+				}
+				got = append(got, change{msg.Event.ID, msg.Subscriptions})
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUserSocket_MaxSubscriptions(t *testing.T) {
+	cfg := DefaultSocketConfig()
+	cfg.MaxSubscriptions = 2
+	client := dialUserSocket(t, cfg, 16)
+
+	steps := []struct {
+		req  socketRequest
+		want socketMessage
+	}{
+		{socketRequest{Type: socketSubscribe, ID: "a"}, socketMessage{Type: socketSubscribed, ID: "a"}},
+		{socketRequest{Type: socketSubscribe, ID: "b"}, socketMessage{Type: socketSubscribed, ID: "b"}},
+		{socketRequest{Type: socketSubscribe, ID: "c"}, socketMessage{Type: socketError, ID: "c", Error: "at most 2 subscriptions per connection"}},
+		{socketRequest{Type: socketSubscribe, ID: "a", UserIDs: []uint{1}}, socketMessage{Type: socketSubscribed, ID: "a"}},
+		{socketRequest{Type: socketUnsubscribe, ID: "b"}, socketMessage{Type: socketUnsubscribed, ID: "b"}},
+		{socketRequest{Type: socketSubscribe, ID: "c"}, socketMessage{Type: socketSubscribed, ID: "c"}},
+	}
+
+	for _, step := range steps {
+		client.send(step.req)
+		require.Equal(t, step.want, client.receive())
+	}
+}
+
+func TestUserSocket_SlowClient(t *testing.T) {
+	cfg := DefaultSocketConfig()
+	cfg.SendQueue = 1
+	cfg.PingInterval = time.Hour
+	client := dialUserSocket(t, cfg, 1)
+
+	client.send(socketRequest{Type: socketSubscribe, ID: "all"})
+	require.Equal(t, socketMessage{Type: socketSubscribed, ID: "all"}, client.receive())
+
+	// The client stops reading: large changes fill the connection, then the
+	// send queue, and the session falls behind the feed.
+	subscription := client.subscription()
+	name := strings.Repeat("x", 16<<10)
+	var id uint64
+	require.Eventually(t, func() bool {
+		id++
+		client.feed.broker.Publish(events.Event{
+			ID:          id,
+			Type:        events.UserUpdated,
+			TenantID:    "default",
+			AggregateID: 1,
+			Payload:     json.RawMessage(fmt.Sprintf(`{"name":%q}`, name)),
+		})
+		return subscription.Lagged()
+	}, 5*time.Second, time.Millisecond)
+
+	require.Equal(t, websocket.StatusTryAgainLater, client.closeStatus())
+}
+
+func TestUserSocket_Ping(t *testing.T) {
+	cfg := DefaultSocketConfig()
+	cfg.PingInterval = 10 * time.Millisecond
+	cfg.WriteTimeout = 50 * time.Millisecond
+
+	t.Run("answered pings keep the connection open", func(t *testing.T) {
+		client := dialUserSocket(t, cfg, 16)
+
+		// Pongs are sent while the client reads.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*cfg.WriteTimeout)
+		defer cancel()
+		_, _, err := client.conn.Read(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.Equal(t, websocket.StatusCode(-1), websocket.CloseStatus(err))
+	})
+
+	t.Run("unanswered ping closes the connection", func(t *testing.T) {
+		client := dialUserSocket(t, cfg, 16)
+
+		time.Sleep(10 * cfg.WriteTimeout)
+
+		require.Equal(t, websocket.StatusInternalError, client.closeStatus())
+	})
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"crud_app/events"
+	"crud_app/service"
+)
+
+const (
+	streamReplayBatch int           = 500
+	streamRetry       time.Duration = 3 * time.Second
+)
+
+var streamHeartbeatInterval = 15 * time.Second
+
+// streamUserHandler serves user changes as Server-Sent Events. A client that
+// reconnects with Last-Event-ID first gets the changes it missed from the
+// change log, then follows the live feed.
+func streamUserHandler(userFeed service.UserFeed) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		eventTypes, err := parseEventTypes(r)
+		if err != nil {
+			writeResponseWithJson(w, Result{Error: err})
+			return
+		}
+		lastEventID, err := parseLastEventID(r)
+		if err != nil {
+			writeResponseWithJson(w, Result{Error: err})
+			return
+		}
+
+		// Subscribe before reading the backlog so nothing committed in
+		// between is lost; duplicates are skipped by sequence number.
+		subscription, err := userFeed.Subscribe(ctx, eventTypes)
+		if err != nil {
+			writeResponseWithJson(w, Result{Error: err})
+			return
+		}
+		defer subscription.Close()
+
+		var backlog []events.Event
+		if lastEventID > 0 {
+			backlog, err = userFeed.Changes(ctx, lastEventID, eventTypes, streamReplayBatch)
+			if err != nil {
+				writeResponseWithJson(w, Result{Error: err})
+				return
+			}
+		}
+
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+		for len(backlog) > 0 {
+			for _, event := range backlog {
+				if err := writeStreamEvent(w, event); err != nil {
+					return
+				}
+				lastEventID = event.ID
+			}
+			if len(backlog) < streamReplayBatch {
+				break
+			}
+
+			backlog, err = userFeed.Changes(ctx, lastEventID, eventTypes, streamReplayBatch)
+			if err != nil {
+				slog.ErrorContext(ctx, "user stream replay failed", slog.Any("error", err))
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-subscription.Events():
+				if !ok {
+					if subscription.Lagged() {
+						slog.WarnContext(ctx, "user stream dropped a slow client", slog.Uint64("last_event_id", lastEventID))
+					}
+					return
+				}
+				if event.ID <= lastEventID {
+					continue
+				}
+				if err := writeStreamEvent(w, event); err != nil {
+					return
+				}
+				lastEventID = event.ID
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+
+	return err
+}
+
+// parseLastEventID reads the Last-Event-ID header browsers send on reconnect,
+// or the last_event_id query parameter for a first connection that resumes.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: Last-Event-ID must be a non-negative integer", errInvalidRequest)
+	}
+
+	return id, nil
+}
+
+func parseEventTypes(r *http.Request) ([]string, error) {
+	v := r.URL.Query().Get("types")
+	if v == "" {
+		return nil, nil
+	}
+
+	var eventTypes []string
+	for _, eventType := range strings.Split(v, ",") {
+		eventType = strings.TrimSpace(eventType)
+		if !slices.Contains(events.UserEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q", errInvalidRequest, eventType)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	return eventTypes, nil
+}
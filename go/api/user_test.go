@@ -0,0 +1,437 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"crud_app/auth"
+	"crud_app/jobs"
+	"crud_app/service"
+)
+
+var errService = errors.New("database is down")
+
+var (
+	jsonBody     = map[string]string{"Content-Type": "application/json"}
+	acceptCSV    = map[string]string{"Accept": "text/csv"}
+	acceptNDJSON = map[string]string{"Accept": "application/x-ndjson"}
+	acceptXML    = map[string]string{"Accept": "application/xml"}
+	csvBody      = map[string]string{"Content-Type": "text/csv"}
+	ndjsonBody   = map[string]string{"Content-Type": "application/x-ndjson"}
+)
+
+func TestUserRoutes(t *testing.T) {
+	cases := []routeCase{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			target: "/users/list",
+		}, {
+			name:   "list not modified",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: map[string]string{"If-None-Match": `"stale", "JEe0L5c6AgDutJCkDa267sWY"`},
+		}, {
+			name:   "list etag of another format",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: map[string]string{"Accept": "application/x-ndjson", "If-None-Match": `"JEe0L5c6AgDutJCkDa267sWY"`},
+		}, {
+			name:   "list not modified since",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		}, {
+			name:   "list modified since",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 11:59:59 GMT"},
+		}, {
+			name:   "list stale etag wins over if modified since",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		}, {
+			name:   "list as of",
+			method: http.MethodGet,
+			target: "/users/list?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "list malformed as of",
+			method: http.MethodGet,
+			target: "/users/list?as_of=yesterday",
+		}, {
+			name:   "list page",
+			method: http.MethodGet,
+			target: "/users/list?limit=1&offset=1",
+		}, {
+			name:   "list filtered",
+			method: http.MethodGet,
+			target: "/users/list?filter.name=Jane",
+		}, {
+			name:   "list unknown filter",
+			method: http.MethodGet,
+			target: "/users/list?filter.nickname=Johnny",
+		}, {
+			name:   "list repeated filter",
+			method: http.MethodGet,
+			target: "/users/list?filter.name=Jane&filter.name=John",
+		}, {
+			name:   "list ignores other parameters",
+			method: http.MethodGet,
+			target: "/users/list?name=Jane&_=1760000000",
+		}, {
+			name:   "list malformed limit",
+			method: http.MethodGet,
+			target: "/users/list?limit=0",
+		}, {
+			name:   "list as of with filter",
+			method: http.MethodGet,
+			target: "/users/list?as_of=2025-09-17T12:00:00Z&filter.name=John",
+		}, {
+			name:   "list csv",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: acceptCSV,
+		}, {
+			name:   "list ndjson",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: acceptNDJSON,
+		}, {
+			name:   "list not acceptable",
+			method: http.MethodGet,
+			target: "/users/list",
+			header: acceptXML,
+		}, {
+			name:   "list forbidden",
+			method: http.MethodGet,
+			target: "/users/list",
+			setup:  func(f *fakes) { f.users.err = auth.ErrForbidden },
+		}, {
+			name:   "list service error",
+			method: http.MethodGet,
+			target: "/users/list",
+			setup:  func(f *fakes) { f.users.err = errService },
+		}, {
+			name:   "export",
+			method: http.MethodGet,
+			target: "/users/export",
+		}, {
+			name:   "export csv",
+			method: http.MethodGet,
+			target: "/users/export",
+			header: acceptCSV,
+		}, {
+			name:   "export ndjson",
+			method: http.MethodGet,
+			target: "/users/export",
+			header: acceptNDJSON,
+		}, {
+			name:   "export not acceptable",
+			method: http.MethodGet,
+			target: "/users/export",
+			header: map[string]string{"Accept": "application/msgpack"},
+		}, {
+			name:   "export service error",
+			method: http.MethodGet,
+			target: "/users/export",
+			setup:  func(f *fakes) { f.users.err = errService },
+		}, {
+			name:   "stream",
+			method: http.MethodGet,
+			target: "/users/stream",
+		}, {
+			name:   "stream resumes after last event id",
+			method: http.MethodGet,
+			target: "/users/stream",
+			header: map[string]string{"Last-Event-ID": "1"},
+		}, {
+			name:   "stream filtered by type",
+			method: http.MethodGet,
+			target: "/users/stream?types=UserDeleted&last_event_id=1",
+		}, {
+			name:   "stream unknown type",
+			method: http.MethodGet,
+			target: "/users/stream?types=UserRenamed",
+		}, {
+			name:   "stream malformed last event id",
+			method: http.MethodGet,
+			target: "/users/stream",
+			header: map[string]string{"Last-Event-ID": "abc"},
+		}, {
+			name:   "stream service error",
+			method: http.MethodGet,
+			target: "/users/stream",
+			setup:  func(f *fakes) { f.feed.err = errService },
+		}, {
+			name:   "socket without upgrade",
+			method: http.MethodGet,
+			target: "/ws/users",
+		}, {
+			name:   "socket service error",
+			method: http.MethodGet,
+			target: "/ws/users",
+			setup:  func(f *fakes) { f.feed.err = errService },
+		}, {
+			name:   "get",
+			method: http.MethodGet,
+			target: "/users/1",
+		}, {
+			name:   "get not modified",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"If-None-Match": `W/"_jLEQbTp0_aW_GdfXUm1dtOX"`},
+		}, {
+			name:   "get not modified since",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		}, {
+			name:   "get modified since",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"If-Modified-Since": "Wed, 17 Sep 2025 11:59:59 GMT"},
+		}, {
+			name:   "get stale etag wins over if modified since",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": "Wed, 17 Sep 2025 12:00:00 GMT"},
+		}, {
+			name:   "get any etag",
+			method: http.MethodGet,
+			target: "/users/1",
+			header: map[string]string{"If-None-Match": "*"},
+		}, {
+			name:   "get not found with etag",
+			method: http.MethodGet,
+			target: "/users/42",
+			header: map[string]string{"If-None-Match": "*"},
+		}, {
+			name:   "get as of",
+			method: http.MethodGet,
+			target: "/users/1?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "get deleted",
+			method: http.MethodGet,
+			target: "/users/3",
+		}, {
+			name:   "get not found",
+			method: http.MethodGet,
+			target: "/users/42",
+		}, {
+			name:   "get malformed id",
+			method: http.MethodGet,
+			target: "/users/abc",
+		}, {
+			name:   "get negative id",
+			method: http.MethodGet,
+			target: "/users/-1",
+		}, {
+			name:   "create",
+			method: http.MethodPost,
+			target: "/users/create",
+			header: jsonBody,
+			body:   `{"Name":"Jill","Age":13}`,
+		}, {
+			name:   "create ndjson",
+			method: http.MethodPost,
+			target: "/users/create",
+			header: ndjsonBody,
+			body:   `{"Name":"Jill","Age":13}`,
+		}, {
+			name:   "create malformed json",
+			method: http.MethodPost,
+			target: "/users/create",
+			header: jsonBody,
+			body:   `{"Name":"Jill",`,
+		}, {
+			name:   "create wrong field type",
+			method: http.MethodPost,
+			target: "/users/create",
+			header: jsonBody,
+			body:   `{"Name":"Jill","Age":"thirteen"}`,
+		}, {
+			name:   "create unsupported media type",
+			method: http.MethodPost,
+			target: "/users/create",
+			header: map[string]string{"Content-Type": "text/plain"},
+			body:   "Jill, 13",
+		}, {
+			name:   "create service error",
+			method: http.MethodPost,
+			target: "/users/create",
+			header: jsonBody,
+			body:   `{"Name":"Jill","Age":13}`,
+			setup:  func(f *fakes) { f.users.err = errService },
+		}, {
+			name:   "create wrong method",
+			method: http.MethodGet,
+			target: "/users/create",
+		}, {
+			name:   "import csv",
+			method: http.MethodPost,
+			target: "/users/import",
+			header: csvBody,
+			body:   "name,age\nJill,13\n",
+		}, {
+			name:   "import ndjson dry run",
+			method: http.MethodPost,
+			target: "/users/import?dry_run=true",
+			header: ndjsonBody,
+			body:   "{\"name\":\"Jill\",\"age\":13}\n",
+		}, {
+			name:   "import unsupported media type",
+			method: http.MethodPost,
+			target: "/users/import",
+			header: jsonBody,
+			body:   `[{"name":"Jill","age":13}]`,
+		}, {
+			name:   "import malformed dry run",
+			method: http.MethodPost,
+			target: "/users/import?dry_run=maybe",
+			header: csvBody,
+			body:   "name,age\nJill,13\n",
+		}, {
+			name:   "import queue full",
+			method: http.MethodPost,
+			target: "/users/import",
+			header: csvBody,
+			body:   "name,age\nJill,13\n",
+			setup:  func(f *fakes) { f.imports.err = jobs.ErrQueueFull },
+		}, {
+			name:   "import too large",
+			method: http.MethodPost,
+			target: "/users/import",
+			header: csvBody,
+			body:   "name,age\nJill,13\n",
+			setup: func(f *fakes) {
+				f.imports.err = fmt.Errorf("%w: the limit is 14 bytes", service.ErrUploadTooLarge)
+			},
+		}, {
+			name:   "update",
+			method: http.MethodPut,
+			target: "/users/update/1",
+			header: jsonBody,
+			body:   `{"Name":"Johnny"}`,
+		}, {
+			name:   "update forbidden",
+			method: http.MethodPut,
+			target: "/users/update/1",
+			header: jsonBody,
+			body:   `{"Name":"Johnny"}`,
+			setup:  func(f *fakes) { f.users.err = auth.ErrForbidden },
+		}, {
+			name:   "update not found",
+			method: http.MethodPut,
+			target: "/users/update/42",
+			header: jsonBody,
+			body:   `{"Name":"Johnny"}`,
+		}, {
+			name:   "update malformed id",
+			method: http.MethodPut,
+			target: "/users/update/abc",
+			header: jsonBody,
+			body:   `{"Name":"Johnny"}`,
+		}, {
+			name:   "update malformed json",
+			method: http.MethodPut,
+			target: "/users/update/1",
+			header: jsonBody,
+			body:   `Johnny`,
+		}, {
+			name:   "delete",
+			method: http.MethodDelete,
+			target: "/users/delete/1",
+		}, {
+			name:   "delete forbidden",
+			method: http.MethodDelete,
+			target: "/users/delete/1",
+			setup:  func(f *fakes) { f.users.err = auth.ErrForbidden },
+		}, {
+			name:   "delete not found",
+			method: http.MethodDelete,
+			target: "/users/delete/42",
+		}, {
+			name:   "delete malformed id",
+			method: http.MethodDelete,
+			target: "/users/delete/abc",
+		}, {
+			name:   "restore",
+			method: http.MethodPost,
+			target: "/users/restore/3",
+		}, {
+			name:   "restore user that is not deleted",
+			method: http.MethodPost,
+			target: "/users/restore/1",
+		}, {
+			name:   "restore malformed id",
+			method: http.MethodPost,
+			target: "/users/restore/abc",
+		}, {
+			name:   "history",
+			method: http.MethodGet,
+			target: "/users/1/history",
+		}, {
+			name:   "history page",
+			method: http.MethodGet,
+			target: "/users/1/history?limit=1&offset=1",
+		}, {
+			name:   "history malformed limit",
+			method: http.MethodGet,
+			target: "/users/1/history?limit=1000",
+		}, {
+			name:   "history malformed offset",
+			method: http.MethodGet,
+			target: "/users/1/history?offset=-1",
+		}, {
+			name:   "history malformed id",
+			method: http.MethodGet,
+			target: "/users/abc/history",
+		}, {
+			name:   "revert",
+			method: http.MethodPost,
+			target: "/users/revert/1?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "revert without as of",
+			method: http.MethodPost,
+			target: "/users/revert/1",
+		}, {
+			name:   "revert malformed as of",
+			method: http.MethodPost,
+			target: "/users/revert/1?as_of=yesterday",
+		}, {
+			name:   "revert not found",
+			method: http.MethodPost,
+			target: "/users/revert/42?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "revert malformed id",
+			method: http.MethodPost,
+			target: "/users/revert/abc?as_of=2025-09-17T12:00:00Z",
+		}, {
+			name:   "purge",
+			method: http.MethodDelete,
+			target: "/users/purge/3",
+		}, {
+			name:   "purge forbidden",
+			method: http.MethodDelete,
+			target: "/users/purge/1",
+			setup:  func(f *fakes) { f.users.err = auth.ErrForbidden },
+		}, {
+			name:   "purge not found",
+			method: http.MethodDelete,
+			target: "/users/purge/42",
+		}, {
+			name:   "purge malformed id",
+			method: http.MethodDelete,
+			target: "/users/purge/abc",
+		}, {
+			name:   "unknown route",
+			method: http.MethodGet,
+			target: "/users/1/friends",
+		},
+	}
+
+	runRouteCases(t, cases)
+}
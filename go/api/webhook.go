@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"crud_app/dto"
+	"crud_app/service"
+)
+
+func SetWebhookHandlers(router chi.Router, webhookService service.Webhook) {
+	webhookRouter := chi.NewRouter()
+
+	webhookRouter.Get("/list", listWebhookHandler(webhookService))
+
+	webhookRouter.Get("/{id}", getWebhookHandler(webhookService))
+
+	webhookRouter.Post("/create", createWebhookHandler(webhookService))
+
+	webhookRouter.Put("/update/{id}", updateWebhookHandler(webhookService))
+
+	webhookRouter.Delete("/delete/{id}", deleteWebhookHandler(webhookService))
+
+	webhookRouter.Get("/{id}/deliveries", webhookDeliveriesHandler(webhookService))
+
+	router.Mount("/webhooks", webhookRouter)
+}
+
+func listWebhookHandler(webhookService service.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var result Result
+
+		result.Data, result.Error = webhookService.List(ctx)
+
+		writeResponseWithJson(w, result)
+	}
+}
+
+func getWebhookHandler(webhookService service.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else {
+			result.Data, result.Error = webhookService.Get(ctx, uint(uuid))
+		}
+
+		writeResponseWithJson(w, result)
+	}
+}
+
+func createWebhookHandler(webhookService service.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var subscription dto.WebhookSubscription
+		var result Result
+
+		if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+			result.Error = fmt.Errorf("%w: invalid JSON format", errInvalidRequest)
+		} else {
+			result.Data, result.Error = webhookService.Create(ctx, &subscription)
+		}
+
+		writeResponseWithJson(w, result)
+	}
+}
+
+func updateWebhookHandler(webhookService service.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var subscription dto.WebhookSubscription
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+			result.Error = fmt.Errorf("%w: invalid JSON format", errInvalidRequest)
+		} else {
+			result.Error = webhookService.Update(ctx, &subscription, uint(uuid))
+		}
+
+		writeActionResponse(w, r, result)
+	}
+}
+
+func deleteWebhookHandler(webhookService service.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else {
+			result.Error = webhookService.Delete(ctx, uint(uuid))
+		}
+
+		writeActionResponse(w, r, result)
+	}
+}
+
+func webhookDeliveriesHandler(webhookService service.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := chi.URLParam(r, "id")
+		uuid, err := strconv.ParseUint(id, 10, 64)
+
+		var result Result
+
+		if err != nil {
+			result.Error = errInvalidID
+		} else if page, err := parsePageRequest(r); err != nil {
+			result.Error = err
+		} else {
+			result.Data, result.Error = webhookService.Deliveries(ctx, uint(uuid), page)
+		}
+
+		writeResponseWithJson(w, result)
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWebhookRoutes(t *testing.T) {
+	cases := []routeCase{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			target: "/webhooks/list",
+		}, {
+			name:   "list service error",
+			method: http.MethodGet,
+			target: "/webhooks/list",
+			setup:  func(f *fakes) { f.webhooks.err = errService },
+		}, {
+			name:   "get",
+			method: http.MethodGet,
+			target: "/webhooks/1",
+		}, {
+			name:   "get not found",
+			method: http.MethodGet,
+			target: "/webhooks/42",
+		}, {
+			name:   "get malformed id",
+			method: http.MethodGet,
+			target: "/webhooks/abc",
+		}, {
+			name:   "create",
+			method: http.MethodPost,
+			target: "/webhooks/create",
+			header: jsonBody,
+			body:   `{"url":"https://example.com/other","event_types":["UserDeleted"],"secret":"s3cret"}`,
+		}, {
+			name:   "create malformed json",
+			method: http.MethodPost,
+			target: "/webhooks/create",
+			header: jsonBody,
+			body:   `{"url":`,
+		}, {
+			name:   "update",
+			method: http.MethodPut,
+			target: "/webhooks/update/1",
+			header: jsonBody,
+			body:   `{"url":"https://example.com/hook","event_types":[],"active":false}`,
+		}, {
+			name:   "update not found",
+			method: http.MethodPut,
+			target: "/webhooks/update/42",
+			header: jsonBody,
+			body:   `{"url":"https://example.com/hook"}`,
+		}, {
+			name:   "update malformed id",
+			method: http.MethodPut,
+			target: "/webhooks/update/abc",
+			header: jsonBody,
+			body:   `{"url":"https://example.com/hook"}`,
+		}, {
+			name:   "update malformed json",
+			method: http.MethodPut,
+			target: "/webhooks/update/1",
+			header: jsonBody,
+			body:   `[]`,
+		}, {
+			name:   "delete",
+			method: http.MethodDelete,
+			target: "/webhooks/delete/1",
+		}, {
+			name:   "delete not found",
+			method: http.MethodDelete,
+			target: "/webhooks/delete/42",
+		}, {
+			name:   "delete malformed id",
+			method: http.MethodDelete,
+			target: "/webhooks/delete/abc",
+		}, {
+			name:   "deliveries",
+			method: http.MethodGet,
+			target: "/webhooks/1/deliveries?limit=10",
+		}, {
+			name:   "deliveries malformed limit",
+			method: http.MethodGet,
+			target: "/webhooks/1/deliveries?limit=abc",
+		}, {
+			name:   "deliveries not found",
+			method: http.MethodGet,
+			target: "/webhooks/42/deliveries",
+		}, {
+			name:   "deliveries malformed id",
+			method: http.MethodGet,
+			target: "/webhooks/abc/deliveries",
+		},
+	}
+
+	runRouteCases(t, cases)
+}
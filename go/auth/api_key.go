@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+const apiKeyPrefix string = "ck_"
+
+type APIKeys struct {
+	repo repository.APIKeyRepo
+}
+
+func NewAPIKeys(repo repository.APIKeyRepo) *APIKeys {
+	return &APIKeys{repo: repo}
+}
+
+// Mint stores a new key and returns its plaintext, which is never persisted
+// and cannot be recovered later.
+func (k *APIKeys) Mint(ctx context.Context, name string, roles []string, tenantID string) (string, *dto.APIKey, error) {
+	prefix := randomHex(4)
+	plaintext := apiKeyPrefix + prefix + "_" + randomHex(24)
+
+	key := &dto.APIKey{
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: hashAPIKey(plaintext),
+		Roles:   strings.Join(roles, ","),
+	}
+	if tenantID != "" {
+		key.TenantID = &tenantID
+	}
+
+	key, err := k.repo.Create(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return plaintext, key, nil
+}
+
+func (k *APIKeys) Revoke(ctx context.Context, id uint) (bool, error) {
+	return k.repo.Revoke(ctx, id)
+}
+
+func (k *APIKeys) List(ctx context.Context) ([]dto.APIKey, error) {
+	return k.repo.List(ctx)
+}
+
+func (k *APIKeys) Verify(ctx context.Context, plaintext string) (*Principal, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	key, err := k.repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(plaintext)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrUnauthenticated
+	}
+
+	principal := &Principal{
+		Subject: "api_key:" + key.Name,
+		Method:  MethodAPIKey,
+		Roles:   splitRoles(key.Roles),
+	}
+	if key.TenantID != nil {
+		principal.TenantID = *key.TenantID
+	}
+
+	return principal, nil
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func splitRoles(roles string) []string {
+	var result []string
+	for _, role := range strings.Split(roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			result = append(result, role)
+		}
+	}
+
+	return result
+}
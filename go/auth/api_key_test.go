@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	mock_repository "crud_app/repository/mocks_repository"
+)
+
+func TestAPIKeys_MintAndVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAPIKeyRepo(ctrl)
+
+	var stored *dto.APIKey
+	mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+			key.ID = 1
+			stored = key
+			return key, nil
+		})
+
+	apiKeys := NewAPIKeys(mockRepo)
+	plaintext, key, err := apiKeys.Mint(context.Background(), "reporting", []string{"reader", "auditor"}, "acme")
+	require.NoError(t, err)
+	require.True(t, IsAPIKey(plaintext))
+	require.NotContains(t, key.KeyHash, plaintext)
+	require.True(t, strings.HasPrefix(plaintext, apiKeyPrefix+key.Prefix+"_"))
+
+	mockRepo.EXPECT().
+		GetByPrefix(gomock.Any(), stored.Prefix).
+		Return(stored, nil).
+		Times(2)
+
+	principal, err := apiKeys.Verify(context.Background(), plaintext)
+	require.NoError(t, err)
+	require.Equal(t, "api_key:reporting", principal.Subject)
+	require.Equal(t, []string{"reader", "auditor"}, principal.Roles)
+	require.Equal(t, "acme", principal.TenantID)
+
+	_, err = apiKeys.Verify(context.Background(), plaintext+"x")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestAPIKeys_Verify(t *testing.T) {
+	type testCase struct {
+		name       string
+		key        string
+		setupMocks func(*mock_repository.MockAPIKeyRepo)
+	}
+
+	cases := []testCase{
+		{
+			name:       "not an api key",
+			key:        "eyJhbGciOi",
+			setupMocks: func(mr *mock_repository.MockAPIKeyRepo) {},
+		}, {
+			name:       "missing secret",
+			key:        "ck_abcd1234",
+			setupMocks: func(mr *mock_repository.MockAPIKeyRepo) {},
+		}, {
+			name: "unknown or revoked prefix",
+			key:  "ck_abcd1234_secret",
+			setupMocks: func(mr *mock_repository.MockAPIKeyRepo) {
+				mr.EXPECT().
+					GetByPrefix(gomock.Any(), "abcd1234").
+					Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockAPIKeyRepo(ctrl)
+			tc.setupMocks(mockRepo)
+
+			principal, err := NewAPIKeys(mockRepo).Verify(context.Background(), tc.key)
+
+			require.ErrorIs(t, err, ErrUnauthenticated)
+			require.Nil(t, principal)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	authorizationHeader string = "Authorization"
+	apiKeyHeader        string = "X-API-Key"
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, r *http.Request) (*Principal, error)
+}
+
+type authenticator struct {
+	jwt     *JWTVerifier
+	apiKeys *APIKeys
+}
+
+// NewAuthenticator accepts bearer JWTs when jwt is set and API keys either as
+// bearer tokens or in the X-API-Key header when apiKeys is set.
+func NewAuthenticator(jwt *JWTVerifier, apiKeys *APIKeys) Authenticator {
+	return &authenticator{jwt: jwt, apiKeys: apiKeys}
+}
+
+func (a *authenticator) Authenticate(ctx context.Context, r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return a.authenticateAPIKey(ctx, key)
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get(authorizationHeader), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	if IsAPIKey(token) {
+		return a.authenticateAPIKey(ctx, token)
+	}
+
+	if a.jwt == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	return a.jwt.Verify(token)
+}
+
+func (a *authenticator) authenticateAPIKey(ctx context.Context, key string) (*Principal, error) {
+	if a.apiKeys == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	return a.apiKeys.Verify(ctx, key)
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+var hmacSecret = []byte("test-secret")
+
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacSecret)
+	require.NoError(t, err)
+
+	return token
+}
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) string {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	type testCase struct {
+		name          string
+		claims        jwt.MapClaims
+		expectedRoles []string
+		wantError     bool
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	cases := []testCase{
+		{
+			name:          "valid token",
+			claims:        jwt.MapClaims{"sub": "alice", "iss": "issuer", "aud": "crud_app", "exp": exp, "roles": []string{"reader"}},
+			expectedRoles: []string{"reader"},
+		}, {
+			name:      "wrong issuer",
+			claims:    jwt.MapClaims{"sub": "alice", "iss": "other", "aud": "crud_app", "exp": exp},
+			wantError: true,
+		}, {
+			name:      "wrong audience",
+			claims:    jwt.MapClaims{"sub": "alice", "iss": "issuer", "aud": "other", "exp": exp},
+			wantError: true,
+		}, {
+			name:      "expired",
+			claims:    jwt.MapClaims{"sub": "alice", "iss": "issuer", "aud": "crud_app", "exp": time.Now().Add(-time.Hour).Unix()},
+			wantError: true,
+		}, {
+			name:      "missing expiry",
+			claims:    jwt.MapClaims{"sub": "alice", "iss": "issuer", "aud": "crud_app"},
+			wantError: true,
+		}, {
+			name:      "missing subject",
+			claims:    jwt.MapClaims{"iss": "issuer", "aud": "crud_app", "exp": exp},
+			wantError: true,
+		},
+	}
+
+	verifier, err := NewJWTVerifier(JWTConfig{HMACSecret: hmacSecret, Issuer: "issuer", Audience: "crud_app"})
+	require.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := verifier.Verify(signHS256(t, tc.claims))
+
+			if tc.wantError {
+				require.ErrorIs(t, err, ErrUnauthenticated)
+				require.Nil(t, principal)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "alice", principal.Subject)
+				require.Equal(t, MethodJWT, principal.Method)
+				require.Equal(t, tc.expectedRoles, principal.Roles)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	verifier, err := NewJWTVerifier(JWTConfig{JWKSFile: writeJWKS(t, "key-1", &key.PublicKey)})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "bob", "exp": time.Now().Add(time.Hour).Unix()}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(signed)
+	require.NoError(t, err)
+	require.Equal(t, "bob", principal.Subject)
+
+	token.Header["kid"] = "key-2"
+	signed, err = token.SignedString(key)
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(signed)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+
+	_, err = verifier.Verify(signHS256(t, claims))
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
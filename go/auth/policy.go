@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PermissionUsersRead     string = "users:read"
+	PermissionUsersWrite    string = "users:write"
+	PermissionUsersDelete   string = "users:delete"
+	PermissionUsersPurge    string = "users:purge"
+	PermissionWebhooksRead  string = "webhooks:read"
+	PermissionWebhooksWrite string = "webhooks:write"
+	// PermissionTenantsAll lets a principal that is not bound to a tenant
+	// act on the one it names.
+	PermissionTenantsAll string = "tenants:all"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+type Policy struct {
+	Roles map[string][]string `yaml:"roles"`
+}
+
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	if len(policy.Roles) == 0 {
+		return nil, errors.New("invalid policy: no roles defined")
+	}
+
+	return &policy, nil
+}
+
+func (p *Policy) Allowed(principal *Principal, permission string) bool {
+	if principal == nil {
+		return false
+	}
+
+	for _, role := range principal.Roles {
+		if slices.Contains(p.Roles[role], permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Policy) Authorize(principal *Principal, permission string) error {
+	if principal == nil {
+		return ErrUnauthenticated
+	}
+
+	if !p.Allowed(principal, permission) {
+		return fmt.Errorf("%w: missing permission %s", ErrForbidden, permission)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"crud_app/tenant"
+)
+
+const (
+	MethodJWT    string = "jwt"
+	MethodAPIKey string = "api_key"
+)
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Principal is bound to TenantID when set; principals without a tenant may
+// act on any tenant chosen by the caller, but only if the policy grants them
+// PermissionTenantsAll.
+type Principal struct {
+	Subject  string
+	Method   string
+	Roles    []string
+	TenantID string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
+
+type ctxKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal)
+}
+
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(ctxKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// ResolveTenant picks the tenant a request acts on: the principal's own tenant,
+// which requested may only repeat, or else the requested one. A principal that
+// is not bound to a tenant, such as a token without a tenant claim, is refused
+// unless policy grants it PermissionTenantsAll.
+func ResolveTenant(policy *Policy, principal *Principal, requested string) (string, error) {
+	if principal != nil && principal.TenantID != "" {
+		if requested != "" && requested != principal.TenantID {
+			return "", fmt.Errorf("%w: tenant %q is not accessible", ErrForbidden, requested)
+		}
+		return principal.TenantID, nil
+	}
+
+	if err := policy.Authorize(principal, PermissionTenantsAll); err != nil {
+		return "", err
+	}
+
+	switch {
+	case requested == "":
+		return "", tenant.ErrRequired
+	case !tenant.Valid(requested):
+		return "", tenant.ErrInvalid
+	default:
+		return requested, nil
+	}
+}
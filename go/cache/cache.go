@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Backend stores encoded values by key. The in-process LRU is the default;
+// a shared store such as Redis can implement it to share one cache between
+// instances.
+type Backend interface {
+	// Get reports whether key is present and not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl, or until evicted when ttl is 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	type testCase struct {
+		name     string
+		size     int
+		run      func(t *testing.T, c *lru, clock *fakeClock)
+		wantKeys []string
+		wantGone []string
+	}
+
+	cases := []testCase{{
+		name: "get returns what set stored",
+		size: 2,
+		run: func(t *testing.T, c *lru, clock *fakeClock) {
+			require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+			require.NoError(t, c.Set(ctx, "a", []byte("2"), 0))
+		},
+		wantKeys: []string{"a"},
+	}, {
+		name: "evicts the least recently used",
+		size: 2,
+		run: func(t *testing.T, c *lru, clock *fakeClock) {
+			require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+			require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+			_, _, err := c.Get(ctx, "a")
+			require.NoError(t, err)
+			require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+		},
+		wantKeys: []string{"a", "c"},
+		wantGone: []string{"b"},
+	}, {
+		name: "expires after ttl",
+		size: 2,
+		run: func(t *testing.T, c *lru, clock *fakeClock) {
+			require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+			require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Hour))
+			clock.now = clock.now.Add(time.Minute)
+		},
+		wantKeys: []string{"b"},
+		wantGone: []string{"a"},
+	}, {
+		name: "set renews ttl",
+		size: 2,
+		run: func(t *testing.T, c *lru, clock *fakeClock) {
+			require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+			clock.now = clock.now.Add(30 * time.Second)
+			require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+			clock.now = clock.now.Add(30 * time.Second)
+		},
+		wantKeys: []string{"a"},
+	}, {
+		name: "delete removes keys",
+		size: 3,
+		run: func(t *testing.T, c *lru, clock *fakeClock) {
+			require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+			require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+			require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+			require.NoError(t, c.Delete(ctx, "a", "c", "missing"))
+		},
+		wantKeys: []string{"b"},
+		wantGone: []string{"a", "c"},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)}
+			c := newLRU(tc.size, clock.Now)
+
+			tc.run(t, c, clock)
+
+			for _, key := range tc.wantKeys {
+				_, ok, err := c.Get(ctx, key)
+				require.NoError(t, err)
+				require.True(t, ok, key)
+			}
+			for _, key := range tc.wantGone {
+				_, ok, err := c.Get(ctx, key)
+				require.NoError(t, err)
+				require.False(t, ok, key)
+			}
+			require.Equal(t, len(tc.wantKeys), c.Len())
+		})
+	}
+}
+
+func TestLRU_GetReturnsLatestValue(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(1)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "a", []byte("2"), 0))
+
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("2"), value)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"crud_app/auth"
+	"crud_app/config"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+const usage string = `usage: admin <command> [flags]
+
+commands:
+  apikey-create -name NAME [-roles ROLE,ROLE] [-tenant ID]
+                                                mint a new API key, bound to
+                                                a tenant unless -tenant is empty
+  apikey-revoke -id ID                          revoke an API key
+  apikey-list                                   list API keys
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	db, err := config.ConnectDB()
+	if err != nil {
+		fatal("database connection failed", err)
+	}
+
+	apiKeys := auth.NewAPIKeys(repository.NewAPIKeyRepo(db))
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "apikey-create":
+		err = createAPIKey(ctx, apiKeys, os.Args[2:])
+	case "apikey-revoke":
+		err = revokeAPIKey(ctx, apiKeys, os.Args[2:])
+	case "apikey-list":
+		err = listAPIKeys(ctx, apiKeys)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fatal(os.Args[1]+" failed", err)
+	}
+}
+
+func createAPIKey(ctx context.Context, apiKeys *auth.APIKeys, args []string) error {
+	fs := flag.NewFlagSet("apikey-create", flag.ExitOnError)
+	name := fs.String("name", "", "key owner or purpose")
+	roles := fs.String("roles", "", "comma-separated roles")
+	tenantID := fs.String("tenant", "", "tenant the key is bound to")
+	fs.Parse(args)
+
+	if strings.TrimSpace(*name) == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if *tenantID != "" && !tenant.Valid(*tenantID) {
+		return tenant.ErrInvalid
+	}
+
+	plaintext, key, err := apiKeys.Mint(ctx, *name, strings.Split(*roles, ","), *tenantID)
+	if err != nil {
+		return err
+	}
+
+	keyTenant := "-"
+	if key.TenantID != nil {
+		keyTenant = *key.TenantID
+	}
+
+	fmt.Printf("id:     %d\nname:   %s\nroles:  %s\ntenant: %s\nkey:    %s\n\nStore the key now, it cannot be shown again.\n",
+		key.ID, key.Name, key.Roles, keyTenant, plaintext)
+	return nil
+}
+
+func revokeAPIKey(ctx context.Context, apiKeys *auth.APIKeys, args []string) error {
+	fs := flag.NewFlagSet("apikey-revoke", flag.ExitOnError)
+	id := fs.String("id", "", "key id")
+	fs.Parse(args)
+
+	keyID, err := strconv.ParseUint(*id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("-id must be a positive integer")
+	}
+
+	revoked, err := apiKeys.Revoke(ctx, uint(keyID))
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fmt.Errorf("active API key with ID %d not found", keyID)
+	}
+
+	fmt.Printf("API key %d revoked\n", keyID)
+	return nil
+}
+
+func listAPIKeys(ctx context.Context, apiKeys *auth.APIKeys) error {
+	keys, err := apiKeys.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tROLES\tTENANT\tCREATED\tREVOKED")
+	for _, key := range keys {
+		keyTenant := "-"
+		if key.TenantID != nil {
+			keyTenant = *key.TenantID
+		}
+		revoked := "-"
+		if key.RevokedAt != nil {
+			revoked = key.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix, key.Roles, keyTenant, key.CreatedAt.Format(time.RFC3339), revoked)
+	}
+
+	return w.Flush()
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
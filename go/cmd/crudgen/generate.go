@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.ParseFS(templateFiles, "templates/*.tmpl"))
+
+// migrationVersionLayout matches the versions of the hand-written
+// migrations.
+const migrationVersionLayout string = "20060102150405"
+
+const (
+	statusCreated   string = "created"
+	statusUpdated   string = "updated"
+	statusUnchanged string = "unchanged"
+	statusGenerated string = "generated by mockgen"
+	statusSkipped   string = "skipped, it has local changes (use -force to overwrite)"
+)
+
+// output is one file crudgen writes, relative to the module root.
+type output struct {
+	path     string
+	template string
+	sqlite   bool
+}
+
+type result struct {
+	path   string
+	status string
+}
+
+// templateData is what every template sees: the resource, the module path
+// and, for migrations, the dialect.
+type templateData struct {
+	*Resource
+	Module string
+	SQLite bool
+}
+
+type generator struct {
+	root  string
+	force bool
+	now   func() time.Time
+}
+
+// generate renders every file of resource under g.root. Files that would
+// not change are left alone, and a migration that already exists keeps its
+// version, so a second run with the same definition writes nothing.
+func (g *generator) generate(resource *Resource) ([]result, error) {
+	module, err := modulePath(g.root)
+	if err != nil {
+		return nil, err
+	}
+	version, err := g.migrationVersion(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	file := resource.File()
+	migration := fmt.Sprintf("%s_create_%s_table.sql", version, resource.Table())
+	outputs := []output{
+		{path: filepath.Join("dto", file+".go"), template: "dto.go.tmpl"},
+		{path: filepath.Join("migrations", migration), template: "migration.sql.tmpl"},
+		{path: filepath.Join("migrations", "sqlite", migration), template: "migration.sql.tmpl", sqlite: true},
+		{path: filepath.Join("repository", file+".go"), template: "repository.go.tmpl"},
+		{path: filepath.Join("repository", file+"_test.go"), template: "repository_test.go.tmpl"},
+		{path: filepath.Join("service", file+".go"), template: "service.go.tmpl"},
+		{path: filepath.Join("service", file+"_test.go"), template: "service_test.go.tmpl"},
+		{path: filepath.Join("service", file+"_authorization.go"), template: "authorization.go.tmpl"},
+		{path: filepath.Join("service", file+"_authorization_test.go"), template: "authorization_test.go.tmpl"},
+		{path: filepath.Join("service", file+"_validator.go"), template: "validator.go.tmpl"},
+		{path: filepath.Join("service", file+"_validator_test.go"), template: "validator_test.go.tmpl"},
+		{path: filepath.Join("api", file+".go"), template: "api.go.tmpl"},
+		{path: filepath.Join("api", file+"_test.go"), template: "api_test.go.tmpl"},
+	}
+
+	results := make([]result, 0, len(outputs))
+	for _, out := range outputs {
+		content, err := render(out, templateData{Resource: resource, Module: module, SQLite: out.sqlite})
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := g.write(out.path, content)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result{path: out.path, status: status})
+	}
+
+	return results, nil
+}
+
+func render(out output, data templateData) ([]byte, error) {
+	var b bytes.Buffer
+	if err := templates.ExecuteTemplate(&b, out.template, data); err != nil {
+		return nil, fmt.Errorf("render %s: %w", out.path, err)
+	}
+	if filepath.Ext(out.path) != ".go" {
+		return b.Bytes(), nil
+	}
+
+	content, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", out.path, err)
+	}
+
+	return content, nil
+}
+
+func (g *generator) write(path string, content []byte) (string, error) {
+	full := filepath.Join(g.root, path)
+
+	existing, err := os.ReadFile(full)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return "", err
+		}
+		return statusCreated, os.WriteFile(full, content, 0o644)
+	case err != nil:
+		return "", err
+	case bytes.Equal(existing, content):
+		return statusUnchanged, nil
+	case !g.force:
+		return statusSkipped, nil
+	default:
+		return statusUpdated, os.WriteFile(full, content, 0o644)
+	}
+}
+
+// migrationVersion reuses the version of the resource's migration when one
+// exists and takes the current time otherwise.
+func (g *generator) migrationVersion(resource *Resource) (string, error) {
+	pattern := filepath.Join(g.root, "migrations", fmt.Sprintf("*_create_%s_table.sql", resource.Table()))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(matches) {
+	case 0:
+		return g.now().UTC().Format(migrationVersionLayout), nil
+	case 1:
+		version, _, _ := strings.Cut(filepath.Base(matches[0]), "_")
+		return version, nil
+	default:
+		return "", fmt.Errorf("more than one migration creates %s: %s", resource.Table(), strings.Join(matches, ", "))
+	}
+}
+
+// modulePath reads the module path from the go.mod in root, so generated
+// imports match the module they land in.
+func modulePath(root string) (string, error) {
+	f, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("%s is not a module root: %w", root, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no module directive in %s", f.Name())
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRoot(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.25.0\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "migrations", "sqlite"), 0o755))
+
+	return root
+}
+
+func statuses(results []result) map[string]string {
+	m := map[string]string{}
+	for _, r := range results {
+		m[r.path] = r.status
+	}
+
+	return m
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	resource, err := loadResource(filepath.Join("testdata", "product.json"))
+	require.NoError(t, err)
+
+	root := newTestRoot(t)
+	first := time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+	g := &generator{root: root, now: func() time.Time { return first }}
+
+	results, err := g.generate(resource)
+	require.NoError(t, err)
+	require.Len(t, results, 13)
+	for _, r := range results {
+		require.Equal(t, statusCreated, r.status, r.path)
+	}
+
+	migration := filepath.Join("migrations", "20250917120000_create_products_table.sql")
+	require.Contains(t, statuses(results), migration)
+
+	dto, err := os.ReadFile(filepath.Join(root, "dto", "product.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(dto), `ReleasedAt time.Time`)
+	require.Contains(t, string(dto), `json:"released_at"`)
+
+	api, err := os.ReadFile(filepath.Join(root, "api", "product.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(api), `"example.com/app/service"`)
+
+	// A later run keeps the migration version and writes nothing.
+	g.now = func() time.Time { return first.Add(time.Hour) }
+	results, err = g.generate(resource)
+	require.NoError(t, err)
+	require.Contains(t, statuses(results), migration)
+	for _, r := range results {
+		require.Equal(t, statusUnchanged, r.status, r.path)
+	}
+
+	// Local changes are kept unless forced.
+	edited := filepath.Join(root, "service", "product_validator.go")
+	require.NoError(t, os.WriteFile(edited, []byte("package service\n"), 0o644))
+
+	results, err = g.generate(resource)
+	require.NoError(t, err)
+	require.Equal(t, statusSkipped, statuses(results)[filepath.Join("service", "product_validator.go")])
+	content, err := os.ReadFile(edited)
+	require.NoError(t, err)
+	require.Equal(t, "package service\n", string(content))
+
+	g.force = true
+	results, err = g.generate(resource)
+	require.NoError(t, err)
+	require.Equal(t, statusUpdated, statuses(results)[filepath.Join("service", "product_validator.go")])
+	content, err = os.ReadFile(edited)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "func NewProductValidator")
+}
+
+// TestGenerator_Generate_Builds generates into a copy of this module and
+// builds, vets and tests the result, so the templates cannot drift from the
+// packages they build on.
+func TestGenerator_Generate_Builds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a copy of the module")
+	}
+	if _, err := exec.LookPath("mockgen"); err != nil {
+		t.Skip("mockgen is not installed")
+	}
+
+	resource, err := loadResource(filepath.Join("testdata", "product.json"))
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	require.NoError(t, os.CopyFS(root, os.DirFS(filepath.Join("..", ".."))))
+
+	g := &generator{root: root, now: time.Now}
+	results, err := g.generate(resource)
+	require.NoError(t, err)
+	_, err = generateMocks(root, resource, results)
+	require.NoError(t, err)
+
+	commands := [][]string{
+		{"build", "./..."},
+		{"vet", "./..."},
+		{"test", "-run", "^Test" + resource.Name, "./repository", "./service", "./api"},
+	}
+	for _, args := range commands {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = root
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "go %s:\n%s", strings.Join(args, " "), output)
+	}
+}
+
+func TestGenerator_Generate_Errors(t *testing.T) {
+	resource := &Resource{Name: "Product", Fields: []Field{{Name: "Title", Type: "string"}}}
+
+	type testCase struct {
+		name    string
+		prepare func(t *testing.T, root string)
+		wantErr string
+	}
+
+	cases := []testCase{{
+		name: "no go.mod",
+		prepare: func(t *testing.T, root string) {
+			require.NoError(t, os.Remove(filepath.Join(root, "go.mod")))
+		},
+		wantErr: "is not a module root",
+	}, {
+		name: "no module directive",
+		prepare: func(t *testing.T, root string) {
+			require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("go 1.25.0\n"), 0o644))
+		},
+		wantErr: "no module directive",
+	}, {
+		name: "two migrations create the table",
+		prepare: func(t *testing.T, root string) {
+			for _, name := range []string{"1_create_products_table.sql", "2_create_products_table.sql"} {
+				require.NoError(t, os.WriteFile(filepath.Join(root, "migrations", name), nil, 0o644))
+			}
+		},
+		wantErr: "more than one migration creates products",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := newTestRoot(t)
+			tc.prepare(t, root)
+
+			g := &generator{root: root, now: time.Now}
+			_, err := g.generate(resource)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLoadResource(t *testing.T) {
+	type testCase struct {
+		name       string
+		definition string
+		wantErr    string
+	}
+
+	cases := []testCase{{
+		name:       "valid",
+		definition: `{"name": "OrderItem", "fields": [{"name": "Quantity", "type": "uint", "required": true, "max": 10}]}`,
+	}, {
+		name:       "unknown key",
+		definition: `{"name": "Product", "fields": [{"name": "Title", "type": "string", "unique": true}]}`,
+		wantErr:    "unknown field",
+	}, {
+		name:       "unexported name",
+		definition: `{"name": "product", "fields": [{"name": "Title", "type": "string"}]}`,
+		wantErr:    "must be an exported Go identifier",
+	}, {
+		name:       "no fields",
+		definition: `{"name": "Product", "fields": []}`,
+		wantErr:    "at least one field is required",
+	}, {
+		name:       "reserved field",
+		definition: `{"name": "Product", "fields": [{"name": "TenantID", "type": "string"}]}`,
+		wantErr:    "name is reserved",
+	}, {
+		name:       "duplicate field",
+		definition: `{"name": "Product", "fields": [{"name": "Title", "type": "string"}, {"name": "Title", "type": "string"}]}`,
+		wantErr:    "defined twice",
+	}, {
+		name:       "unknown type",
+		definition: `{"name": "Product", "fields": [{"name": "Title", "type": "text"}]}`,
+		wantErr:    `unknown type "text"`,
+	}, {
+		name:       "required bool",
+		definition: `{"name": "Product", "fields": [{"name": "Active", "type": "bool", "required": true}]}`,
+		wantErr:    "a bool cannot be required",
+	}, {
+		name:       "bound on a time",
+		definition: `{"name": "Product", "fields": [{"name": "ReleasedAt", "type": "time", "min": 1}]}`,
+		wantErr:    "min and max do not apply to time",
+	}, {
+		name:       "fractional int bound",
+		definition: `{"name": "Product", "fields": [{"name": "Stock", "type": "int", "min": 0.5}]}`,
+		wantErr:    "must be whole numbers",
+	}, {
+		name:       "negative string bound",
+		definition: `{"name": "Product", "fields": [{"name": "Title", "type": "string", "min": -1}]}`,
+		wantErr:    "cannot be negative",
+	}, {
+		name:       "min greater than max",
+		definition: `{"name": "Product", "fields": [{"name": "Price", "type": "float64", "min": 10, "max": 1}]}`,
+		wantErr:    "min is greater than max",
+	}, {
+		name:       "unsatisfiable",
+		definition: `{"name": "Product", "fields": [{"name": "Stock", "type": "int", "required": true, "min": 0, "max": 0}]}`,
+		wantErr:    "no value satisfies the constraints",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "definition.json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.definition), 0o644))
+
+			_, err := loadResource(path)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestResource_Names(t *testing.T) {
+	type testCase struct {
+		name      string
+		wantVar   string
+		wantFile  string
+		wantTable string
+	}
+
+	cases := []testCase{{
+		name:      "Product",
+		wantVar:   "product",
+		wantFile:  "product",
+		wantTable: "products",
+	}, {
+		name:      "OrderItem",
+		wantVar:   "orderItem",
+		wantFile:  "order_item",
+		wantTable: "order_items",
+	}, {
+		name:      "HTTPRoute",
+		wantVar:   "httpRoute",
+		wantFile:  "http_route",
+		wantTable: "http_routes",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Resource{Name: tc.name}
+			require.Equal(t, tc.wantVar, r.Var())
+			require.Equal(t, tc.wantFile, r.File())
+			require.Equal(t, tc.wantTable, r.Table())
+			require.False(t, strings.Contains(r.Label(), "_"))
+		})
+	}
+}
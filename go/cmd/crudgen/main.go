@@ -0,0 +1,134 @@
+// Command crudgen scaffolds a CRUD resource from a JSON definition: the dto,
+// goose migrations for both dialects, repository, service, validator, HTTP
+// handlers and their tests, built on the generic Repo, Service and
+// MountCRUD. The service is generated with an authorization decorator whose
+// permissions no role holds yet, so a new resource is closed until the
+// policy grants them. See Resource for the definition format.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+const usage string = `usage: crudgen [flags] DEFINITION.json
+
+Generates the files of a CRUD resource under the module root. Running it
+again with the same definition changes nothing; files edited since they were
+generated are kept unless -force is given.
+
+flags:
+`
+
+func main() {
+	root := flag.String("root", ".", "module root to generate into")
+	force := flag.Bool("force", false, "overwrite generated files that have local changes")
+	mocks := flag.Bool("mocks", true, "run go generate on the new files to create their mocks (needs mockgen)")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	resource, err := loadResource(flag.Arg(0))
+	if err != nil {
+		fatal("definition is invalid", err)
+	}
+
+	g := &generator{root: *root, force: *force, now: time.Now}
+	results, err := g.generate(resource)
+	if err != nil {
+		fatal("generation failed", err)
+	}
+
+	if *mocks {
+		mockResults, err := generateMocks(*root, resource, results)
+		if err != nil {
+			fatal("mock generation failed", err)
+		}
+		results = append(results, mockResults...)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\n", r.path, r.status)
+	}
+	w.Flush()
+
+	printNextSteps(resource)
+}
+
+// generateMocks runs the mockgen directives of the resource's interfaces
+// when their source changed or their mock is missing.
+func generateMocks(root string, resource *Resource, results []result) ([]result, error) {
+	status := map[string]string{}
+	for _, r := range results {
+		status[r.path] = r.status
+	}
+
+	var mockResults []result
+	file := resource.File()
+	sources := []string{
+		filepath.Join("repository", file+".go"),
+		filepath.Join("service", file+".go"),
+		filepath.Join("service", file+"_validator.go"),
+	}
+
+	for _, source := range sources {
+		dir, name := filepath.Split(source)
+		pkg := filepath.Base(dir)
+		mock := filepath.Join(dir, "mocks_"+pkg, "mock_"+name)
+		if _, err := os.Stat(filepath.Join(root, mock)); err == nil && status[source] == statusUnchanged {
+			mockResults = append(mockResults, result{path: mock, status: statusUnchanged})
+			continue
+		}
+
+		cmd := exec.Command("go", "generate", "./"+filepath.ToSlash(source))
+		cmd.Dir = root
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("go generate %s: %w", source, err)
+		}
+		mockResults = append(mockResults, result{path: mock, status: statusGenerated})
+	}
+
+	return mockResults, nil
+}
+
+func printNextSteps(resource *Resource) {
+	name := resource.Name
+	v := resource.Var()
+	table := resource.Table()
+
+	steps := []string{
+		fmt.Sprintf("add %sRepo repository.%sRepo to repositories in storage.go and set it to repository.New%sRepo(db); there is no in-memory version", v, name, name),
+		fmt.Sprintf("build the service in main.go: service.New%s(service.New%sValidator(repos.%sRepo), repos.%sRepo, transactor)", name, name, v, v),
+		fmt.Sprintf("wrap it with service.New%sWithAuthorization(%sService, policy)", name, v),
+		fmt.Sprintf("grant %s:read, %s:write and %s:delete to the roles that need them in config/policy.yaml; until then every call is forbidden", table, table, table),
+		fmt.Sprintf("mount it next to the other handlers: api.Set%sHandlers(r, %sService)", name, v),
+	}
+
+	fmt.Println()
+	fmt.Println("next steps:")
+	for _, step := range steps {
+		fmt.Println("  - " + step)
+	}
+	fmt.Println("  - run go test ./...")
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
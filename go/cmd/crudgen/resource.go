@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm/schema"
+)
+
+// reservedFields are added to every resource by the templates.
+var reservedFields = []string{"ID", "TenantID", "CreatedAt", "UpdatedAt", "DeletedAt"}
+
+// fieldTypes maps the types a definition may use to their Go type and the
+// column types of each dialect.
+var fieldTypes = map[string]fieldType{
+	"string":  {goType: "string", postgres: "TEXT", sqlite: "TEXT", zero: `''`},
+	"int":     {goType: "int", postgres: "INTEGER", sqlite: "INTEGER", zero: "0", integer: true},
+	"int64":   {goType: "int64", postgres: "BIGINT", sqlite: "INTEGER", zero: "0", integer: true},
+	"uint":    {goType: "uint", postgres: "BIGINT", sqlite: "INTEGER", zero: "0", integer: true, unsigned: true},
+	"float64": {goType: "float64", postgres: "DOUBLE PRECISION", sqlite: "REAL", zero: "0"},
+	"bool":    {goType: "bool", postgres: "BOOLEAN", sqlite: "BOOLEAN", zero: "FALSE"},
+	"time":    {goType: "time.Time", postgres: "TIMESTAMP", sqlite: "TIMESTAMP", zero: "CURRENT_TIMESTAMP"},
+}
+
+type fieldType struct {
+	goType   string
+	postgres string
+	sqlite   string
+	zero     string
+	integer  bool
+	unsigned bool
+}
+
+// Resource is the definition crudgen reads, e.g.
+//
+//	{
+//	  "name": "Product",
+//	  "fields": [
+//	    {"name": "Title", "type": "string", "required": true, "min": 2, "max": 100},
+//	    {"name": "Price", "type": "float64", "min": 0}
+//	  ]
+//	}
+//
+// min and max bound the length of strings and the value of numbers.
+type Resource struct {
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+}
+
+type Field struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Min      *float64 `json:"min"`
+	Max      *float64 `json:"max"`
+}
+
+func loadResource(path string) (*Resource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource Resource
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&resource); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if err := resource.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &resource, nil
+}
+
+func (r *Resource) validate() error {
+	if !isExported(r.Name) {
+		return fmt.Errorf("name %q must be an exported Go identifier", r.Name)
+	}
+	if len(r.Fields) == 0 {
+		return errors.New("at least one field is required")
+	}
+
+	seen := map[string]bool{}
+	for _, field := range r.Fields {
+		if err := field.validate(); err != nil {
+			return fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("field %q is defined twice", field.Name)
+		}
+		seen[field.Name] = true
+	}
+
+	return nil
+}
+
+func (f Field) validate() error {
+	if !isExported(f.Name) {
+		return errors.New("name must be an exported Go identifier")
+	}
+	for _, reserved := range reservedFields {
+		if f.Name == reserved {
+			return errors.New("name is reserved, every resource has it")
+		}
+	}
+
+	t, ok := fieldTypes[f.Type]
+	if !ok {
+		return fmt.Errorf("unknown type %q", f.Type)
+	}
+	if f.Required && f.Type == "bool" {
+		return errors.New("a bool cannot be required")
+	}
+
+	bounded := f.Type == "string" || t.integer || f.Type == "float64"
+	if (f.Min != nil || f.Max != nil) && !bounded {
+		return fmt.Errorf("min and max do not apply to %s", f.Type)
+	}
+	for _, bound := range []*float64{f.Min, f.Max} {
+		if bound == nil {
+			continue
+		}
+		if (f.Type == "string" || t.integer) && *bound != math.Trunc(*bound) {
+			return errors.New("min and max must be whole numbers")
+		}
+		if (f.Type == "string" || t.unsigned) && *bound < 0 {
+			return errors.New("min and max cannot be negative")
+		}
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return errors.New("min is greater than max")
+	}
+	if _, ok := f.validValue(); !ok {
+		return errors.New("no value satisfies the constraints")
+	}
+
+	return nil
+}
+
+func isExported(name string) bool {
+	return token.IsIdentifier(name) && token.IsExported(name)
+}
+
+// The methods below derive what the templates need from the definition.
+
+var naming = schema.NamingStrategy{}
+
+// Var is the name of a variable holding the resource: orderItem.
+func (r *Resource) Var() string {
+	return lowerFirst(r.Name)
+}
+
+// File is the base name of the resource's files: order_item.
+func (r *Resource) File() string {
+	return naming.ColumnName("", r.Name)
+}
+
+// Table is the table GORM maps the resource to: order_items.
+func (r *Resource) Table() string {
+	return naming.TableName(r.Name)
+}
+
+// Label names the resource in messages: order item.
+func (r *Resource) Label() string {
+	return strings.ReplaceAll(r.File(), "_", " ")
+}
+
+func (r *Resource) HasTimeField() bool {
+	return slices.ContainsFunc(r.Fields, Field.IsTime)
+}
+
+// NeedsStrings and NeedsTime tell the validator templates which imports
+// their checks use.
+func (r *Resource) NeedsStrings() bool {
+	return slices.ContainsFunc(r.Fields, func(f Field) bool { return f.IsString() && f.HasRules() })
+}
+
+func (r *Resource) NeedsTime() bool {
+	return slices.ContainsFunc(r.Fields, func(f Field) bool { return f.IsTime() && f.HasRules() })
+}
+
+// TestsUseStrings tells whether the validator tests build strings with
+// strings.Repeat.
+func (r *Resource) TestsUseStrings() bool {
+	for _, field := range r.Fields {
+		for _, c := range field.InvalidCases() {
+			if strings.HasPrefix(c.Literal, "strings.") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (f Field) GoType() string {
+	return fieldTypes[f.Type].goType
+}
+
+func (f Field) Column() string {
+	return naming.ColumnName("", f.Name)
+}
+
+// Label names the field in messages and validation rules: unit_price.
+func (f Field) Label() string {
+	return f.Column()
+}
+
+func (f Field) IsString() bool {
+	return f.Type == "string"
+}
+
+func (f Field) IsTime() bool {
+	return f.Type == "time"
+}
+
+func (f Field) IsNumber() bool {
+	t := fieldTypes[f.Type]
+	return t.integer || f.Type == "float64"
+}
+
+// PostgresType and SQLiteType are the column definitions; strings with a
+// max get a VARCHAR of that size.
+func (f Field) PostgresType() string {
+	return f.columnType(fieldTypes[f.Type].postgres)
+}
+
+func (f Field) SQLiteType() string {
+	return f.columnType(fieldTypes[f.Type].sqlite)
+}
+
+func (f Field) columnType(base string) string {
+	if f.IsString() && f.Max != nil {
+		base = fmt.Sprintf("VARCHAR(%d)", int(*f.Max))
+	}
+
+	return fmt.Sprintf("%s NOT NULL DEFAULT %s", base, fieldTypes[f.Type].zero)
+}
+
+func (f Field) HasRules() bool {
+	return f.Required || f.Min != nil || f.Max != nil
+}
+
+func (f Field) HasMin() bool { return f.Min != nil }
+func (f Field) HasMax() bool { return f.Max != nil }
+
+// MinLiteral and MaxLiteral are the bounds as Go constants of the field's
+// type.
+func (f Field) MinLiteral() string { return f.literal(*f.Min) }
+func (f Field) MaxLiteral() string { return f.literal(*f.Max) }
+
+func (f Field) literal(v float64) string {
+	if f.IsString() || fieldTypes[f.Type].integer {
+		return strconv.FormatInt(int64(v), 10)
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// ValidLiteral is a Go expression for a value that passes validation.
+func (f Field) ValidLiteral() string {
+	v, _ := f.validValue()
+	return v
+}
+
+// validValue picks the smallest value that satisfies every constraint.
+func (f Field) validValue() (string, bool) {
+	switch {
+	case f.Type == "bool":
+		return "true", true
+	case f.IsTime():
+		return "time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)", true
+	case f.IsString():
+		n := 1
+		if f.Min != nil {
+			n = max(n, int(*f.Min))
+		}
+		if f.Max != nil && n > int(*f.Max) {
+			return "", false
+		}
+		return strconv.Quote(strings.Repeat("a", n)), true
+	}
+
+	candidates := []float64{1}
+	if f.Min != nil {
+		candidates = []float64{*f.Min, *f.Min + 1}
+	}
+	if f.Max != nil {
+		candidates = append(candidates, *f.Max)
+	}
+	for _, v := range candidates {
+		if f.accepts(v) {
+			return f.literal(v), true
+		}
+	}
+
+	return "", false
+}
+
+func (f Field) accepts(v float64) bool {
+	if f.Required && v == 0 {
+		return false
+	}
+	if f.Min != nil && v < *f.Min {
+		return false
+	}
+	if f.Max != nil && v > *f.Max {
+		return false
+	}
+
+	return true
+}
+
+// InvalidCase is an input the validator must reject with Rule.
+type InvalidCase struct {
+	Name    string
+	Literal string
+	Rule    string
+}
+
+// InvalidCases lists one rejected value per constraint of the field. A
+// bound whose violation is caught by an earlier check is left out.
+func (f Field) InvalidCases() []InvalidCase {
+	var cases []InvalidCase
+	label := f.Label()
+
+	if f.Required {
+		zero := "0"
+		switch {
+		case f.IsString():
+			zero = `""`
+		case f.IsTime():
+			zero = "time.Time{}"
+		}
+		cases = append(cases, InvalidCase{Name: label + " is required", Literal: zero, Rule: label + "_required"})
+	}
+
+	if f.IsString() {
+		if f.Min != nil && *f.Min > 0 && !(f.Required && *f.Min == 1) {
+			cases = append(cases, InvalidCase{
+				Name:    label + " too short",
+				Literal: repeatLiteral(int(*f.Min) - 1),
+				Rule:    label + "_min_length",
+			})
+		}
+		if f.Max != nil {
+			cases = append(cases, InvalidCase{
+				Name:    label + " too long",
+				Literal: repeatLiteral(int(*f.Max) + 1),
+				Rule:    label + "_max_length",
+			})
+		}
+	}
+
+	if f.IsNumber() {
+		if f.Min != nil && !(fieldTypes[f.Type].unsigned && *f.Min == 0) && !(f.Required && *f.Min-1 == 0) {
+			cases = append(cases, InvalidCase{Name: label + " below min", Literal: f.literal(*f.Min - 1), Rule: label + "_min"})
+		}
+		if f.Max != nil && !(f.Required && *f.Max+1 == 0) {
+			cases = append(cases, InvalidCase{Name: label + " above max", Literal: f.literal(*f.Max + 1), Rule: label + "_max"})
+		}
+	}
+
+	return cases
+}
+
+func repeatLiteral(n int) string {
+	if n == 0 {
+		return `""`
+	}
+
+	return fmt.Sprintf("strings.Repeat(%q, %d)", "a", n)
+}
+
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+		// Keep the last capital of an initialism that starts a word:
+		// HTTPServer becomes httpServer.
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+
+	return string(runes)
+}
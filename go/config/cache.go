@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadCachePolicies reads HTTP_CACHE_POLICIES, the Cache-Control headers of
+// cacheable reads by route, as route=policy entries separated by semicolons:
+//
+//	/users/{id}=private, max-age=60;/users/list=no-store
+//
+// Routes are full route patterns; the ones left out keep the default policy.
+func LoadCachePolicies() (map[string]string, error) {
+	return parseCachePolicies(os.Getenv("HTTP_CACHE_POLICIES"))
+}
+
+func parseCachePolicies(value string) (map[string]string, error) {
+	policies := map[string]string{}
+
+	for entry := range strings.SplitSeq(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		route, policy, ok := strings.Cut(entry, "=")
+		route, policy = strings.TrimSpace(route), strings.TrimSpace(policy)
+		if !ok || !strings.HasPrefix(route, "/") || policy == "" {
+			return nil, fmt.Errorf("invalid HTTP_CACHE_POLICIES entry %q: want route=policy", entry)
+		}
+		if _, ok := policies[route]; ok {
+			return nil, fmt.Errorf("invalid HTTP_CACHE_POLICIES: %s is listed twice", route)
+		}
+
+		policies[route] = policy
+	}
+
+	return policies, nil
+}
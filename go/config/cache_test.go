@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCachePolicies(t *testing.T) {
+	type testCase struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr string
+	}
+
+	cases := []testCase{
+		{
+			name:  "unset",
+			value: "",
+			want:  map[string]string{},
+		}, {
+			name:  "policies with commas",
+			value: "/users/{id}=private, max-age=60;/users/list=no-store",
+			want:  map[string]string{"/users/{id}": "private, max-age=60", "/users/list": "no-store"},
+		}, {
+			name:  "spaces and a trailing separator",
+			value: " /users/list = private, max-age=5 ; ",
+			want:  map[string]string{"/users/list": "private, max-age=5"},
+		}, {
+			name:    "missing policy",
+			value:   "/users/list=",
+			wantErr: `invalid HTTP_CACHE_POLICIES entry "/users/list=": want route=policy`,
+		}, {
+			name:    "missing separator",
+			value:   "/users/list",
+			wantErr: `invalid HTTP_CACHE_POLICIES entry "/users/list": want route=policy`,
+		}, {
+			name:    "route without leading slash",
+			value:   "users/list=no-store",
+			wantErr: `invalid HTTP_CACHE_POLICIES entry "users/list=no-store": want route=policy`,
+		}, {
+			name:    "route listed twice",
+			value:   "/users/list=no-store;/users/list=no-cache",
+			wantErr: "invalid HTTP_CACHE_POLICIES: /users/list is listed twice",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseCachePolicies(tc.value)
+
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
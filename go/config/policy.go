@@ -0,0 +1,24 @@
+package config
+
+import (
+	_ "embed"
+	"os"
+
+	"crud_app/auth"
+)
+
+//go:embed policy.yaml
+var defaultPolicy []byte
+
+func LoadPolicy() (*auth.Policy, error) {
+	data := defaultPolicy
+
+	if path := os.Getenv("AUTH_POLICY_FILE"); path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.ParsePolicy(data)
+}
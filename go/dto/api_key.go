@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type APIKey struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TenantID  *string    `json:"tenant_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	KeyHash   string     `json:"-"`
+	Roles     string     `json:"roles"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+const (
+	JobKindUserImport string = "user_import"
+
+	JobStatusPending   string = "pending"
+	JobStatusRunning   string = "running"
+	JobStatusSucceeded string = "succeeded"
+	JobStatusFailed    string = "failed"
+)
+
+// Job is a background job. Owner names the process running it, which renews
+// RenewedAt for as long as it is alive.
+type Job struct {
+	ID         uint64     `gorm:"primaryKey" json:"id"`
+	TenantID   string     `json:"-"`
+	Kind       string     `json:"kind"`
+	Status     string     `json:"status"`
+	DryRun     bool       `json:"dry_run"`
+	CreatedBy  string     `json:"created_by"`
+	BytesTotal int64      `json:"bytes_total"`
+	BytesRead  int64      `json:"bytes_read"`
+	Processed  int        `json:"processed"`
+	Valid      int        `json:"valid"`
+	Invalid    int        `json:"invalid"`
+	Imported   int        `json:"imported"`
+	Error      string     `json:"error"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Owner      string     `json:"-"`
+	RenewedAt  *time.Time `json:"-"`
+}
+
+type JobProgress struct {
+	BytesRead int64
+	Processed int
+	Valid     int
+	Invalid   int
+	Imported  int
+}
+
+// JobError is one rejected input row; Line is its line in the uploaded file.
+type JobError struct {
+	ID      uint64 `gorm:"primaryKey" json:"-" csv:"-"`
+	JobID   uint64 `json:"job_id" csv:"job_id"`
+	Line    int    `json:"line" csv:"line"`
+	Message string `json:"message" csv:"message"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a recorded domain event. Sequence orders events by the
+// commit that made them visible, which ID does not; it is nil until the relay
+// assigns it.
+type OutboxEvent struct {
+	ID            uint64          `gorm:"primaryKey" json:"id"`
+	Sequence      *uint64         `json:"sequence"`
+	TenantID      string          `json:"tenant_id"`
+	EventType     string          `json:"event_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+type PageRequest struct {
+	Limit  int
+	Offset int
+}
+
+type Page[T any] struct {
+	Items  []T   `json:"items"`
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// ListQuery selects the records matching every filter and condition; a zero
+// Limit returns every match.
+type ListQuery struct {
+	PageRequest
+	// Filters maps field names to the exact value they must have.
+	Filters    map[string]string
+	Conditions []Condition
+}
+
+type Operator string
+
+const (
+	OpEqual          Operator = "="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	// OpContains matches text fields containing Value, ignoring case.
+	OpContains Operator = "contains"
+)
+
+// Condition compares a field with Value, which is parsed as the field's type.
+type Condition struct {
+	Field string
+	Op    Operator
+	Value string
+}
@@ -1,15 +1,17 @@
 package dto
 
 import (
-    "time"
-    "gorm.io/gorm"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uint `gorm:"primaryKey" json:"id"`
-	Name      string
-	Age       int
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `json:"omitempty"`
-}
\ No newline at end of file
+	ID        uint           `gorm:"primaryKey" json:"id" csv:"id"`
+	TenantID  string         `json:"-"`
+	Name      string         `csv:"name"`
+	Age       int            `csv:"age"`
+	CreatedAt time.Time      `csv:"created_at"`
+	UpdatedAt time.Time      `csv:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"omitempty" csv:"deleted_at"`
+}
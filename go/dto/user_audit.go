@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditOperationCreate  string = "create"
+	AuditOperationUpdate  string = "update"
+	AuditOperationDelete  string = "delete"
+	AuditOperationRestore string = "restore"
+	AuditOperationRevert  string = "revert"
+	AuditOperationImport  string = "import"
+	AuditOperationPurge   string = "purge"
+)
+
+type UserAudit struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	TenantID  string          `json:"-"`
+	UserID    uint            `json:"user_id"`
+	Operation string          `json:"operation"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Diff      json.RawMessage `json:"diff"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserVersion struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint
+	TenantID  string
+	Name      string
+	Age       int
+	CreatedAt time.Time `gorm:"autoCreateTime:false"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:false"`
+	DeletedAt *time.Time
+	ValidFrom time.Time
+	ValidTo   *time.Time
+}
+
+func (v *UserVersion) User() *User {
+	user := &User{
+		ID:        v.UserID,
+		TenantID:  v.TenantID,
+		Name:      v.Name,
+		Age:       v.Age,
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}
+	if v.DeletedAt != nil {
+		user.DeletedAt = gorm.DeletedAt{Time: *v.DeletedAt, Valid: true}
+	}
+
+	return user
+}
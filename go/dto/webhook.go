@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookDeliveryPending   string = "pending"
+	WebhookDeliverySucceeded string = "succeeded"
+	WebhookDeliveryFailed    string = "failed"
+)
+
+type WebhookSubscription struct {
+	ID         uint     `gorm:"primaryKey" json:"id"`
+	TenantID   string   `json:"-"`
+	URL        string   `json:"url"`
+	EventTypes []string `gorm:"serializer:json" json:"event_types"`
+	Secret     string   `json:"secret,omitempty"`
+	// Active is a pointer so an update that leaves it out keeps it as is.
+	Active              *bool      `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uint64          `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint            `json:"subscription_id"`
+	TenantID       string          `json:"-"`
+	EventID        uint64          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"-"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
@@ -0,0 +1,57 @@
+// Package errkind classifies the errors of the services once, so the HTTP,
+// gRPC and GraphQL transports report the same failure the same way.
+package errkind
+
+import (
+	"context"
+	"errors"
+
+	"crud_app/auth"
+	"crud_app/jobs"
+	"crud_app/repository"
+	"crud_app/service"
+	"crud_app/tenant"
+)
+
+type Kind int
+
+const (
+	Internal Kind = iota
+	Invalid
+	Unauthenticated
+	Forbidden
+	NotFound
+	Unavailable
+	Canceled
+	DeadlineExceeded
+)
+
+// ErrInvalidRequest is wrapped by the transports when a request cannot be
+// decoded or is out of range.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// Of returns the kind of err. A validation error about a record that does not
+// exist wraps repository.ErrNotFound and is NotFound rather than Invalid.
+func Of(err error) Kind {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return Unauthenticated
+	case errors.Is(err, auth.ErrForbidden):
+		return Forbidden
+	case errors.Is(err, repository.ErrNotFound):
+		return NotFound
+	case errors.As(err, &validationErr), errors.Is(err, ErrInvalidRequest), errors.Is(err, repository.ErrInvalidFilter),
+		errors.Is(err, tenant.ErrRequired), errors.Is(err, tenant.ErrInvalid):
+		return Invalid
+	case errors.Is(err, jobs.ErrQueueFull):
+		return Unavailable
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	default:
+		return Internal
+	}
+}
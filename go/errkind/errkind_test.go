@@ -0,0 +1,46 @@
+package errkind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/auth"
+	"crud_app/jobs"
+	"crud_app/repository"
+	"crud_app/service"
+	"crud_app/tenant"
+)
+
+func TestOf(t *testing.T) {
+	type testCase struct {
+		name string
+		err  error
+		want Kind
+	}
+
+	cases := []testCase{
+		{name: "unauthenticated", err: auth.ErrUnauthenticated, want: Unauthenticated},
+		{name: "forbidden", err: fmt.Errorf("delete: %w", auth.ErrForbidden), want: Forbidden},
+		{name: "not found", err: repository.ErrNotFound, want: NotFound},
+		{name: "validation error", err: &service.ValidationError{Rule: "name_required", Message: "name is required"}, want: Invalid},
+		{name: "validation error about a missing record", err: &service.ValidationError{Rule: "user_exists", Message: "user with ID 9 not found", Err: repository.ErrNotFound}, want: NotFound},
+		{name: "invalid request", err: fmt.Errorf("%w: malformed cursor", ErrInvalidRequest), want: Invalid},
+		{name: "invalid filter", err: repository.ErrInvalidFilter, want: Invalid},
+		{name: "missing tenant", err: tenant.ErrRequired, want: Invalid},
+		{name: "malformed tenant", err: tenant.ErrInvalid, want: Invalid},
+		{name: "queue full", err: jobs.ErrQueueFull, want: Unavailable},
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), want: Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: DeadlineExceeded},
+		{name: "anything else", err: errors.New("connection refused"), want: Internal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, Of(tc.err))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"slices"
+	"sync"
+)
+
+// Broker fans committed events out to in-process subscribers. It never
+// blocks a publisher: a subscriber whose buffer is full is closed and has to
+// catch up from the change log.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	buffer      int
+	closed      bool
+}
+
+type Subscription struct {
+	broker     *Broker
+	tenantID   string
+	eventTypes []string
+	events     chan Event
+	lagged     bool
+}
+
+func NewBroker(buffer int) *Broker {
+	return &Broker{
+		subscribers: make(map[*Subscription]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Subscribe registers for the tenant's events of the given types; an empty
+// eventTypes matches every type.
+func (b *Broker) Subscribe(tenantID string, eventTypes []string) *Subscription {
+	s := &Subscription{
+		broker:     b,
+		tenantID:   tenantID,
+		eventTypes: eventTypes,
+		events:     make(chan Event, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(s.events)
+		return s
+	}
+	b.subscribers[s] = struct{}{}
+
+	return s
+}
+
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subscribers {
+		if s.tenantID != event.TenantID {
+			continue
+		}
+		if len(s.eventTypes) > 0 && !slices.Contains(s.eventTypes, event.Type) {
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		default:
+			s.lagged = true
+			b.remove(s)
+		}
+	}
+}
+
+// Close ends every subscription, e.g. so long-lived streams let the server
+// shut down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subscribers {
+		b.remove(s)
+	}
+}
+
+func (b *Broker) remove(s *Subscription) {
+	if _, ok := b.subscribers[s]; !ok {
+		return
+	}
+
+	delete(b.subscribers, s)
+	close(s.events)
+}
+
+// Events is closed when the subscription ends.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Lagged reports whether the subscription was dropped for falling behind.
+func (s *Subscription) Lagged() bool {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	return s.lagged
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
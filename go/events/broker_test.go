@@ -0,0 +1,135 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	mock_repository "crud_app/repository/mocks_repository"
+	"crud_app/tenant"
+)
+
+func receive(s *Subscription) []uint64 {
+	var ids []uint64
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	type testCase struct {
+		name       string
+		tenantID   string
+		eventTypes []string
+		want       []uint64
+	}
+
+	published := []Event{
+		{ID: 1, TenantID: "acme", Type: UserCreated},
+		{ID: 2, TenantID: "globex", Type: UserCreated},
+		{ID: 3, TenantID: "acme", Type: UserDeleted},
+		{ID: 4, TenantID: "acme", Type: UserUpdated},
+	}
+
+	cases := []testCase{
+		{
+			name:     "all events of the tenant",
+			tenantID: "acme",
+			want:     []uint64{1, 3, 4},
+		}, {
+			name:       "filtered by type",
+			tenantID:   "acme",
+			eventTypes: []string{UserCreated, UserDeleted},
+			want:       []uint64{1, 3},
+		}, {
+			name:     "other tenant",
+			tenantID: "globex",
+			want:     []uint64{2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			broker := NewBroker(10)
+			subscription := broker.Subscribe(tc.tenantID, tc.eventTypes)
+			defer subscription.Close()
+
+			for _, event := range published {
+				broker.Publish(event)
+			}
+
+			require.Equal(t, tc.want, receive(subscription))
+		})
+	}
+}
+
+func TestBroker_SlowSubscriberIsDropped(t *testing.T) {
+	broker := NewBroker(2)
+	slow := broker.Subscribe("acme", nil)
+	fast := broker.Subscribe("acme", nil)
+
+	broker.Publish(Event{ID: 1, TenantID: "acme"})
+	broker.Publish(Event{ID: 2, TenantID: "acme"})
+	require.Equal(t, []uint64{1, 2}, receive(fast))
+
+	broker.Publish(Event{ID: 3, TenantID: "acme"})
+
+	require.Equal(t, []uint64{1, 2}, receive(slow))
+	require.True(t, slow.Lagged())
+	_, ok := <-slow.Events()
+	require.False(t, ok)
+
+	require.Equal(t, []uint64{3}, receive(fast))
+	require.False(t, fast.Lagged())
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker(1)
+	subscription := broker.Subscribe("acme", nil)
+
+	broker.Close()
+	_, ok := <-subscription.Events()
+	require.False(t, ok)
+	require.False(t, subscription.Lagged())
+
+	late := broker.Subscribe("acme", nil)
+	_, ok = <-late.Events()
+	require.False(t, ok)
+
+	subscription.Close()
+}
+
+func TestOutboxRecorder_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	outboxRepo := mock_repository.NewMockOutboxRepo(ctrl)
+
+	var recorded *dto.OutboxEvent
+	outboxRepo.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *dto.OutboxEvent) error {
+			recorded = event
+			return nil
+		})
+
+	notified := 0
+	ctx := tenant.WithID(t.Context(), "acme")
+	err := NewOutboxRecorder(outboxRepo, func() { notified++ }).Record(ctx, UserCreated, 7, map[string]int{"id": 7})
+	require.NoError(t, err)
+
+	require.Equal(t, UserCreated, recorded.EventType)
+	require.Equal(t, uint(7), recorded.AggregateID)
+	require.JSONEq(t, `{"id":7}`, string(recorded.Payload))
+	require.Nil(t, recorded.Sequence)
+	require.Equal(t, 1, notified)
+}
@@ -0,0 +1,24 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	UserCreated string = "UserCreated"
+	UserUpdated string = "UserUpdated"
+	UserDeleted string = "UserDeleted"
+	UserPurged  string = "UserPurged"
+)
+
+var UserEventTypes = []string{UserCreated, UserUpdated, UserDeleted, UserPurged}
+
+type Event struct {
+	ID          uint64          `json:"id"`
+	Type        string          `json:"type"`
+	TenantID    string          `json:"tenant_id"`
+	AggregateID uint            `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
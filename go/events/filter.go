@@ -0,0 +1,322 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed filter expression such as
+//
+//	type == "UserUpdated" && (age >= 18 || name == "Ann")
+//
+// Fields are type, id (the aggregate ID) and the top-level fields of the event
+// payload, matched case-insensitively. Comparisons with a missing field or a value of another kind are
+// false, except for != which is true. An event whose payload is not a JSON
+// object matches nothing.
+type Filter struct {
+	root filterNode
+}
+
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{tokens: tokenizeFilter(expr)}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if tok := p.peek(); tok.kind != filterEOF {
+		return nil, fmt.Errorf("invalid filter: unexpected %q", tok.text)
+	}
+
+	return &Filter{root: root}, nil
+}
+
+func (f *Filter) Match(event Event) bool {
+	var payload map[string]any
+	if len(event.Payload) > 0 {
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return false
+		}
+	}
+
+	fields := make(map[string]any, len(payload)+2)
+	for k, v := range payload {
+		fields[strings.ToLower(k)] = v
+	}
+	fields["type"] = event.Type
+	fields["id"] = float64(event.AggregateID)
+
+	return f.root.eval(fields)
+}
+
+type filterNode interface {
+	eval(fields map[string]any) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+
+func (n filterAnd) eval(fields map[string]any) bool {
+	return n.left.eval(fields) && n.right.eval(fields)
+}
+
+type filterOr struct{ left, right filterNode }
+
+func (n filterOr) eval(fields map[string]any) bool {
+	return n.left.eval(fields) || n.right.eval(fields)
+}
+
+type filterComparison struct {
+	field string
+	op    string
+	value any
+}
+
+func (n filterComparison) eval(fields map[string]any) bool {
+	actual, ok := fields[n.field]
+	if !ok {
+		return n.op == "!="
+	}
+
+	var cmp int
+	switch want := n.value.(type) {
+	case float64:
+		got, ok := actual.(float64)
+		if !ok {
+			return n.op == "!="
+		}
+		cmp = compareOrdered(got, want)
+	case string:
+		got, ok := actual.(string)
+		if !ok {
+			return n.op == "!="
+		}
+		cmp = compareOrdered(got, want)
+	case bool:
+		got, ok := actual.(bool)
+		if !ok || (n.op != "==" && n.op != "!=") {
+			return n.op == "!="
+		}
+		if got != want {
+			cmp = 1
+		}
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func compareOrdered[T float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+const (
+	filterEOF = iota
+	filterIdent
+	filterNumber
+	filterString
+	filterOp
+	filterLParen
+	filterRParen
+	filterInvalid
+)
+
+type filterToken struct {
+	kind int
+	text string
+}
+
+func tokenizeFilter(expr string) []filterToken {
+	var tokens []filterToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterLParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterRParen, text: ")"})
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j == len(runes) {
+				return append(tokens, filterToken{kind: filterInvalid, text: string(runes[i:])})
+			}
+			tokens = append(tokens, filterToken{kind: filterString, text: string(runes[i+1 : j])})
+			i = j + 1
+		case unicode.IsDigit(r) || r == '-':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, filterToken{kind: filterNumber, text: string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, filterToken{kind: filterOp, text: "&&"})
+			case "or":
+				tokens = append(tokens, filterToken{kind: filterOp, text: "||"})
+			default:
+				tokens = append(tokens, filterToken{kind: filterIdent, text: word})
+			}
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">"} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return append(tokens, filterToken{kind: filterInvalid, text: string(r)})
+			}
+			tokens = append(tokens, filterToken{kind: filterOp, text: op})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, filterToken{kind: filterEOF})
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != filterEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == filterOp && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == filterOp && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filterNode, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case filterLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != filterRParen {
+			return nil, fmt.Errorf("missing )")
+		}
+		return node, nil
+	case filterIdent:
+		op := p.next()
+		if op.kind != filterOp || op.text == "&&" || op.text == "||" {
+			return nil, fmt.Errorf("expected comparison after %q", tok.text)
+		}
+		value, err := parseFilterValue(p.next())
+		if err != nil {
+			return nil, err
+		}
+		return filterComparison{field: strings.ToLower(tok.text), op: op.text, value: value}, nil
+	case filterEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+}
+
+func parseFilterValue(tok filterToken) (any, error) {
+	switch tok.kind {
+	case filterString:
+		return tok.text, nil
+	case filterNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return n, nil
+	case filterIdent:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	case filterEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("expected a value, got %q", tok.text)
+}
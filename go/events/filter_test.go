@@ -0,0 +1,67 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	type testCase struct {
+		name  string
+		expr  string
+		event Event
+		want  bool
+	}
+
+	adult := Event{
+		Type:        UserUpdated,
+		AggregateID: 7,
+		Payload:     json.RawMessage(`{"id":7,"Name":"Ann","Age":34}`),
+	}
+
+	cases := []testCase{
+		{name: "type", expr: `type == "UserUpdated"`, event: adult, want: true},
+		{name: "type mismatch", expr: `type == "UserCreated"`, event: adult, want: false},
+		{name: "id", expr: `id == 7`, event: adult, want: true},
+		{name: "payload field is case-insensitive", expr: `name == 'Ann'`, event: adult, want: true},
+		{name: "numeric comparison", expr: `age >= 18 && age < 65`, event: adult, want: true},
+		{name: "or", expr: `age < 18 or name == "Ann"`, event: adult, want: true},
+		{name: "and binds tighter than or", expr: `age < 18 && name == "Ann" || id == 8`, event: adult, want: false},
+		{name: "parentheses", expr: `(age < 18 || name == "Ann") && id == 7`, event: adult, want: true},
+		{name: "missing field", expr: `email == "a@b.c"`, event: adult, want: false},
+		{name: "missing field not equal", expr: `email != "a@b.c"`, event: adult, want: true},
+		{name: "kind mismatch", expr: `age == "34"`, event: adult, want: false},
+		{name: "negative number", expr: `age > -1`, event: adult, want: true},
+		{name: "malformed payload", expr: `type == "UserUpdated"`, event: Event{Type: UserUpdated, Payload: json.RawMessage(`{"Name":`)}, want: false},
+		{name: "malformed payload not equal", expr: `name != "Ann"`, event: Event{Type: UserUpdated, Payload: json.RawMessage(`[1,2]`)}, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := ParseFilter(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, filter.Match(tc.event))
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"age",
+		"age >=",
+		"age >= 18 &&",
+		`name == "Ann`,
+		"(age > 1",
+		"age > 1)",
+		"age ~ 1",
+		"18 == age",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseFilter(expr)
+			require.Error(t, err)
+		})
+	}
+}
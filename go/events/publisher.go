@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type logPublisher struct{}
+
+func NewLogPublisher() EventPublisher {
+	return logPublisher{}
+}
+
+func (logPublisher) Publish(ctx context.Context, event Event) error {
+	slog.InfoContext(ctx, "event published",
+		slog.Uint64("event_id", event.ID),
+		slog.String("event_type", event.Type),
+		slog.String("tenant_id", event.TenantID),
+		slog.Uint64("aggregate_id", uint64(event.AggregateID)),
+	)
+
+	return nil
+}
+
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []Event
+	fail   func(Event) error
+}
+
+// NewMemoryPublisher keeps published events in memory. When fail is not nil
+// it is consulted first and a non-nil error rejects the event.
+func NewMemoryPublisher(fail func(Event) error) *MemoryPublisher {
+	return &MemoryPublisher{fail: fail}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.fail != nil {
+		if err := p.fail(event); err != nil {
+			return err
+		}
+	}
+
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *MemoryPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Event(nil), p.events...)
+}
+
+type filePublisher struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+func NewFilePublisher(path string) (EventPublisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterPublisher(f), nil
+}
+
+// NewWriterPublisher writes each event as one JSON line.
+func NewWriterPublisher(w io.Writer) EventPublisher {
+	return &filePublisher{w: w, enc: json.NewEncoder(w)}
+}
+
+func (p *filePublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.enc.Encode(event)
+}
+
+type multiPublisher struct {
+	publishers []EventPublisher
+}
+
+// NewMultiPublisher publishes every event to all publishers and fails if any
+// of them does; the relay then retries the event for all of them.
+func NewMultiPublisher(publishers ...EventPublisher) EventPublisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+// Recorder stores domain events alongside the write that caused them; it must
+// be called with the context of that write's transaction.
+type Recorder interface {
+	Record(ctx context.Context, eventType string, aggregateID uint, payload any) error
+}
+
+type outboxRecorder struct {
+	outboxRepo repository.OutboxRepo
+	notify     func()
+}
+
+// NewOutboxRecorder calls notify once the transaction of an event has
+// committed, e.g. Relay.Notify, so it is relayed without waiting for a poll.
+func NewOutboxRecorder(outboxRepo repository.OutboxRepo, notify func()) Recorder {
+	return &outboxRecorder{outboxRepo: outboxRepo, notify: notify}
+}
+
+func (r *outboxRecorder) Record(ctx context.Context, eventType string, aggregateID uint, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	outboxEvent := &dto.OutboxEvent{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	}
+	if err := r.outboxRepo.Add(ctx, outboxEvent); err != nil {
+		return err
+	}
+
+	repository.AfterCommit(ctx, r.notify)
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		MinBackoff:   time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Relay moves events from the outbox to a publisher. An event is marked
+// published only after Publish succeeds, so delivery is at-least-once.
+//
+// The relay also gives each event its sequence number, the event ID everyone
+// sees, and follows the sequenced change log into broker, so the subscribers
+// of every process get the events of all of them in sequence order.
+type Relay struct {
+	outboxRepo repository.OutboxRepo
+	transactor repository.Transactor
+	publisher  EventPublisher
+	broker     *Broker
+	cfg        RelayConfig
+	now        func() time.Time
+	wake       chan struct{}
+
+	// followed is the last sequence handed to broker; following is false
+	// until it has been read.
+	followed  uint64
+	following bool
+}
+
+func NewRelay(outboxRepo repository.OutboxRepo, transactor repository.Transactor, publisher EventPublisher, broker *Broker, cfg RelayConfig) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		transactor: transactor,
+		publisher:  publisher,
+		broker:     broker,
+		cfg:        cfg,
+		now:        time.Now,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Notify makes a running relay look for events now instead of at its next
+// poll, e.g. once a transaction that recorded some has committed.
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx, "outbox relay failed", r.RelayBatch)
+		r.drain(ctx, "change log follow failed", r.FollowBatch)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// drain runs batch until it comes back short or fails.
+func (r *Relay) drain(ctx context.Context, failure string, batch func(context.Context) (int, error)) {
+	for {
+		n, err := batch(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, failure, slog.Any("error", err))
+			return
+		}
+		if n < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// RelayBatch sequences and publishes up to one batch of due events and
+// returns how many were claimed.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	var claimed int
+
+	err := r.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		now := r.now()
+
+		pending, err := r.outboxRepo.ClaimPending(ctx, now, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		claimed = len(pending)
+
+		// Events keep the sequence of their first attempt, so retries
+		// carry the same ID.
+		if err := r.outboxRepo.AssignSequences(ctx, pending); err != nil {
+			return err
+		}
+
+		for _, outboxEvent := range pending {
+			if err := r.publisher.Publish(ctx, FromOutbox(outboxEvent)); err != nil {
+				slog.WarnContext(ctx, "event publish failed",
+					slog.Uint64("event_id", outboxEvent.ID),
+					slog.Int("attempts", outboxEvent.Attempts+1),
+					slog.Any("error", err),
+				)
+
+				next := now.Add(r.backoff(outboxEvent.Attempts + 1))
+				if err := r.outboxRepo.MarkFailed(ctx, outboxEvent.ID, next, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := r.outboxRepo.MarkPublished(ctx, outboxEvent.ID, r.now()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return claimed, err
+}
+
+// FollowBatch hands broker up to one batch of the events sequenced since the
+// last call and returns how many there were. The first call starts from the
+// end of the log: subscribers read what came before from the log itself.
+func (r *Relay) FollowBatch(ctx context.Context) (int, error) {
+	if !r.following {
+		last, err := r.outboxRepo.LastSequence(ctx)
+		if err != nil {
+			return 0, err
+		}
+		r.followed, r.following = last, true
+	}
+
+	sequenced, err := r.outboxRepo.ListSequenced(ctx, r.followed, r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, outboxEvent := range sequenced {
+		event := FromOutbox(outboxEvent)
+		r.broker.Publish(event)
+		r.followed = event.ID
+	}
+
+	return len(sequenced), nil
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.MinBackoff
+	for i := 1; i < attempts && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, r.cfg.MaxBackoff)
+}
+
+// FromOutbox is the event an outbox row carries. Its ID is the row's
+// sequence, which is zero until the relay has assigned one.
+func FromOutbox(outboxEvent dto.OutboxEvent) Event {
+	var sequence uint64
+	if outboxEvent.Sequence != nil {
+		sequence = *outboxEvent.Sequence
+	}
+
+	return Event{
+		ID:          sequence,
+		Type:        outboxEvent.EventType,
+		TenantID:    outboxEvent.TenantID,
+		AggregateID: outboxEvent.AggregateID,
+		Payload:     outboxEvent.Payload,
+		OccurredAt:  outboxEvent.CreatedAt,
+	}
+}
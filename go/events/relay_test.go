@@ -0,0 +1,231 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	mock_repository "crud_app/repository/mocks_repository"
+	"crud_app/tenant"
+)
+
+var (
+	testNow     = time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+	testPending = []dto.OutboxEvent{
+		{ID: 1, TenantID: "acme", EventType: UserCreated, AggregateID: 7, Payload: json.RawMessage(`{"id":7}`)},
+		{ID: 2, TenantID: "acme", EventType: UserDeleted, AggregateID: 8, Payload: json.RawMessage(`{"id":8}`), Attempts: 2},
+	}
+	errPublish = errors.New("broker unavailable")
+)
+
+func newTestRelay(ctrl *gomock.Controller, publisher EventPublisher) (*Relay, *mock_repository.MockOutboxRepo) {
+	mockRepo := mock_repository.NewMockOutboxRepo(ctrl)
+	mockTx := mock_repository.NewMockTransactor(ctrl)
+	mockTx.EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	relay := NewRelay(mockRepo, mockTx, publisher, NewBroker(1), RelayConfig{
+		BatchSize:    10,
+		PollInterval: time.Second,
+		MinBackoff:   time.Second,
+		MaxBackoff:   time.Minute,
+	})
+	relay.now = func() time.Time { return testNow }
+
+	return relay, mockRepo
+}
+
+// expectSequences numbers the claimed events from 11 on.
+func expectSequences(mr *mock_repository.MockOutboxRepo) {
+	mr.EXPECT().
+		AssignSequences(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, events []dto.OutboxEvent) error {
+			for i := range events {
+				sequence := uint64(11 + i)
+				events[i].Sequence = &sequence
+			}
+			return nil
+		})
+}
+
+func TestRelay_RelayBatch(t *testing.T) {
+	type testCase struct {
+		name       string
+		fail       func(Event) error
+		setupMocks func(*mock_repository.MockOutboxRepo)
+		published  []uint64
+		wantError  bool
+	}
+
+	cases := []testCase{
+		{
+			name: "all published",
+			setupMocks: func(mr *mock_repository.MockOutboxRepo) {
+				mr.EXPECT().
+					ClaimPending(gomock.Any(), testNow, 10).
+					Return(slices.Clone(testPending), nil)
+				expectSequences(mr)
+				mr.EXPECT().
+					MarkPublished(gomock.Any(), uint64(1), testNow).
+					Return(nil)
+				mr.EXPECT().
+					MarkPublished(gomock.Any(), uint64(2), testNow).
+					Return(nil)
+			},
+			published: []uint64{11, 12},
+		}, {
+			name: "failed event is rescheduled with backoff",
+			fail: func(e Event) error {
+				if e.ID == 12 {
+					return errPublish
+				}
+				return nil
+			},
+			setupMocks: func(mr *mock_repository.MockOutboxRepo) {
+				mr.EXPECT().
+					ClaimPending(gomock.Any(), testNow, 10).
+					Return(slices.Clone(testPending), nil)
+				expectSequences(mr)
+				mr.EXPECT().
+					MarkPublished(gomock.Any(), uint64(1), testNow).
+					Return(nil)
+				mr.EXPECT().
+					MarkFailed(gomock.Any(), uint64(2), testNow.Add(4*time.Second), errPublish.Error()).
+					Return(nil)
+			},
+			published: []uint64{11},
+		}, {
+			name: "error sequencing events",
+			setupMocks: func(mr *mock_repository.MockOutboxRepo) {
+				mr.EXPECT().
+					ClaimPending(gomock.Any(), testNow, 10).
+					Return(slices.Clone(testPending), nil)
+				mr.EXPECT().
+					AssignSequences(gomock.Any(), gomock.Any()).
+					Return(errors.New("db down"))
+			},
+			wantError: true,
+		}, {
+			name: "error claiming events",
+			setupMocks: func(mr *mock_repository.MockOutboxRepo) {
+				mr.EXPECT().
+					ClaimPending(gomock.Any(), testNow, 10).
+					Return(nil, errors.New("db down"))
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			publisher := NewMemoryPublisher(tc.fail)
+			relay, mockRepo := newTestRelay(ctrl, publisher)
+			tc.setupMocks(mockRepo)
+
+			_, err := relay.RelayBatch(context.Background())
+
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var published []uint64
+			for _, e := range publisher.Events() {
+				published = append(published, e.ID)
+			}
+			require.Equal(t, tc.published, published)
+		})
+	}
+}
+
+// TestRelay_FollowBatch relays with the in-memory outbox and follows what it
+// sequenced into the broker.
+func TestRelay_FollowBatch(t *testing.T) {
+	ctx := tenant.WithID(t.Context(), "acme")
+	outboxRepo := repository.NewMemoryOutboxRepo()
+	broker := NewBroker(10)
+	relay := NewRelay(outboxRepo, repository.NewMemoryTransactor(), NewMemoryPublisher(nil), broker, RelayConfig{BatchSize: 2})
+
+	record := func(eventType string, aggregateID uint) {
+		t.Helper()
+		require.NoError(t, outboxRepo.Add(ctx, &dto.OutboxEvent{EventType: eventType, AggregateID: aggregateID, Payload: json.RawMessage(`{}`)}))
+	}
+
+	// What was sequenced before the relay started following is left to the
+	// log.
+	record(UserCreated, 1)
+	_, err := relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	n, err := relay.FollowBatch(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	subscription := broker.Subscribe("acme", nil)
+	defer subscription.Close()
+
+	record(UserUpdated, 1)
+	record(UserCreated, 2)
+	record(UserDeleted, 1)
+	_, err = relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	_, err = relay.RelayBatch(ctx)
+	require.NoError(t, err)
+
+	n, err = relay.FollowBatch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	n, err = relay.FollowBatch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = relay.FollowBatch(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	var got []uint64
+	for range 3 {
+		got = append(got, (<-subscription.Events()).ID)
+	}
+	require.Equal(t, []uint64{2, 3, 4}, got)
+
+	changes, err := outboxRepo.ListSince(ctx, 2, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, UserCreated, changes[0].EventType)
+	require.Equal(t, UserDeleted, changes[1].EventType)
+}
+
+func TestRelay_Notify(t *testing.T) {
+	relay := NewRelay(nil, nil, nil, nil, DefaultRelayConfig())
+
+	relay.Notify()
+	relay.Notify()
+
+	require.Len(t, relay.wake, 1)
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := &Relay{cfg: RelayConfig{MinBackoff: time.Second, MaxBackoff: time.Minute}}
+
+	require.Equal(t, time.Second, relay.backoff(1))
+	require.Equal(t, 2*time.Second, relay.backoff(2))
+	require.Equal(t, 32*time.Second, relay.backoff(6))
+	require.Equal(t, time.Minute, relay.backoff(7))
+	require.Equal(t, time.Minute, relay.backoff(50))
+}
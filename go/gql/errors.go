@@ -0,0 +1,77 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"crud_app/errkind"
+	"crud_app/service"
+)
+
+const (
+	codeBadUserInput     string = "BAD_USER_INPUT"
+	codeNotFound         string = "NOT_FOUND"
+	codeUnauthenticated  string = "UNAUTHENTICATED"
+	codeForbidden        string = "FORBIDDEN"
+	codeUnavailable      string = "SERVICE_UNAVAILABLE"
+	codeCanceled         string = "CANCELED"
+	codeDeadlineExceeded string = "DEADLINE_EXCEEDED"
+	codeInternal         string = "INTERNAL_SERVER_ERROR"
+	codeQueryTooComplex  string = "QUERY_TOO_COMPLEX"
+)
+
+var errInvalidRequest = errkind.ErrInvalidRequest
+
+// Error is reported in the GraphQL errors list with its code, and the
+// validation rule when there is one, under extensions.
+type Error struct {
+	Message string
+	Code    string
+	Rule    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.Code}
+	if e.Rule != "" {
+		extensions["rule"] = e.Rule
+	}
+
+	return extensions
+}
+
+// errorFromService reports err with the GraphQL code of its errkind.
+func errorFromService(ctx context.Context, err error) error {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		code := codeBadUserInput
+		if errkind.Of(err) == errkind.NotFound {
+			code = codeNotFound
+		}
+		return &Error{Message: validationErr.Message, Code: code, Rule: validationErr.Rule}
+	}
+
+	switch errkind.Of(err) {
+	case errkind.Unauthenticated:
+		return &Error{Message: err.Error(), Code: codeUnauthenticated}
+	case errkind.Forbidden:
+		return &Error{Message: err.Error(), Code: codeForbidden}
+	case errkind.Invalid:
+		return &Error{Message: err.Error(), Code: codeBadUserInput}
+	case errkind.NotFound:
+		return &Error{Message: err.Error(), Code: codeNotFound}
+	case errkind.Unavailable:
+		return &Error{Message: err.Error(), Code: codeUnavailable}
+	case errkind.Canceled:
+		return &Error{Message: err.Error(), Code: codeCanceled}
+	case errkind.DeadlineExceeded:
+		return &Error{Message: err.Error(), Code: codeDeadlineExceeded}
+	default:
+		slog.ErrorContext(ctx, "graphql resolver failed", slog.Any("error", err))
+		return &Error{Message: "internal error", Code: codeInternal}
+	}
+}
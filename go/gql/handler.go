@@ -0,0 +1,114 @@
+package gql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+const maxRequestBytes int64 = 1 << 20
+
+type request struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// NewHandler serves GraphQL over HTTP: POST with a JSON body, or GET with
+// query, variables and operationName parameters for queries only.
+func NewHandler(schema graphql.Schema) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+
+		switch r.Method {
+		case http.MethodPost:
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+				writeResult(w, http.StatusBadRequest, errorResult("invalid JSON format"))
+				return
+			}
+		case http.MethodGet:
+			query := r.URL.Query()
+			req.Query = query.Get("query")
+			req.OperationName = query.Get("operationName")
+			if v := query.Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					writeResult(w, http.StatusBadRequest, errorResult("variables must be a JSON object"))
+					return
+				}
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeResult(w, http.StatusMethodNotAllowed, errorResult("method not allowed"))
+			return
+		}
+
+		if req.Query == "" {
+			writeResult(w, http.StatusBadRequest, errorResult("query is required"))
+			return
+		}
+
+		// Unparseable queries are left to graphql.Do to report.
+		if document, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+			if r.Method == http.MethodGet && isMutation(document, req.OperationName) {
+				w.Header().Set("Allow", "POST")
+				writeResult(w, http.StatusMethodNotAllowed, errorResult("mutations require POST"))
+				return
+			}
+			if err := checkLimits(document, maxQueryDepth, maxAliases); err != nil {
+				result := errorResult(err.Error())
+				result.Errors[0].Extensions = map[string]any{"code": codeQueryTooComplex}
+				writeResult(w, http.StatusBadRequest, result)
+				return
+			}
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        r.Context(),
+		})
+
+		writeResult(w, http.StatusOK, result)
+	})
+}
+
+// isMutation reports whether the operation to run is a mutation, so GET
+// requests, which caches and prefetchers treat as safe, cannot change data.
+func isMutation(document *ast.Document, operationName string) bool {
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+		if operation.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+
+	return false
+}
+
+func errorResult(message string) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
+
+func writeResult(w http.ResponseWriter, status int, result *graphql.Result) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		body = []byte(`{"errors":[{"message":"internal error"}]}`)
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
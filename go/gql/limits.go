@@ -0,0 +1,110 @@
+package gql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// The limits every operation is checked against before it runs, so a small
+// request cannot make the resolvers do an unbounded amount of work. The depth
+// leaves room for the introspection query of GraphiQL and other tools.
+const (
+	maxQueryDepth int = 15
+	maxAliases    int = 30
+)
+
+// checkLimits reports the first operation of document that is nested deeper
+// than maxDepth or uses more than maxAliases aliases. Fragments are counted
+// every time they are spread, and fragments that spread themselves are left
+// to validation to report.
+func checkLimits(document *ast.Document, maxDepth, maxAliases int) error {
+	c := &limitChecker{
+		fragments: map[string]*ast.FragmentDefinition{},
+		measured:  map[string]selectionCost{},
+		limit:     maxAliases + 1,
+	}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			c.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		cost := c.selectionSet(operation.SelectionSet)
+		if cost.depth > maxDepth {
+			return fmt.Errorf("query is nested %d levels deep, the limit is %d", cost.depth, maxDepth)
+		}
+		if cost.aliases > maxAliases {
+			return fmt.Errorf("query uses more than %d aliases", maxAliases)
+		}
+	}
+
+	return nil
+}
+
+type selectionCost struct {
+	depth   int
+	aliases int
+}
+
+// limitChecker measures every fragment once, so spreading the same fragment
+// many times, however deeply, takes linear time to check.
+type limitChecker struct {
+	fragments map[string]*ast.FragmentDefinition
+	measured  map[string]selectionCost
+	// limit caps the alias count, which spreads could otherwise grow
+	// exponentially.
+	limit int
+}
+
+func (c *limitChecker) selectionSet(set *ast.SelectionSet) selectionCost {
+	var cost selectionCost
+	if set == nil {
+		return cost
+	}
+
+	for _, selection := range set.Selections {
+		var next selectionCost
+		switch selection := selection.(type) {
+		case *ast.Field:
+			next = c.selectionSet(selection.SelectionSet)
+			next.depth++
+			if selection.Alias != nil {
+				next.aliases++
+			}
+		case *ast.InlineFragment:
+			next = c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			next = c.fragment(selection.Name.Value)
+		}
+
+		cost.depth = max(cost.depth, next.depth)
+		cost.aliases = min(cost.aliases+next.aliases, c.limit)
+	}
+
+	return cost
+}
+
+func (c *limitChecker) fragment(name string) selectionCost {
+	if cost, ok := c.measured[name]; ok {
+		return cost
+	}
+	fragment, ok := c.fragments[name]
+	if !ok {
+		return selectionCost{}
+	}
+
+	// A cycle comes back here before the fragment is measured; counting it
+	// as empty ends the walk.
+	c.measured[name] = selectionCost{}
+	cost := c.selectionSet(fragment.SelectionSet)
+	c.measured[name] = cost
+
+	return cost
+}
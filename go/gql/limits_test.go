@@ -0,0 +1,112 @@
+package gql
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mock_service "crud_app/service/mocks_service"
+)
+
+func TestCheckLimits(t *testing.T) {
+	type testCase struct {
+		name      string
+		query     string
+		wantError string
+	}
+
+	// bomb spreads each fragment twice into the next, so expanding it
+	// naively would visit 2^20 aliases.
+	var bomb strings.Builder
+	bomb.WriteString("{ ...f0 } fragment f20 on Query { a: user(id: 1) { id } }")
+	for i := range 20 {
+		fmt.Fprintf(&bomb, " fragment f%d on Query { ...f%d ...f%d }", i, i+1, i+1)
+	}
+
+	cases := []testCase{{
+		name:  "within the limits",
+		query: `{ a: user(id: 1) { name } b: user(id: 2) { name } }`,
+	}, {
+		name:      "too deep",
+		query:     `{ a { b { c { d { e } } } } }`,
+		wantError: "query is nested 5 levels deep, the limit is 4",
+	}, {
+		name:      "too deep through fragments",
+		query:     `{ a { ...F } } fragment F on T { b { ... on T { c { d { e } } } } }`,
+		wantError: "query is nested 5 levels deep, the limit is 4",
+	}, {
+		name:      "too many aliases",
+		query:     `{ a: x b: x c: x d: x }`,
+		wantError: "query uses more than 3 aliases",
+	}, {
+		name:      "aliases of a fragment count every time it is spread",
+		query:     `{ ...F ...F } fragment F on T { a: x b: x }`,
+		wantError: "query uses more than 3 aliases",
+	}, {
+		name:      "every operation is checked",
+		query:     `query A { x } query B { a { b { c { d { e } } } } }`,
+		wantError: "query is nested 5 levels deep, the limit is 4",
+	}, {
+		name:      "exponential fragments",
+		query:     bomb.String(),
+		wantError: "query uses more than 3 aliases",
+	}, {
+		name:  "fragment cycles are left to validation",
+		query: `{ ...F } fragment F on T { a { ...F } }`,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			document, err := parser.Parse(parser.ParseParams{Source: tc.query})
+			require.NoError(t, err)
+
+			err = checkLimits(document, 4, 3)
+
+			if tc.wantError != "" {
+				require.EqualError(t, err, tc.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandler_Limits(t *testing.T) {
+	aliases := make([]string, maxAliases+1)
+	for i := range aliases {
+		aliases[i] = fmt.Sprintf("u%d: user(id: 1) { name }", i)
+	}
+
+	t.Run("the introspection query runs", func(t *testing.T) {
+		code, resp := execute(t, mock_service.NewMockUser(gomock.NewController(t)), testutil.IntrospectionQuery, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, resp.Errors)
+	})
+
+	type testCase struct {
+		name  string
+		query string
+	}
+
+	cases := []testCase{
+		{name: "too deep", query: `{ __schema { types { fields { type { fields { type { fields { type { fields { type { fields { type { fields { type { fields { type { name } } } } } } } } } } } } } } } } }`},
+		{name: "too many aliases", query: "{ " + strings.Join(aliases, " ") + " }"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The mock expects no call: nothing runs.
+			code, resp := execute(t, mock_service.NewMockUser(gomock.NewController(t)), tc.query, nil)
+			require.Equal(t, http.StatusBadRequest, code)
+			require.Len(t, resp.Errors, 1)
+			require.Equal(t, codeQueryTooComplex, resp.Errors[0].Extensions["code"])
+			require.Nil(t, resp.Data)
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package gql
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	"crud_app/service"
+)
+
+const (
+	defaultPageSize int = 50
+	maxPageSize     int = 500
+	cursorPrefix        = "user:"
+)
+
+type userConnection struct {
+	users      []dto.User
+	totalCount int
+	hasNext    bool
+	hasPrev    bool
+}
+
+// NewSchema builds the users schema. Every resolver delegates to userService,
+// so authorization, validation and tenancy behave as on the REST routes.
+func NewSchema(userService service.User) (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":        userField(graphql.NewNonNull(graphql.ID), func(u *dto.User) any { return strconv.FormatUint(uint64(u.ID), 10) }),
+			"name":      userField(graphql.NewNonNull(graphql.String), func(u *dto.User) any { return u.Name }),
+			"age":       userField(graphql.NewNonNull(graphql.Int), func(u *dto.User) any { return u.Age }),
+			"createdAt": userField(graphql.NewNonNull(graphql.DateTime), func(u *dto.User) any { return u.CreatedAt }),
+			"updatedAt": userField(graphql.NewNonNull(graphql.DateTime), func(u *dto.User) any { return u.UpdatedAt }),
+		},
+	})
+
+	userEdgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "UserEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return encodeCursor(p.Source.(*dto.User).ID), nil
+				},
+			},
+			"node": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     connectionField(graphql.NewNonNull(graphql.Boolean), func(c *userConnection) any { return c.hasNext }),
+			"hasPreviousPage": connectionField(graphql.NewNonNull(graphql.Boolean), func(c *userConnection) any { return c.hasPrev }),
+			"startCursor": connectionField(graphql.String, func(c *userConnection) any {
+				if len(c.users) == 0 {
+					return nil
+				}
+				return encodeCursor(c.users[0].ID)
+			}),
+			"endCursor": connectionField(graphql.String, func(c *userConnection) any {
+				if len(c.users) == 0 {
+					return nil
+				}
+				return encodeCursor(c.users[len(c.users)-1].ID)
+			}),
+		},
+	})
+
+	userConnectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "UserConnection",
+		Fields: graphql.Fields{
+			"edges":      connectionField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userEdgeType))), connectionUsers),
+			"nodes":      connectionField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))), connectionUsers),
+			"pageInfo":   connectionField(graphql.NewNonNull(pageInfoType), func(c *userConnection) any { return c }),
+			"totalCount": connectionField(graphql.NewNonNull(graphql.Int), func(c *userConnection) any { return c.totalCount }),
+		},
+	})
+
+	userFilterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UserFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"nameContains": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"minAge":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"maxAge":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+
+	userInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UserInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"age":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(userConnectionType),
+				Args: graphql.FieldConfigArgument{
+					"first":  &graphql.ArgumentConfig{Type: graphql.Int},
+					"after":  &graphql.ArgumentConfig{Type: graphql.String},
+					"filter": &graphql.ArgumentConfig{Type: userFilterType},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					connection, err := resolveUsers(p, userService)
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+					return connection, nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+
+					user, err := userService.Get(p.Context, id)
+					if errors.Is(err, repository.ErrNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+					return user, nil
+				},
+			},
+		},
+	})
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createUser": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(userInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					user, err := userService.Create(p.Context, userFromInput(p.Args["input"]))
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+					return user, nil
+				},
+			},
+			"updateUser": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(userInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+
+					if err := userService.Update(p.Context, userFromInput(p.Args["input"]), id); err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+
+					user, err := userService.Get(p.Context, id)
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+					return user, nil
+				},
+			},
+			"deleteUser": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+
+					if err := userService.Delete(p.Context, id); err != nil {
+						return nil, errorFromService(p.Context, err)
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	})
+}
+
+// resolveUsers pages with cursors over the ID order service.User finds users
+// in, so a page boundary does not shift when users are created or deleted.
+// Paging and filtering happen in the repository; only a page after a cursor
+// costs a second query, which counts the matches before it.
+func resolveUsers(p graphql.ResolveParams, userService service.User) (*userConnection, error) {
+	first := defaultPageSize
+	if v, ok := p.Args["first"].(int); ok {
+		if v < 1 || v > maxPageSize {
+			return nil, fmt.Errorf("%w: first must be between 1 and %d", errInvalidRequest, maxPageSize)
+		}
+		first = v
+	}
+
+	var after uint
+	if v, ok := p.Args["after"].(string); ok && v != "" {
+		id, err := decodeCursor(v)
+		if err != nil {
+			return nil, err
+		}
+		after = id
+	}
+
+	var conditions []dto.Condition
+	if v, ok := p.Args["filter"].(map[string]any); ok {
+		if nameContains, ok := v["nameContains"].(string); ok && nameContains != "" {
+			conditions = append(conditions, dto.Condition{Field: "name", Op: dto.OpContains, Value: nameContains})
+		}
+		if minAge, ok := v["minAge"].(int); ok {
+			conditions = append(conditions, dto.Condition{Field: "age", Op: dto.OpGreaterOrEqual, Value: strconv.Itoa(minAge)})
+		}
+		if maxAge, ok := v["maxAge"].(int); ok {
+			conditions = append(conditions, dto.Condition{Field: "age", Op: dto.OpLessOrEqual, Value: strconv.Itoa(maxAge)})
+		}
+	}
+	cursor := strconv.FormatUint(uint64(after), 10)
+
+	// One more than first tells whether there is a next page.
+	users, total, err := userService.Find(p.Context, dto.ListQuery{
+		PageRequest: dto.PageRequest{Limit: first + 1},
+		Conditions:  append(slices.Clip(conditions), dto.Condition{Field: "id", Op: dto.OpGreater, Value: cursor}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &userConnection{totalCount: int(total)}
+	if len(users) > first {
+		users, connection.hasNext = users[:first], true
+	}
+	connection.users = users
+
+	if after > 0 {
+		_, before, err := userService.Find(p.Context, dto.ListQuery{
+			PageRequest: dto.PageRequest{Limit: 1},
+			Conditions:  append(slices.Clip(conditions), dto.Condition{Field: "id", Op: dto.OpLessOrEqual, Value: cursor}),
+		})
+		if err != nil {
+			return nil, err
+		}
+		connection.totalCount += int(before)
+		connection.hasPrev = before > 0
+	}
+
+	return connection, nil
+}
+
+func userField(fieldType graphql.Output, value func(*dto.User) any) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return value(p.Source.(*dto.User)), nil
+		},
+	}
+}
+
+func connectionField(fieldType graphql.Output, value func(*userConnection) any) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return value(p.Source.(*userConnection)), nil
+		},
+	}
+}
+
+func connectionUsers(c *userConnection) any {
+	users := make([]*dto.User, len(c.users))
+	for i := range c.users {
+		users[i] = &c.users[i]
+	}
+
+	return users
+}
+
+func userFromInput(input any) *dto.User {
+	fields, _ := input.(map[string]any)
+	name, _ := fields["name"].(string)
+	age, _ := fields["age"].(int)
+
+	return &dto.User{Name: name, Age: age}
+}
+
+func parseID(v any) (uint, error) {
+	s, _ := v.(string)
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: id must be a positive integer", errInvalidRequest)
+	}
+
+	return uint(id), nil
+}
+
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, fmt.Errorf("%w: malformed cursor", errInvalidRequest)
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(string(raw), cursorPrefix), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed cursor", errInvalidRequest)
+	}
+
+	return uint(id), nil
+}
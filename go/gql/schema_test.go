@@ -0,0 +1,269 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	"crud_app/service"
+	mock_service "crud_app/service/mocks_service"
+)
+
+var testUsers = []dto.User{
+	{ID: 1, Name: "Ann", Age: 34},
+	{ID: 2, Name: "Bob", Age: 17},
+	{ID: 3, Name: "Annette", Age: 61},
+	{ID: 4, Name: "Dan", Age: 40},
+}
+
+type response struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, userService service.User, query string, variables map[string]any) (int, response) {
+	t.Helper()
+
+	schema, err := NewSchema(userService)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	NewHandler(schema).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+	var resp response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+	return rec.Code, resp
+}
+
+func TestSchema_Users(t *testing.T) {
+	type testCase struct {
+		name       string
+		args       string
+		setupMocks func(*mock_service.MockUser)
+		wantNodes  []any
+		wantNext   bool
+		wantPrev   bool
+		wantTotal  float64
+		wantCode   string
+	}
+
+	afterID := func(id string) dto.Condition {
+		return dto.Condition{Field: "id", Op: dto.OpGreater, Value: id}
+	}
+
+	cases := []testCase{
+		{
+			name: "first page",
+			args: `first: 2`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Find(gomock.Any(), dto.ListQuery{PageRequest: dto.PageRequest{Limit: 3}, Conditions: []dto.Condition{afterID("0")}}).
+					Return(testUsers[:3], int64(4), nil)
+			},
+			wantNodes: []any{
+				map[string]any{"id": "1", "name": "Ann"},
+				map[string]any{"id": "2", "name": "Bob"},
+			},
+			wantNext:  true,
+			wantTotal: 4,
+		}, {
+			name: "after cursor",
+			args: `first: 2, after: "` + encodeCursor(2) + `"`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Find(gomock.Any(), dto.ListQuery{PageRequest: dto.PageRequest{Limit: 3}, Conditions: []dto.Condition{afterID("2")}}).
+					Return(testUsers[2:], int64(2), nil)
+				ms.EXPECT().
+					Find(gomock.Any(), dto.ListQuery{PageRequest: dto.PageRequest{Limit: 1}, Conditions: []dto.Condition{{Field: "id", Op: dto.OpLessOrEqual, Value: "2"}}}).
+					Return(testUsers[:1], int64(2), nil)
+			},
+			wantNodes: []any{
+				map[string]any{"id": "3", "name": "Annette"},
+				map[string]any{"id": "4", "name": "Dan"},
+			},
+			wantPrev:  true,
+			wantTotal: 4,
+		}, {
+			name: "filtered",
+			args: `filter: {nameContains: "ann", minAge: 18, maxAge: 60}`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Find(gomock.Any(), dto.ListQuery{PageRequest: dto.PageRequest{Limit: 51}, Conditions: []dto.Condition{
+						{Field: "name", Op: dto.OpContains, Value: "ann"},
+						{Field: "age", Op: dto.OpGreaterOrEqual, Value: "18"},
+						{Field: "age", Op: dto.OpLessOrEqual, Value: "60"},
+						afterID("0"),
+					}}).
+					Return(testUsers[:1], int64(1), nil)
+			},
+			wantNodes: []any{
+				map[string]any{"id": "1", "name": "Ann"},
+			},
+			wantTotal: 1,
+		}, {
+			name:       "first out of range",
+			args:       `first: 501`,
+			setupMocks: func(ms *mock_service.MockUser) {},
+			wantCode:   codeBadUserInput,
+		}, {
+			name: "canceled",
+			args: `first: 2`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Find(gomock.Any(), gomock.Any()).Return(nil, int64(0), context.Canceled)
+			},
+			wantCode: codeCanceled,
+		}, {
+			name: "deadline exceeded",
+			args: `first: 2`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Find(gomock.Any(), gomock.Any()).Return(nil, int64(0), fmt.Errorf("find: %w", context.DeadlineExceeded))
+			},
+			wantCode: codeDeadlineExceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userService := mock_service.NewMockUser(ctrl)
+			tc.setupMocks(userService)
+
+			query := `{ users(` + tc.args + `) { nodes { id name } totalCount pageInfo { hasNextPage hasPreviousPage } } }`
+			status, resp := execute(t, userService, query, nil)
+			require.Equal(t, http.StatusOK, status)
+			if tc.wantCode != "" {
+				require.Len(t, resp.Errors, 1)
+				require.Equal(t, tc.wantCode, resp.Errors[0].Extensions["code"])
+				return
+			}
+			require.Empty(t, resp.Errors)
+
+			users := resp.Data["users"].(map[string]any)
+			require.Equal(t, tc.wantNodes, users["nodes"])
+			require.Equal(t, tc.wantTotal, users["totalCount"])
+			pageInfo := users["pageInfo"].(map[string]any)
+			require.Equal(t, tc.wantNext, pageInfo["hasNextPage"])
+			require.Equal(t, tc.wantPrev, pageInfo["hasPreviousPage"])
+		})
+	}
+}
+
+func TestSchema_User(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userService := mock_service.NewMockUser(ctrl)
+	userService.EXPECT().Get(gomock.Any(), uint(1)).Return(&testUsers[0], nil)
+	userService.EXPECT().Get(gomock.Any(), uint(9)).Return(nil, repository.ErrNotFound)
+
+	_, resp := execute(t, userService, `{ a: user(id: 1) { name age } b: user(id: 9) { name } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.Equal(t, map[string]any{"name": "Ann", "age": float64(34)}, resp.Data["a"])
+	require.Nil(t, resp.Data["b"])
+}
+
+func TestSchema_Mutations(t *testing.T) {
+	type testCase struct {
+		name       string
+		query      string
+		variables  map[string]any
+		setupMocks func(*mock_service.MockUser)
+		wantData   map[string]any
+		wantCode   string
+		wantRule   string
+	}
+
+	cases := []testCase{
+		{
+			name:      "create",
+			query:     `mutation($input: UserInput!) { createUser(input: $input) { id name } }`,
+			variables: map[string]any{"input": map[string]any{"name": "Eve", "age": 28}},
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Create(gomock.Any(), &dto.User{Name: "Eve", Age: 28}).
+					Return(&dto.User{ID: 5, Name: "Eve", Age: 28}, nil)
+			},
+			wantData: map[string]any{"createUser": map[string]any{"id": "5", "name": "Eve"}},
+		}, {
+			name:  "create fails validation",
+			query: `mutation { createUser(input: {name: "E", age: 28}) { id } }`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(nil, &service.ValidationError{Rule: "name_min_length", Message: "name must be at least 2 characters long"})
+			},
+			wantCode: codeBadUserInput,
+			wantRule: "name_min_length",
+		}, {
+			name:  "update returns the stored user",
+			query: `mutation { updateUser(id: "1", input: {name: "Ann", age: 35}) { age } }`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Update(gomock.Any(), &dto.User{Name: "Ann", Age: 35}, uint(1)).Return(nil)
+				ms.EXPECT().Get(gomock.Any(), uint(1)).Return(&dto.User{ID: 1, Name: "Ann", Age: 35}, nil)
+			},
+			wantData: map[string]any{"updateUser": map[string]any{"age": float64(35)}},
+		}, {
+			name:  "delete missing user",
+			query: `mutation { deleteUser(id: "9") }`,
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Delete(gomock.Any(), uint(9)).
+					Return(&service.ValidationError{Rule: "user_exists", Message: "user with ID 9 not found", Err: repository.ErrNotFound})
+			},
+			wantCode: codeNotFound,
+			wantRule: "user_exists",
+		}, {
+			name:       "invalid id",
+			query:      `mutation { deleteUser(id: "abc") }`,
+			setupMocks: func(ms *mock_service.MockUser) {},
+			wantCode:   codeBadUserInput,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userService := mock_service.NewMockUser(ctrl)
+			tc.setupMocks(userService)
+
+			_, resp := execute(t, userService, tc.query, tc.variables)
+			if tc.wantCode == "" {
+				require.Empty(t, resp.Errors)
+				require.Equal(t, tc.wantData, resp.Data)
+				return
+			}
+
+			require.Len(t, resp.Errors, 1)
+			require.Equal(t, tc.wantCode, resp.Errors[0].Extensions["code"])
+			if tc.wantRule != "" {
+				require.Equal(t, tc.wantRule, resp.Errors[0].Extensions["rule"])
+			}
+		})
+	}
+}
+
+func TestHandler_GetRejectsMutations(t *testing.T) {
+	schema, err := NewSchema(mock_service.NewMockUser(gomock.NewController(t)))
+	require.NoError(t, err)
+
+	target := "/graphql?query=" + url.QueryEscape(`mutation { deleteUser(id: "1") }`)
+	rec := httptest.NewRecorder()
+	NewHandler(schema).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"crud_app/repository"
+)
+
+type LeaseConfig struct {
+	// Owner names this process on the jobs it runs. It must differ between
+	// processes running at the same time and should stay the same across
+	// restarts, so a restarted process can fail what it was running at once.
+	Owner string
+	// RenewInterval is how often the jobs of Owner are renewed and the
+	// expired jobs of other owners failed.
+	RenewInterval time.Duration
+	// TTL is how long a job may go without being renewed before its owner is
+	// taken to be gone.
+	TTL time.Duration
+}
+
+func DefaultLeaseConfig() LeaseConfig {
+	hostname, _ := os.Hostname()
+
+	return LeaseConfig{
+		Owner:         cmp.Or(hostname, "localhost"),
+		RenewInterval: 15 * time.Second,
+		TTL:           2 * time.Minute,
+	}
+}
+
+// Lease keeps the unfinished jobs of this process alive and fails those of
+// processes that stopped renewing theirs. Jobs run in the process that
+// created them, so a job whose owner crashed would otherwise stay pending or
+// running forever.
+type Lease struct {
+	jobRepo repository.JobRepo
+	cfg     LeaseConfig
+	now     func() time.Time
+}
+
+func NewLease(jobRepo repository.JobRepo, cfg LeaseConfig) *Lease {
+	return &Lease{jobRepo: jobRepo, cfg: cfg, now: time.Now}
+}
+
+func (l *Lease) Owner() string {
+	return l.cfg.Owner
+}
+
+// Recover fails the jobs this owner left unfinished when it last stopped.
+// It must run before the owner starts new jobs.
+func (l *Lease) Recover(ctx context.Context) (int64, error) {
+	return l.jobRepo.FailOwned(ctx, l.cfg.Owner, "interrupted by a restart", l.now())
+}
+
+// Renew renews the jobs of this owner, then fails the jobs of owners that
+// have not renewed theirs within the TTL.
+func (l *Lease) Renew(ctx context.Context) (int64, error) {
+	now := l.now()
+	if err := l.jobRepo.Renew(ctx, l.cfg.Owner, now); err != nil {
+		return 0, err
+	}
+
+	return l.jobRepo.FailExpired(ctx, now.Add(-l.cfg.TTL), "its process stopped", now)
+}
+
+func (l *Lease) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.cfg.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := l.Renew(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "job lease renewal failed", slog.Any("error", err))
+		}
+		if n > 0 {
+			slog.WarnContext(ctx, "expired jobs marked as failed", slog.Int64("count", n))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+func TestLease(t *testing.T) {
+	jobRepo := repository.NewMemoryJobRepo()
+	ctx := tenant.WithID(context.Background(), "acme")
+	start := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	create := func(owner string) uint64 {
+		job, err := jobRepo.Create(ctx, &dto.Job{Kind: dto.JobKindUserImport, Status: dto.JobStatusRunning, Owner: owner, RenewedAt: &start})
+		require.NoError(t, err)
+		return job.ID
+	}
+	status := func(id uint64) string {
+		job, err := jobRepo.Get(ctx, id)
+		require.NoError(t, err)
+		return job.Status
+	}
+
+	previous := create("node-1")
+	other := create("node-2")
+
+	now := start
+	lease := NewLease(jobRepo, LeaseConfig{Owner: "node-1", RenewInterval: time.Second, TTL: time.Minute})
+	lease.now = func() time.Time { return now }
+
+	// node-1 restarted: what it ran before cannot finish any more.
+	n, err := lease.Recover(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	require.Equal(t, dto.JobStatusFailed, status(previous))
+	require.Equal(t, dto.JobStatusRunning, status(other))
+
+	current := create("node-1")
+
+	// Within the TTL nobody is failed.
+	now = start.Add(30 * time.Second)
+	n, err = lease.Renew(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// node-2 stopped renewing; node-1 keeps its job alive.
+	now = start.Add(90 * time.Second)
+	n, err = lease.Renew(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	require.Equal(t, dto.JobStatusFailed, status(other))
+	require.Equal(t, dto.JobStatusRunning, status(current))
+}
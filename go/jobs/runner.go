@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrQueueFull = errors.New("job queue is full")
+
+type task struct {
+	ctx context.Context
+	fn  func(ctx context.Context)
+}
+
+// Runner executes background jobs on a fixed number of workers. Jobs see the
+// values of the context they were submitted with, e.g. tenant and principal,
+// but are only canceled when the runner stops.
+type Runner struct {
+	queue   chan task
+	workers int
+}
+
+func NewRunner(workers, queueSize int) *Runner {
+	return &Runner{
+		queue:   make(chan task, queueSize),
+		workers: workers,
+	}
+}
+
+// Submit never blocks; it fails with ErrQueueFull when all workers are busy
+// and the queue is full.
+func (r *Runner) Submit(ctx context.Context, fn func(ctx context.Context)) error {
+	select {
+	case r.queue <- task{ctx: context.WithoutCancel(ctx), fn: fn}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run works off the queue until ctx is done. Jobs still queued then are run
+// with a canceled context so they can release what they hold.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range r.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case t := <-r.queue:
+					r.execute(ctx, t)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for {
+		select {
+		case t := <-r.queue:
+			r.execute(ctx, t)
+		default:
+			return
+		}
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, t task) {
+	t.fn(jobContext{Context: ctx, values: t.ctx})
+}
+
+// jobContext is canceled with the runner but carries the values of the
+// context the job was submitted with.
+type jobContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c jobContext) Value(key any) any {
+	return c.values.Value(key)
+}
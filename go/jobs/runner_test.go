@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestRunner(t *testing.T) {
+	runner := NewRunner(1, 1)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	reqCtx, cancelReq := context.WithCancel(context.WithValue(t.Context(), ctxKey{}, "acme"))
+	require.NoError(t, runner.Submit(reqCtx, func(ctx context.Context) {
+		close(started)
+		<-block
+		if ctx.Value(ctxKey{}) != "acme" {
+			result <- context.Canceled
+			return
+		}
+		result <- ctx.Err()
+	}))
+	cancelReq()
+
+	runCtx, stopRunner := context.WithCancel(t.Context())
+	defer stopRunner()
+	go runner.Run(runCtx)
+	<-started
+
+	require.NoError(t, runner.Submit(t.Context(), func(context.Context) {}))
+	require.ErrorIs(t, runner.Submit(t.Context(), func(context.Context) {}), ErrQueueFull)
+
+	close(block)
+	require.NoError(t, <-result, "a finished request must not cancel its job")
+}
+
+func TestRunner_QueuedJobsRunCanceled(t *testing.T) {
+	runner := NewRunner(2, 2)
+
+	results := make(chan error, 2)
+	for range 2 {
+		require.NoError(t, runner.Submit(t.Context(), func(ctx context.Context) {
+			results <- ctx.Err()
+		}))
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	runner.Run(ctx)
+
+	require.Len(t, results, 2)
+	for range 2 {
+		require.ErrorIs(t, <-results, context.Canceled)
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	type testCase struct {
+		name      string
+		level     string
+		format    string
+		wantError bool
+	}
+
+	cases := []testCase{
+		{name: "defaults", level: "", format: ""},
+		{name: "debug text", level: "debug", format: "text"},
+		{name: "warn json", level: "WARN", format: "JSON"},
+		{name: "invalid level", level: "loud", format: "json", wantError: true},
+		{name: "invalid format", level: "info", format: "xml", wantError: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, err := New(&bytes.Buffer{}, tc.level, tc.format)
+
+			if tc.wantError {
+				require.Error(t, err)
+				require.Nil(t, logger)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, logger)
+			}
+		})
+	}
+}
+
+func TestContextHandler_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.InfoContext(ctx, "hello")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "hello", line["msg"])
+	require.Equal(t, "req-1", line["request_id"])
+}
@@ -1,38 +1,297 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"crud_app/api"
+	"crud_app/auth"
+	"crud_app/cache"
 	"crud_app/config"
+	"crud_app/events"
+	"crud_app/gql"
+	"crud_app/jobs"
+	"crud_app/logging"
+	"crud_app/metrics"
 	"crud_app/repository"
+	"crud_app/rpc"
 	"crud_app/service"
+	"crud_app/tracing"
+	"crud_app/webhooks"
 )
 
 func main() {
-	fmt.Println("Server starting on :8080")
+	storage := flag.String("storage", storageDatabase, "where data is kept: database (DB_DRIVER picks postgres or sqlite), or memory for local development")
+	flag.Parse()
 
-	db, err := config.ConnectDB()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger, err := logging.New(os.Stdout, os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		slog.Error("logger setup failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter: os.Getenv("TRACING_EXPORTER"),
+		FilePath: os.Getenv("TRACING_FILE"),
+	})
+	if err != nil {
+		fatal("tracing setup failed", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	repos, err := newRepositories(*storage)
+	if err != nil {
+		fatal("storage setup failed", err)
+	}
+
+	userCache, userCacheTTL, err := newUserCache()
 	if err != nil {
-		log.Fatal("Database connection failed:", err)
+		fatal("user cache setup failed", err)
 	}
 
 	var userRepo repository.UserRepo
-	userRepo = repository.NewUserRepo(db)
+	userRepo = repos.users
+	if userCache != nil {
+		userRepo = repository.NewUserRepoWithCache(userRepo, userCache, userCacheTTL)
+	}
 
 	var userValidator service.UserValidator
 	userValidator = service.NewUserValidator(userRepo)
+	userValidator = service.NewUserValidatorWithMetrics(userValidator)
+	userValidator = service.NewUserValidatorWithTracing(userValidator)
+
+	policy, err := config.LoadPolicy()
+	if err != nil {
+		fatal("authorization policy failed to load", err)
+	}
+
+	transactor := repos.transactor
+	outboxRepo := repos.outbox
+
+	publisher, err := newEventPublisher()
+	if err != nil {
+		fatal("event publisher setup failed", err)
+	}
+	webhookSubscriptionRepo := repos.webhookSubscriptions
+	webhookDeliveryRepo := repos.webhookDeliveries
+	publisher = events.NewMultiPublisher(publisher, webhooks.NewEnqueuer(webhookSubscriptionRepo, webhookDeliveryRepo))
+
+	broker := events.NewBroker(256)
+	relay := events.NewRelay(outboxRepo, transactor, publisher, broker, events.DefaultRelayConfig())
+	go relay.Run(ctx)
+
+	dispatcher := webhooks.NewDispatcher(webhookSubscriptionRepo, webhookDeliveryRepo, webhooks.NewClient(), webhooks.DefaultDispatcherConfig())
+	go dispatcher.Run(ctx)
+
+	userAuditRepo := repos.userAudit
+	eventRecorder := events.NewOutboxRecorder(outboxRepo, relay.Notify)
 
 	var userService service.User
-	userService = service.NewUser(userValidator, userRepo)
+	userService = service.NewUser(userValidator, userRepo, transactor, userAuditRepo, eventRecorder)
+	userService = service.NewUserWithAuthorization(userService, policy)
+	userService = service.NewUserWithMetrics(userService)
+	userService = service.NewUserWithTracing(userService)
+
+	jobRepo := repos.jobs
+	leaseConfig := jobs.DefaultLeaseConfig()
+	leaseConfig.Owner = cmp.Or(os.Getenv("INSTANCE_ID"), leaseConfig.Owner)
+	lease := jobs.NewLease(jobRepo, leaseConfig)
+	if n, err := lease.Recover(ctx); err != nil {
+		fatal("job recovery failed", err)
+	} else if n > 0 {
+		slog.Warn("unfinished jobs marked as failed", slog.Int64("count", n))
+	}
+	go lease.Run(ctx)
+
+	runner := jobs.NewRunner(2, 16)
+	go runner.Run(ctx)
+
+	importConfig := service.DefaultUserImportConfig()
+	importConfig.SpoolDir = os.Getenv("IMPORT_SPOOL_DIR")
+	importConfig.Owner = lease.Owner()
+	if v := os.Getenv("IMPORT_MAX_UPLOAD_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			fatal("import setup failed", fmt.Errorf("invalid IMPORT_MAX_UPLOAD_SIZE %q", v))
+		}
+		importConfig.MaxUploadSize = n
+	}
+
+	var userImport service.UserImport
+	userImport = service.NewUserImport(userValidator, userRepo, transactor, userAuditRepo, eventRecorder, jobRepo, runner, importConfig)
+	userImport = service.NewUserImportWithAuthorization(userImport, policy)
+
+	var userFeed service.UserFeed
+	userFeed = service.NewUserFeed(outboxRepo, broker)
+	userFeed = service.NewUserFeedWithAuthorization(userFeed, policy)
+
+	var webhookService service.Webhook
+	webhookService = service.NewWebhook(service.NewWebhookValidator(webhookSubscriptionRepo), webhookSubscriptionRepo, webhookDeliveryRepo)
+	webhookService = service.NewWebhookWithAuthorization(webhookService, policy)
+
+	authenticator, err := newAuthenticator(repos.apiKeys)
+	if err != nil {
+		fatal("authentication setup failed", err)
+	}
 
 	r := chi.NewRouter()
+	r.Use(api.RequestID)
+	r.Use(api.Tracing)
+	r.Use(api.AccessLog)
+	r.Use(api.Metrics)
+
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+
+	schema, err := gql.NewSchema(userService)
+	if err != nil {
+		fatal("graphql schema is invalid", err)
+	}
 
-	api.SetUserHandlers(r, userService)
+	if err := setCachePolicies(); err != nil {
+		fatal("cache policies failed to load", err)
+	}
+
+	socketConfig := api.DefaultSocketConfig()
+	if origins := os.Getenv("WS_ALLOWED_ORIGINS"); origins != "" {
+		socketConfig.OriginPatterns = strings.Split(origins, ",")
+	}
+
+	r.Group(func(r chi.Router) {
+		api.UseAuthentication(r, authenticator, policy)
+
+		api.SetUserHandlers(r, userService, userFeed, userImport)
+		api.SetJobHandlers(r, userImport)
+		api.SetWebhookHandlers(r, webhookService)
+		api.SetUserSocketHandlers(r, userFeed, socketConfig)
+		r.Handle("/graphql", gql.NewHandler(schema))
+	})
+
+	grpcAddr := ":" + cmp.Or(os.Getenv("GRPC_PORT"), "9090")
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		fatal("grpc listener failed", err)
+	}
+	grpcServer := rpc.NewServer(userService, authenticator, policy)
+	go func() {
+		slog.Info("grpc server starting", slog.String("addr", grpcAddr))
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			fatal("grpc server stopped", err)
+		}
+	}()
+
+	server := &http.Server{Addr: ":8080", Handler: r}
+	server.RegisterOnShutdown(broker.Close)
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+		grpcServer.GracefulStop()
+	}()
+
+	slog.Info("server starting", slog.String("addr", server.Addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("server stopped", err)
+	}
+	slog.Info("server stopped")
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
+
+// setCachePolicies sends the Cache-Control headers of HTTP_CACHE_POLICIES
+// instead of the default one.
+func setCachePolicies() error {
+	policies, err := config.LoadCachePolicies()
+	if err != nil {
+		return err
+	}
+
+	for route, policy := range policies {
+		api.SetCachePolicy(route, policy)
+	}
+
+	return nil
+}
+
+func newAuthenticator(apiKeyRepo repository.APIKeyRepo) (auth.Authenticator, error) {
+	var jwtVerifier *auth.JWTVerifier
+
+	secret := os.Getenv("AUTH_JWT_HS256_SECRET")
+	jwksFile := os.Getenv("AUTH_JWT_JWKS_FILE")
+	if secret != "" || jwksFile != "" {
+		var err error
+		jwtVerifier, err = auth.NewJWTVerifier(auth.JWTConfig{
+			HMACSecret: []byte(secret),
+			JWKSFile:   jwksFile,
+			Issuer:     os.Getenv("AUTH_JWT_ISSUER"),
+			Audience:   os.Getenv("AUTH_JWT_AUDIENCE"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.NewAuthenticator(jwtVerifier, auth.NewAPIKeys(apiKeyRepo)), nil
+}
+
+func newEventPublisher() (events.EventPublisher, error) {
+	switch kind := os.Getenv("EVENTS_PUBLISHER"); kind {
+	case "", "log":
+		return events.NewLogPublisher(), nil
+	case "file":
+		return events.NewFilePublisher(os.Getenv("EVENTS_FILE"))
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", kind)
+	}
+}
+
+// newUserCache reads USER_CACHE_SIZE, the number of entries to keep, where
+// 0 turns the cache off, and USER_CACHE_TTL. Every instance caches on its
+// own, so another instance's writes show after at most the TTL.
+func newUserCache() (cache.Backend, time.Duration, error) {
+	size := 10000
+	if v := os.Getenv("USER_CACHE_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("invalid USER_CACHE_SIZE %q", v)
+		}
+		size = n
+	}
+
+	ttl := 30 * time.Second
+	if v := os.Getenv("USER_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid USER_CACHE_TTL: %w", err)
+		}
+		ttl = d
+	}
+
+	if size == 0 {
+		return nil, 0, nil
+	}
 
-	http.ListenAndServe(":8080", r)
+	return cache.NewLRU(size), ttl, nil
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/api"
+	"crud_app/dto"
+	mock_service "crud_app/service/mocks_service"
+)
+
+func TestSetCachePolicies(t *testing.T) {
+	t.Setenv("HTTP_CACHE_POLICIES", "/users/{id}=private, max-age=60")
+	require.NoError(t, setCachePolicies())
+
+	ctrl := gomock.NewController(t)
+	userService := mock_service.NewMockUser(ctrl)
+	updatedAt := time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+	userService.EXPECT().
+		Get(gomock.Any(), uint(1)).
+		Return(&dto.User{ID: 1, Name: "John", Age: 10, UpdatedAt: updatedAt}, nil)
+	userService.EXPECT().
+		LastModified(gomock.Any()).
+		Return(updatedAt, nil)
+	userService.EXPECT().
+		Find(gomock.Any(), gomock.Any()).
+		Return([]dto.User{{ID: 1, Name: "John", Age: 10, UpdatedAt: updatedAt}}, int64(1), nil)
+
+	router := chi.NewRouter()
+	api.SetUserHandlers(router, userService, nil, nil)
+
+	for target, want := range map[string]string{
+		"/users/1":    "private, max-age=60",
+		"/users/list": "private, no-cache",
+	} {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code, target)
+		require.Equal(t, want, rec.Header().Get("Cache-Control"), target)
+	}
+}
+
+func TestSetCachePolicies_Invalid(t *testing.T) {
+	t.Setenv("HTTP_CACHE_POLICIES", "/users/{id}")
+
+	require.ErrorContains(t, setCachePolicies(), "invalid HTTP_CACHE_POLICIES")
+}
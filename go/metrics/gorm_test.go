@@ -0,0 +1,110 @@
+package metrics_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"crud_app/metrics"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestInstrumentGORM(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "metrics.db")), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&widget{}))
+
+	require.NoError(t, metrics.InstrumentGORM(db, "gorm_test"))
+
+	require.NoError(t, db.Create(&widget{Name: "a"}).Error)
+	require.NoError(t, db.Create(&widget{Name: "b"}).Error)
+	var found widget
+	require.NoError(t, db.First(&found, 1).Error)
+	// Not finding a record is an answer, not a failure.
+	require.ErrorIs(t, db.First(&found, 42).Error, gorm.ErrRecordNotFound)
+	require.NoError(t, db.Model(&widget{}).Where("id = ?", 1).Update("name", "c").Error)
+	require.NoError(t, db.Delete(&widget{}, 2).Error)
+	require.Error(t, db.Table("missing").Find(&[]widget{}).Error)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.DBQueryDuration)
+
+	type testCase struct {
+		operation string
+		table     string
+		result    string
+		want      uint64
+	}
+
+	cases := []testCase{
+		{operation: "create", table: "widgets", result: "ok", want: 2},
+		{operation: "query", table: "widgets", result: "ok", want: 2},
+		{operation: "update", table: "widgets", result: "ok", want: 1},
+		{operation: "delete", table: "widgets", result: "ok", want: 1},
+		{operation: "query", table: "missing", result: "error", want: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.operation+" "+tc.table, func(t *testing.T) {
+			got := sampleCount(t, reg, "crud_app_db_query_duration_seconds", map[string]string{
+				"operation": tc.operation,
+				"table":     tc.table,
+				"result":    tc.result,
+			})
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	// The connection pool is reported under the database name.
+	n, err := testutil.GatherAndCount(metrics.Registry, "go_sql_open_connections")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+// sampleCount gathers reg and returns how many observations the histogram
+// series of name with exactly labels has seen.
+func sampleCount(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) uint64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if labelsMatch(metric, labels) {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
+
+func labelsMatch(metric *dto.Metric, labels map[string]string) bool {
+	if len(metric.GetLabel()) != len(labels) {
+		return false
+	}
+	for _, label := range metric.GetLabel() {
+		if labels[label.GetName()] != label.GetValue() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"crud_app/api"
+	"crud_app/metrics"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(api.Metrics)
+	router.Get("/widgets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "404" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+
+	for _, target := range []string{"/widgets/1", "/widgets/2", "/widgets/404", "/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/widgets/1", nil))
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.HTTPRequestsTotal, metrics.HTTPRequestDuration)
+
+	// Requests are labelled with the route pattern rather than the path, so
+	// ids do not create a series each.
+	want := `
+# HELP crud_app_http_requests_total Number of HTTP requests by route pattern, method and status code.
+# TYPE crud_app_http_requests_total counter
+crud_app_http_requests_total{method="GET",route="/widgets/{id}",status="200"} 2
+crud_app_http_requests_total{method="GET",route="/widgets/{id}",status="404"} 1
+crud_app_http_requests_total{method="GET",route="unmatched",status="404"} 1
+crud_app_http_requests_total{method="POST",route="unmatched",status="405"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(want), "crud_app_http_requests_total"))
+
+	got := sampleCount(t, reg, "crud_app_http_request_duration_seconds", map[string]string{
+		"method": "GET",
+		"route":  "/widgets/{id}",
+		"status": "200",
+	})
+	require.EqualValues(t, 2, got)
+}
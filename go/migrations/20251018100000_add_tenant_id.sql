@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id_id ON users (tenant_id, id);
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id_deleted_at ON users (tenant_id, deleted_at);
+
+ALTER TABLE api_keys
+ADD COLUMN tenant_id VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_keys
+DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_users_tenant_id_deleted_at;
+DROP INDEX IF EXISTS idx_users_tenant_id_id;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS tenant_id;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS users_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    age INTEGER NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_history_tenant_id_user_id ON users_history (tenant_id, user_id, valid_from);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_history_current ON users_history (user_id) WHERE valid_to IS NULL;
+
+INSERT INTO users_history (user_id, tenant_id, name, age, created_at, updated_at, deleted_at, valid_from)
+SELECT id, tenant_id, name, age, created_at, updated_at, deleted_at, COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+FROM users;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS users_history;
+-- +goose StatementEnd
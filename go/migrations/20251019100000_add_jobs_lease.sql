@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN owner VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN renewed_at TIMESTAMP;
+UPDATE jobs SET renewed_at = COALESCE(started_at, created_at) WHERE finished_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_unfinished_owner ON jobs (owner) WHERE finished_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_jobs_unfinished_renewed_at ON jobs (renewed_at) WHERE finished_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_unfinished_renewed_at;
+DROP INDEX IF EXISTS idx_jobs_unfinished_owner;
+ALTER TABLE jobs DROP COLUMN renewed_at;
+ALTER TABLE jobs DROP COLUMN owner;
+-- +goose StatementEnd
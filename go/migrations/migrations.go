@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Postgres migrations live at the top level, SQLite variants of the same
+// versions under sqlite/.
+//
+//go:embed *.sql sqlite/*.sql
+var files embed.FS
+
+// Up applies every pending migration for the given goose dialect.
+func Up(ctx context.Context, db *sql.DB, dialect goose.Dialect) error {
+	dir := "."
+	if dialect == goose.DialectSQLite3 {
+		dir = "sqlite"
+	}
+
+	fsys, err := fs.Sub(files, dir)
+	if err != nil {
+		return err
+	}
+
+	provider, err := goose.NewProvider(dialect, db, fsys)
+	if err != nil {
+		return fmt.Errorf("create migration provider: %w", err)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox ADD COLUMN sequence INTEGER;
+UPDATE outbox SET sequence = id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_sequence ON outbox (sequence);
+CREATE INDEX IF NOT EXISTS idx_outbox_tenant_id_sequence ON outbox (tenant_id, sequence);
+DROP INDEX IF EXISTS idx_outbox_tenant_id;
+
+CREATE TABLE IF NOT EXISTS outbox_sequence (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    value BIGINT NOT NULL
+);
+INSERT INTO outbox_sequence (id, value) SELECT 1, COALESCE(MAX(id), 0) FROM outbox;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_sequence;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_tenant_id ON outbox (tenant_id, id);
+DROP INDEX IF EXISTS idx_outbox_tenant_id_sequence;
+DROP INDEX IF EXISTS idx_outbox_sequence;
+ALTER TABLE outbox DROP COLUMN sequence;
+-- +goose StatementEnd
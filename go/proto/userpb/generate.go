@@ -0,0 +1,5 @@
+// Package userpb holds the protobuf and gRPC bindings generated from
+// user.proto.
+package userpb
+
+//go:generate buf generate
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crud_app/dto"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+const apiKeysTableName string = "api_keys"
+
+type APIKeyRepo interface {
+	List(ctx context.Context) ([]dto.APIKey, error)
+	Create(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*dto.APIKey, error)
+	Revoke(ctx context.Context, id uint) (bool, error)
+}
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepo(db *gorm.DB) APIKeyRepo {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) List(ctx context.Context) ([]dto.APIKey, error) {
+	var keys []dto.APIKey
+
+	return keys, conn(ctx, r.db).
+		Table(apiKeysTableName).
+		Order("id").
+		Find(&keys).
+		Error
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+	err := conn(ctx, r.db).
+		Table(apiKeysTableName).
+		Create(key).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*dto.APIKey, error) {
+	var key dto.APIKey
+	err := conn(ctx, r.db).
+		Table(apiKeysTableName).
+		Where("prefix = ?", prefix).
+		Where("revoked_at is null").
+		Take(&key).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, id uint) (bool, error) {
+	result := conn(ctx, r.db).
+		Table(apiKeysTableName).
+		Where("id = ?", id).
+		Where("revoked_at is null").
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
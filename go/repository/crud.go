@@ -0,0 +1,473 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Repo is the storage every entity gets for free. Models with a
+// gorm.DeletedAt field are soft deleted, and models with a TenantID field
+// are scoped to the tenant carried by the context.
+type Repo[T any] interface {
+	List(ctx context.Context) ([]T, error)
+	// Find returns the page of records matching query and the number of
+	// matching records.
+	Find(ctx context.Context, query dto.ListQuery) ([]T, int64, error)
+	// Iterate yields the same records as List, one row at a time, from a
+	// consistent snapshot. An error ends the sequence.
+	Iterate(ctx context.Context) iter.Seq2[T, error]
+	Get(ctx context.Context, id uint) (*T, error)
+	GetDeleted(ctx context.Context, id uint) (*T, error)
+	Create(ctx context.Context, entity *T) (*T, error)
+	CreateBatch(ctx context.Context, entities []T) ([]T, error)
+	Update(ctx context.Context, entity *T, id uint) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	Exists(ctx context.Context, id uint) (bool, error)
+}
+
+type repo[T any] struct {
+	db *gorm.DB
+}
+
+func NewRepo[T any](db *gorm.DB) Repo[T] {
+	return &repo[T]{db: db}
+}
+
+func (r *repo[T]) List(ctx context.Context) ([]T, error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+
+	entities := []T{}
+
+	return entities, r.query(ctx, s).
+		Order(s.PrioritizedPrimaryField.DBName).
+		Find(&entities).
+		Error
+}
+
+func (r *repo[T]) Find(ctx context.Context, query dto.ListQuery) ([]T, int64, error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, 0, err
+	}
+	filters, err := parseFilters(s, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := r.query(ctx, s)
+	for _, f := range filters {
+		q = q.Where(f.expression())
+	}
+	q = q.Session(&gorm.Session{})
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if query.Limit > 0 {
+		q = q.Limit(query.Limit)
+	}
+	entities := []T{}
+	err = q.
+		Order(s.PrioritizedPrimaryField.DBName).
+		Offset(query.Offset).
+		Find(&entities).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entities, total, nil
+}
+
+func (r *repo[T]) Iterate(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		s, err := r.schema()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		err = withinSnapshot(ctx, r.db, func(ctx context.Context) error {
+			rows, err := r.query(ctx, s).
+				Order(s.PrioritizedPrimaryField.DBName).
+				Rows()
+
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var entity T
+				if err := conn(ctx, r.db).ScanRows(rows, &entity); err != nil {
+					return err
+				}
+				if !yield(entity, nil) {
+					return nil
+				}
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+func (r *repo[T]) Get(ctx context.Context, id uint) (*T, error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.take(r.query(ctx, s).Where(clause.Eq{Column: clause.PrimaryColumn, Value: id}))
+}
+
+func (r *repo[T]) GetDeleted(ctx context.Context, id uint) (*T, error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	deletedAt := softDeleteField(s)
+	if deletedAt == nil {
+		return nil, ErrNotFound
+	}
+
+	return r.take(r.query(ctx, s).
+		Unscoped().
+		Where(clause.Eq{Column: clause.PrimaryColumn, Value: id}).
+		Where(clause.Neq{Column: clause.Column{Name: deletedAt.DBName}, Value: nil}))
+}
+
+func (r *repo[T]) Create(ctx context.Context, entity *T) (*T, error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	if err := setTenant(ctx, s, reflect.ValueOf(entity).Elem()); err != nil {
+		return nil, err
+	}
+
+	if err := conn(ctx, r.db).Create(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+func (r *repo[T]) CreateBatch(ctx context.Context, entities []T) ([]T, error) {
+	if len(entities) == 0 {
+		return entities, nil
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entities {
+		if err := setTenant(ctx, s, reflect.ValueOf(&entities[i]).Elem()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := conn(ctx, r.db).Create(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+// Update replaces every column of the record but those fixedColumns keeps,
+// so fields can be set back to their zero value.
+func (r *repo[T]) Update(ctx context.Context, entity *T, id uint) error {
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+	if err := setTenant(ctx, s, reflect.ValueOf(entity).Elem()); err != nil {
+		return err
+	}
+
+	return conn(ctx, r.db).
+		Scopes(r.scopeTenant(ctx, s)).
+		Where(clause.Eq{Column: clause.PrimaryColumn, Value: id}).
+		Select("*").
+		Omit(fixedColumns(s)...).
+		Updates(entity).
+		Error
+}
+
+func (r *repo[T]) Delete(ctx context.Context, id uint) error {
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+
+	return conn(ctx, r.db).
+		Scopes(r.scopeTenant(ctx, s)).
+		Delete(new(T), id).
+		Error
+}
+
+// Restore leaves updated_at alone, so a restored record reads as it did
+// before it was deleted.
+func (r *repo[T]) Restore(ctx context.Context, id uint) error {
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+	deletedAt := softDeleteField(s)
+	if deletedAt == nil {
+		return nil
+	}
+
+	return conn(ctx, r.db).
+		Table(s.Table).
+		Scopes(r.scopeTenant(ctx, s)).
+		Where(clause.Eq{Column: clause.Column{Name: s.PrioritizedPrimaryField.DBName}, Value: id}).
+		Where(clause.Neq{Column: clause.Column{Name: deletedAt.DBName}, Value: nil}).
+		Update(deletedAt.DBName, nil).
+		Error
+}
+
+func (r *repo[T]) Exists(ctx context.Context, id uint) (bool, error) {
+	s, err := r.schema()
+	if err != nil {
+		return false, err
+	}
+
+	var count int64
+	err = r.query(ctx, s).
+		Where(clause.Eq{Column: clause.PrimaryColumn, Value: id}).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// schema parses T through the connection's cache, so it costs a lookup
+// after the first call.
+func (r *repo[T]) schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, fmt.Errorf("parse model: %w", err)
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("model %s has no primary key", stmt.Schema.Name)
+	}
+
+	return stmt.Schema, nil
+}
+
+// query starts a read of T; GORM leaves soft deleted records out unless
+// the caller asks for Unscoped.
+func (r *repo[T]) query(ctx context.Context, s *schema.Schema) *gorm.DB {
+	return conn(ctx, r.db).
+		Model(new(T)).
+		Scopes(r.scopeTenant(ctx, s))
+}
+
+func (r *repo[T]) scopeTenant(ctx context.Context, s *schema.Schema) func(*gorm.DB) *gorm.DB {
+	if tenantField(s) == nil {
+		return func(db *gorm.DB) *gorm.DB { return db }
+	}
+
+	return scopeTenant(ctx)
+}
+
+func (r *repo[T]) take(query *gorm.DB) (*T, error) {
+	var entity T
+	err := query.Take(&entity).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+// fixedColumns are the columns Update leaves alone: the key, the tenant, the
+// creation time and the deletion time, which only Delete and Restore change.
+// Every other column is written, zero values included.
+func fixedColumns(s *schema.Schema) []string {
+	columns := slices.Clone(s.PrimaryFieldDBNames)
+	for _, name := range []string{"TenantID", "CreatedAt", "DeletedAt"} {
+		if field := s.LookUpField(name); field != nil {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns
+}
+
+func tenantField(s *schema.Schema) *schema.Field {
+	return s.LookUpField("TenantID")
+}
+
+func softDeleteField(s *schema.Schema) *schema.Field {
+	for _, field := range s.Fields {
+		if field.FieldType == reflect.TypeFor[gorm.DeletedAt]() {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// setTenant stamps the tenant carried by ctx on a record of a tenant scoped
+// model.
+func setTenant(ctx context.Context, s *schema.Schema, entity reflect.Value) error {
+	field := tenantField(s)
+	if field == nil {
+		return nil
+	}
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return field.Set(ctx, entity, tenantID)
+}
+
+type filter struct {
+	field *schema.Field
+	op    dto.Operator
+	value any
+}
+
+// parseFilters resolves the filters and conditions of query, keyed by column
+// or field names, against the model and converts each value to its field's
+// type. Only scalar fields can be filtered on, and never the tenant, which
+// comes from the context.
+func parseFilters(s *schema.Schema, query dto.ListQuery) ([]filter, error) {
+	conditions := make([]dto.Condition, 0, len(query.Filters)+len(query.Conditions))
+	for key, raw := range query.Filters {
+		conditions = append(conditions, dto.Condition{Field: key, Op: dto.OpEqual, Value: raw})
+	}
+	conditions = append(conditions, query.Conditions...)
+
+	parsed := make([]filter, 0, len(conditions))
+	for _, c := range conditions {
+		field := s.LookUpField(c.Field)
+		if field == nil || field.DBName == "" || field == tenantField(s) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, c.Field)
+		}
+
+		kind := field.IndirectFieldType.Kind()
+		switch c.Op {
+		case dto.OpEqual:
+		case dto.OpLess, dto.OpLessOrEqual, dto.OpGreater, dto.OpGreaterOrEqual:
+			if kind == reflect.Bool {
+				return nil, fmt.Errorf("%w: %s: cannot be compared with %s", ErrInvalidFilter, c.Field, c.Op)
+			}
+		case dto.OpContains:
+			if kind != reflect.String {
+				return nil, fmt.Errorf("%w: %s: not a text field", ErrInvalidFilter, c.Field)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, c.Op)
+		}
+
+		value, err := parseFilterValue(field.IndirectFieldType, c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFilter, c.Field, err)
+		}
+		parsed = append(parsed, filter{field: field, op: c.Op, value: value})
+	}
+
+	return parsed, nil
+}
+
+func (f filter) expression() clause.Expression {
+	column := clause.Column{Name: f.field.DBName}
+
+	switch f.op {
+	case dto.OpLess:
+		return clause.Lt{Column: column, Value: f.value}
+	case dto.OpLessOrEqual:
+		return clause.Lte{Column: column, Value: f.value}
+	case dto.OpGreater:
+		return clause.Gt{Column: column, Value: f.value}
+	case dto.OpGreaterOrEqual:
+		return clause.Gte{Column: column, Value: f.value}
+	case dto.OpContains:
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(f.value.(string))) + "%"
+		return clause.Expr{SQL: `LOWER(?) LIKE ? ESCAPE '\'`, Vars: []any{column, pattern}}
+	default:
+		return clause.Eq{Column: column, Value: f.value}
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func parseFilterValue(t reflect.Type, raw string) (any, error) {
+	value := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("not a boolean")
+		}
+		value.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return nil, errors.New("not an integer")
+		}
+		value.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return nil, errors.New("not a non-negative integer")
+		}
+		value.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return nil, errors.New("not a number")
+		}
+		value.SetFloat(v)
+	default:
+		return nil, errors.New("field cannot be filtered on")
+	}
+
+	return value.Interface(), nil
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+// note has neither a tenant nor soft deletes, so the generic repository
+// shares it between tenants and deletes it for good.
+type note struct {
+	ID     uint `gorm:"primaryKey"`
+	Text   string
+	Pinned bool
+}
+
+func TestRepo_PlainModel(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "notes.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&note{}))
+
+	repo := repository.NewRepo[note](db)
+	ctx := t.Context()
+
+	created, err := repo.CreateBatch(ctx, []note{{Text: "a", Pinned: true}, {Text: "b"}, {Text: "c", Pinned: true}})
+	require.NoError(t, err)
+
+	pinned, total, err := repo.Find(ctx, dto.ListQuery{
+		PageRequest: dto.PageRequest{Limit: 1},
+		Filters:     map[string]string{"pinned": "true"},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Equal(t, []note{created[0]}, pinned)
+
+	_, _, err = repo.Find(ctx, dto.ListQuery{Filters: map[string]string{"pinned": "maybe"}})
+	require.ErrorIs(t, err, repository.ErrInvalidFilter)
+
+	require.NoError(t, repo.Update(ctx, &note{Text: "b2"}, created[1].ID))
+	got, err := repo.Get(ctx, created[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, "b2", got.Text)
+
+	require.NoError(t, repo.Delete(ctx, created[1].ID))
+	_, err = repo.Get(ctx, created[1].ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.GetDeleted(ctx, created[1].ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&note{}).Count(&count).Error)
+	require.EqualValues(t, 2, count)
+}
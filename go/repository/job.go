@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+const (
+	jobsTableName      string = "jobs"
+	jobErrorsTableName string = "job_errors"
+)
+
+type JobRepo interface {
+	Create(ctx context.Context, job *dto.Job) (*dto.Job, error)
+	Get(ctx context.Context, id uint64) (*dto.Job, error)
+	Start(ctx context.Context, id uint64, at time.Time) error
+	UpdateProgress(ctx context.Context, id uint64, progress dto.JobProgress) error
+	Finish(ctx context.Context, id uint64, status, lastError string, at time.Time) error
+	AddErrors(ctx context.Context, jobErrors []dto.JobError) error
+	ListErrors(ctx context.Context, id uint64) ([]dto.JobError, error)
+
+	// The methods below serve recovery and ignore the tenant carried by ctx.
+	// Jobs run in the process that created them, so a job whose owner is gone
+	// never finishes on its own.
+
+	// Renew marks the unfinished jobs of owner as alive at at.
+	Renew(ctx context.Context, owner string, at time.Time) error
+	// FailOwned fails the unfinished jobs of owner, e.g. when it starts again
+	// after a crash.
+	FailOwned(ctx context.Context, owner, lastError string, at time.Time) (int64, error)
+	// FailExpired fails the unfinished jobs of any owner last renewed before
+	// renewedBefore.
+	FailExpired(ctx context.Context, renewedBefore time.Time, lastError string, at time.Time) (int64, error)
+}
+
+type jobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepo(db *gorm.DB) JobRepo {
+	return &jobRepo{db: db}
+}
+
+func (r *jobRepo) Create(ctx context.Context, job *dto.Job) (*dto.Job, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job.TenantID = tenantID
+
+	err = conn(ctx, r.db).
+		Table(jobsTableName).
+		Create(job).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepo) Get(ctx context.Context, id uint64) (*dto.Job, error) {
+	var job dto.Job
+	err := conn(ctx, r.db).
+		Table(jobsTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Take(&job).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *jobRepo) Start(ctx context.Context, id uint64, at time.Time) error {
+	return conn(ctx, r.db).
+		Table(jobsTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     dto.JobStatusRunning,
+			"started_at": at.UTC(),
+		}).
+		Error
+}
+
+func (r *jobRepo) UpdateProgress(ctx context.Context, id uint64, progress dto.JobProgress) error {
+	return conn(ctx, r.db).
+		Table(jobsTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"bytes_read": progress.BytesRead,
+			"processed":  progress.Processed,
+			"valid":      progress.Valid,
+			"invalid":    progress.Invalid,
+			"imported":   progress.Imported,
+		}).
+		Error
+}
+
+func (r *jobRepo) Finish(ctx context.Context, id uint64, status, lastError string, at time.Time) error {
+	return conn(ctx, r.db).
+		Table(jobsTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":      status,
+			"error":       lastError,
+			"finished_at": at.UTC(),
+		}).
+		Error
+}
+
+func (r *jobRepo) AddErrors(ctx context.Context, jobErrors []dto.JobError) error {
+	if len(jobErrors) == 0 {
+		return nil
+	}
+
+	return conn(ctx, r.db).
+		Table(jobErrorsTableName).
+		Create(&jobErrors).
+		Error
+}
+
+func (r *jobRepo) ListErrors(ctx context.Context, id uint64) ([]dto.JobError, error) {
+	if _, err := r.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var jobErrors []dto.JobError
+
+	return jobErrors, conn(ctx, r.db).
+		Table(jobErrorsTableName).
+		Where("job_id = ?", id).
+		Order("line").
+		Find(&jobErrors).
+		Error
+}
+
+func (r *jobRepo) Renew(ctx context.Context, owner string, at time.Time) error {
+	return conn(ctx, r.db).
+		Table(jobsTableName).
+		Where("owner = ? AND finished_at IS NULL", owner).
+		Update("renewed_at", at.UTC()).
+		Error
+}
+
+func (r *jobRepo) FailOwned(ctx context.Context, owner, lastError string, at time.Time) (int64, error) {
+	return r.failUnfinished(ctx, lastError, at, "owner = ?", owner)
+}
+
+func (r *jobRepo) FailExpired(ctx context.Context, renewedBefore time.Time, lastError string, at time.Time) (int64, error) {
+	// Jobs created before leases existed were never renewed.
+	return r.failUnfinished(ctx, lastError, at, "renewed_at IS NULL OR renewed_at < ?", renewedBefore.UTC())
+}
+
+func (r *jobRepo) failUnfinished(ctx context.Context, lastError string, at time.Time, query string, args ...any) (int64, error) {
+	result := conn(ctx, r.db).
+		Table(jobsTableName).
+		Where("finished_at IS NULL").
+		Where(query, args...).
+		Updates(map[string]any{
+			"status":      dto.JobStatusFailed,
+			"error":       lastError,
+			"finished_at": at.UTC(),
+		})
+
+	return result.RowsAffected, result.Error
+}
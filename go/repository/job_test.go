@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/config"
+	"crud_app/dto"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+var jobRepoFactories = map[string]func(t *testing.T) repository.JobRepo{
+	"memory": func(t *testing.T) repository.JobRepo {
+		return repository.NewMemoryJobRepo()
+	},
+	"sqlite": func(t *testing.T) repository.JobRepo {
+		t.Setenv("DB_DRIVER", config.DriverSQLite)
+		t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "crud_app.db"))
+
+		db, err := config.ConnectDB()
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = sqlDB.Close() })
+
+		return repository.NewJobRepo(db)
+	},
+}
+
+func TestJobRepo_Lifecycle(t *testing.T) {
+	for name, newRepo := range jobRepoFactories {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			acme := tenant.WithID(context.Background(), "acme")
+			globex := tenant.WithID(context.Background(), "globex")
+			at := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+
+			job, err := repo.Create(acme, &dto.Job{Kind: dto.JobKindUserImport, Status: dto.JobStatusPending, BytesTotal: 42})
+			require.NoError(t, err)
+			require.NotZero(t, job.ID)
+
+			require.NoError(t, repo.Start(acme, job.ID, at))
+			require.NoError(t, repo.UpdateProgress(acme, job.ID, dto.JobProgress{BytesRead: 42, Processed: 3, Valid: 2, Invalid: 1, Imported: 2}))
+			require.NoError(t, repo.AddErrors(acme, []dto.JobError{
+				{JobID: job.ID, Line: 4, Message: "age must be positive"},
+				{JobID: job.ID, Line: 2, Message: "name is required"},
+			}))
+			require.NoError(t, repo.Finish(acme, job.ID, dto.JobStatusSucceeded, "", at.Add(time.Minute)))
+
+			got, err := repo.Get(acme, job.ID)
+			require.NoError(t, err)
+			require.Equal(t, dto.JobStatusSucceeded, got.Status)
+			require.Equal(t, 3, got.Processed)
+			require.Equal(t, 2, got.Imported)
+			require.EqualValues(t, 42, got.BytesRead)
+			require.NotNil(t, got.StartedAt)
+			require.True(t, at.Equal(*got.StartedAt))
+			require.NotNil(t, got.FinishedAt)
+
+			jobErrors, err := repo.ListErrors(acme, job.ID)
+			require.NoError(t, err)
+			require.Len(t, jobErrors, 2)
+			require.Equal(t, 2, jobErrors[0].Line)
+			require.Equal(t, 4, jobErrors[1].Line)
+
+			// Another tenant sees neither the job nor its errors.
+			_, err = repo.Get(globex, job.ID)
+			require.ErrorIs(t, err, repository.ErrNotFound)
+			_, err = repo.ListErrors(globex, job.ID)
+			require.ErrorIs(t, err, repository.ErrNotFound)
+		})
+	}
+}
+
+func TestJobRepo_Recovery(t *testing.T) {
+	at := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name string
+		// fail runs the recovery under test; node-1 renewed its jobs at
+		// at+1m, node-2 last did at at.
+		fail       func(ctx context.Context, repo repository.JobRepo) (int64, error)
+		wantFailed []string
+	}
+
+	cases := []testCase{{
+		name: "owned",
+		fail: func(ctx context.Context, repo repository.JobRepo) (int64, error) {
+			return repo.FailOwned(ctx, "node-1", "interrupted by a restart", at.Add(2*time.Minute))
+		},
+		wantFailed: []string{"node-1 running", "node-1 other tenant"},
+	}, {
+		name: "expired",
+		fail: func(ctx context.Context, repo repository.JobRepo) (int64, error) {
+			return repo.FailExpired(ctx, at.Add(30*time.Second), "its process stopped", at.Add(2*time.Minute))
+		},
+		wantFailed: []string{"node-2 running"},
+	}, {
+		name: "nothing expired",
+		fail: func(ctx context.Context, repo repository.JobRepo) (int64, error) {
+			return repo.FailExpired(ctx, at, "its process stopped", at.Add(2*time.Minute))
+		},
+	}}
+
+	for name, newRepo := range jobRepoFactories {
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					repo := newRepo(t)
+					acme := tenant.WithID(context.Background(), "acme")
+					globex := tenant.WithID(context.Background(), "globex")
+
+					seeded := []struct {
+						key   string
+						ctx   context.Context
+						owner string
+					}{
+						{key: "node-1 running", ctx: acme, owner: "node-1"},
+						{key: "node-1 other tenant", ctx: globex, owner: "node-1"},
+						{key: "node-2 running", ctx: acme, owner: "node-2"},
+						{key: "node-1 finished", ctx: acme, owner: "node-1"},
+					}
+					ids := make([]uint64, len(seeded))
+					for i, job := range seeded {
+						created, err := repo.Create(job.ctx, &dto.Job{
+							Kind:      dto.JobKindUserImport,
+							Status:    dto.JobStatusRunning,
+							Owner:     job.owner,
+							RenewedAt: &at,
+						})
+						require.NoError(t, err)
+						ids[i] = created.ID
+					}
+					require.NoError(t, repo.Finish(acme, ids[3], dto.JobStatusSucceeded, "", at))
+					require.NoError(t, repo.Renew(context.Background(), "node-1", at.Add(time.Minute)))
+
+					n, err := tc.fail(context.Background(), repo)
+					require.NoError(t, err)
+					require.EqualValues(t, len(tc.wantFailed), n)
+
+					var failed []string
+					for i, job := range seeded {
+						got, err := repo.Get(job.ctx, ids[i])
+						require.NoError(t, err)
+						if got.Status == dto.JobStatusFailed {
+							failed = append(failed, job.key)
+							require.NotEmpty(t, got.Error)
+						}
+					}
+					require.Equal(t, tc.wantFailed, failed)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+// memoryTransactor serves the in-memory repositories. They apply every write
+// at once and cannot roll back, so a failing fn leaves its earlier writes in
+// place; that is fine for local development and tests, not for real data.
+// After-commit hooks still wait for fn to succeed.
+type memoryTransactor struct{}
+
+func NewMemoryTransactor() Transactor {
+	return memoryTransactor{}
+}
+
+func (memoryTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks); ok {
+		return fn(ctx)
+	}
+
+	hooks := &afterCommitHooks{}
+	if err := fn(context.WithValue(ctx, afterCommitKey{}, hooks)); err != nil {
+		return err
+	}
+
+	for _, hook := range hooks.hooks {
+		hook()
+	}
+
+	return nil
+}
+
+// paginate applies a PageRequest to rows that are already in order.
+func paginate[T any](rows []T, page dto.PageRequest) []T {
+	if page.Offset >= len(rows) {
+		return []T{}
+	}
+	rows = rows[page.Offset:]
+
+	if page.Limit > 0 && page.Limit < len(rows) {
+		rows = rows[:page.Limit]
+	}
+
+	return rows
+}
+
+var memorySchemas sync.Map
+
+// filterRows keeps the rows matching every filter and condition of query,
+// with the same parsing and errors as the database repositories.
+func filterRows[T any](ctx context.Context, rows []T, query dto.ListQuery) ([]T, error) {
+	s, err := schema.Parse(new(T), &memorySchemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseFilters(s, query)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []T{}
+	for i := range rows {
+		row := reflect.ValueOf(&rows[i]).Elem()
+		if rowMatches(ctx, row, parsed) {
+			matches = append(matches, rows[i])
+		}
+	}
+
+	return matches, nil
+}
+
+func rowMatches(ctx context.Context, row reflect.Value, filters []filter) bool {
+	for _, f := range filters {
+		value, _ := f.field.ValueOf(ctx, row)
+		if !f.matches(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f filter) matches(value any) bool {
+	switch f.op {
+	case dto.OpEqual:
+		return value == f.value
+	case dto.OpContains:
+		text, _ := value.(string)
+		return strings.Contains(strings.ToLower(text), strings.ToLower(f.value.(string)))
+	}
+
+	got, want := reflect.ValueOf(value), reflect.ValueOf(f.value)
+	if got.Kind() != want.Kind() {
+		return false
+	}
+
+	var c int
+	switch got.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c = cmp.Compare(got.Int(), want.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		c = cmp.Compare(got.Uint(), want.Uint())
+	case reflect.Float32, reflect.Float64:
+		c = cmp.Compare(got.Float(), want.Float())
+	case reflect.String:
+		c = cmp.Compare(got.String(), want.String())
+	default:
+		return false
+	}
+
+	switch f.op {
+	case dto.OpLess:
+		return c < 0
+	case dto.OpLessOrEqual:
+		return c <= 0
+	case dto.OpGreater:
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// memoryTenantID returns the tenant carried by ctx and then, like a database
+// driver would, fails once ctx is done.
+func memoryTenantID(ctx context.Context) (string, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return tenantID, ctx.Err()
+}
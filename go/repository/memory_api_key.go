@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"crud_app/dto"
+)
+
+type memoryAPIKeyRepo struct {
+	mu   sync.RWMutex
+	keys []dto.APIKey
+}
+
+func NewMemoryAPIKeyRepo() APIKeyRepo {
+	return &memoryAPIKeyRepo{}
+}
+
+func (r *memoryAPIKeyRepo) List(ctx context.Context) ([]dto.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]dto.APIKey, len(r.keys))
+	copy(keys, r.keys)
+
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepo) Create(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.keys {
+		if existing.Prefix == key.Prefix {
+			return nil, fmt.Errorf("api key prefix %q already exists", key.Prefix)
+		}
+	}
+
+	key.ID = uint(len(r.keys) + 1)
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	r.keys = append(r.keys, *key)
+
+	return key, nil
+}
+
+func (r *memoryAPIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*dto.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Prefix == prefix && key.RevokedAt == nil {
+			return &key, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *memoryAPIKeyRepo) Revoke(ctx context.Context, id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > uint(len(r.keys)) || r.keys[id-1].RevokedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	r.keys[id-1].RevokedAt = &now
+
+	return true, nil
+}
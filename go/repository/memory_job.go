@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+type memoryJobRepo struct {
+	mu     sync.RWMutex
+	jobs   []dto.Job
+	errors []dto.JobError
+}
+
+func NewMemoryJobRepo() JobRepo {
+	return &memoryJobRepo{}
+}
+
+func (r *memoryJobRepo) Create(ctx context.Context, job *dto.Job) (*dto.Job, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.ID = uint64(len(r.jobs) + 1)
+	job.TenantID = tenantID
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	r.jobs = append(r.jobs, *job)
+
+	return job, nil
+}
+
+func (r *memoryJobRepo) Get(ctx context.Context, id uint64) (*dto.Job, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id < 1 || id > uint64(len(r.jobs)) || r.jobs[id-1].TenantID != tenantID {
+		return nil, ErrNotFound
+	}
+	job := r.jobs[id-1]
+
+	return &job, nil
+}
+
+func (r *memoryJobRepo) Start(ctx context.Context, id uint64, at time.Time) error {
+	return r.update(ctx, id, func(job *dto.Job) {
+		at := at.UTC()
+		job.Status = dto.JobStatusRunning
+		job.StartedAt = &at
+	})
+}
+
+func (r *memoryJobRepo) UpdateProgress(ctx context.Context, id uint64, progress dto.JobProgress) error {
+	return r.update(ctx, id, func(job *dto.Job) {
+		job.BytesRead = progress.BytesRead
+		job.Processed = progress.Processed
+		job.Valid = progress.Valid
+		job.Invalid = progress.Invalid
+		job.Imported = progress.Imported
+	})
+}
+
+func (r *memoryJobRepo) Finish(ctx context.Context, id uint64, status, lastError string, at time.Time) error {
+	return r.update(ctx, id, func(job *dto.Job) {
+		at := at.UTC()
+		job.Status = status
+		job.Error = lastError
+		job.FinishedAt = &at
+	})
+}
+
+func (r *memoryJobRepo) AddErrors(ctx context.Context, jobErrors []dto.JobError) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, jobError := range jobErrors {
+		jobError.ID = uint64(len(r.errors) + 1)
+		r.errors = append(r.errors, jobError)
+	}
+
+	return nil
+}
+
+func (r *memoryJobRepo) ListErrors(ctx context.Context, id uint64) ([]dto.JobError, error) {
+	if _, err := r.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobErrors := []dto.JobError{}
+	for _, jobError := range r.errors {
+		if jobError.JobID == id {
+			jobErrors = append(jobErrors, jobError)
+		}
+	}
+	slices.SortStableFunc(jobErrors, func(a, b dto.JobError) int { return cmp.Compare(a.Line, b.Line) })
+
+	return jobErrors, nil
+}
+
+func (r *memoryJobRepo) Renew(ctx context.Context, owner string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.jobs {
+		job := &r.jobs[i]
+		if job.FinishedAt == nil && job.Owner == owner {
+			at := at.UTC()
+			job.RenewedAt = &at
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryJobRepo) FailOwned(ctx context.Context, owner, lastError string, at time.Time) (int64, error) {
+	return r.failUnfinished(lastError, at, func(job *dto.Job) bool {
+		return job.Owner == owner
+	}), nil
+}
+
+func (r *memoryJobRepo) FailExpired(ctx context.Context, renewedBefore time.Time, lastError string, at time.Time) (int64, error) {
+	return r.failUnfinished(lastError, at, func(job *dto.Job) bool {
+		return job.RenewedAt == nil || job.RenewedAt.Before(renewedBefore)
+	}), nil
+}
+
+func (r *memoryJobRepo) failUnfinished(lastError string, at time.Time, match func(*dto.Job) bool) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failed int64
+	for i := range r.jobs {
+		job := &r.jobs[i]
+		if job.FinishedAt != nil || !match(job) {
+			continue
+		}
+
+		at := at.UTC()
+		job.Status = dto.JobStatusFailed
+		job.Error = lastError
+		job.FinishedAt = &at
+		failed++
+	}
+
+	return failed
+}
+
+func (r *memoryJobRepo) update(ctx context.Context, id uint64, fn func(*dto.Job)) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id >= 1 && id <= uint64(len(r.jobs)) && r.jobs[id-1].TenantID == tenantID {
+		fn(&r.jobs[id-1])
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+// memoryOutboxRepo does not lock claimed events, so it only suits a single
+// relay, which is all a process with in-memory storage can have.
+type memoryOutboxRepo struct {
+	mu           sync.RWMutex
+	events       []dto.OutboxEvent
+	lastSequence uint64
+}
+
+func NewMemoryOutboxRepo() OutboxRepo {
+	return &memoryOutboxRepo{}
+}
+
+func (r *memoryOutboxRepo) Add(ctx context.Context, event *dto.OutboxEvent) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = uint64(len(r.events) + 1)
+	event.TenantID = tenantID
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = time.Now().UTC()
+	}
+	r.events = append(r.events, *event)
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) ClaimPending(ctx context.Context, now time.Time, limit int) ([]dto.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []dto.OutboxEvent
+	for _, event := range r.events {
+		if len(events) == limit {
+			break
+		}
+		if event.PublishedAt == nil && !event.NextAttemptAt.After(now) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *memoryOutboxRepo) AssignSequences(ctx context.Context, events []dto.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range events {
+		if events[i].Sequence != nil {
+			continue
+		}
+		r.lastSequence++
+		sequence := r.lastSequence
+		events[i].Sequence = &sequence
+		if id := events[i].ID; id >= 1 && id <= uint64(len(r.events)) {
+			r.events[id-1].Sequence = &sequence
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) MarkPublished(ctx context.Context, id uint64, at time.Time) error {
+	r.update(id, func(event *dto.OutboxEvent) {
+		at := at.UTC()
+		event.PublishedAt = &at
+		event.Attempts++
+		event.LastError = ""
+	})
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) MarkFailed(ctx context.Context, id uint64, nextAttemptAt time.Time, lastError string) error {
+	r.update(id, func(event *dto.OutboxEvent) {
+		event.NextAttemptAt = nextAttemptAt.UTC()
+		event.Attempts++
+		event.LastError = lastError
+	})
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) ListSince(ctx context.Context, afterSequence uint64, eventTypes []string, limit int) ([]dto.OutboxEvent, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.listSequenced(afterSequence, limit, func(event dto.OutboxEvent) bool {
+		return event.TenantID == tenantID && (len(eventTypes) == 0 || slices.Contains(eventTypes, event.EventType))
+	}), nil
+}
+
+func (r *memoryOutboxRepo) ListSequenced(ctx context.Context, afterSequence uint64, limit int) ([]dto.OutboxEvent, error) {
+	return r.listSequenced(afterSequence, limit, func(dto.OutboxEvent) bool { return true }), nil
+}
+
+func (r *memoryOutboxRepo) LastSequence(ctx context.Context) (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastSequence, nil
+}
+
+func (r *memoryOutboxRepo) listSequenced(afterSequence uint64, limit int, keep func(dto.OutboxEvent) bool) []dto.OutboxEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := []dto.OutboxEvent{}
+	for _, event := range r.events {
+		if event.Sequence != nil && *event.Sequence > afterSequence && keep(event) {
+			events = append(events, event)
+		}
+	}
+	slices.SortFunc(events, func(a, b dto.OutboxEvent) int {
+		return cmp.Compare(*a.Sequence, *b.Sequence)
+	})
+
+	return events[:min(limit, len(events))]
+}
+
+func (r *memoryOutboxRepo) update(id uint64, fn func(*dto.OutboxEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id >= 1 && id <= uint64(len(r.events)) {
+		fn(&r.events[id-1])
+	}
+}
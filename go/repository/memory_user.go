@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crud_app/dto"
+)
+
+// memoryUserRepo keeps users and their versions in memory with the semantics
+// of userRepo: IDs are shared by all tenants and never reused, deletes are
+// soft, and every write appends a version.
+type memoryUserRepo struct {
+	mu       sync.RWMutex
+	lastID   uint
+	users    map[uint]dto.User
+	versions []dto.UserVersion
+	// lastVersionID keeps version IDs unique once Purge removed some.
+	lastVersionID uint
+	// current maps a user ID to the index of its open version.
+	current map[uint]int
+}
+
+func NewMemoryUserRepo() UserRepo {
+	return &memoryUserRepo{
+		users:   map[uint]dto.User{},
+		current: map[uint]int{},
+	}
+}
+
+func (r *memoryUserRepo) List(ctx context.Context) ([]dto.User, error) {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []dto.User{}
+	for _, user := range r.users {
+		if user.TenantID == tenantID && !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	slices.SortFunc(users, func(a, b dto.User) int { return cmp.Compare(a.ID, b.ID) })
+
+	return users, nil
+}
+
+func (r *memoryUserRepo) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	users, err := r.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users, err = filterRows(ctx, users, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return paginate(users, query.PageRequest), int64(len(users)), nil
+}
+
+// Iterate copies the tenant's users up front, which is what makes the
+// sequence a consistent snapshot here.
+func (r *memoryUserRepo) Iterate(ctx context.Context) iter.Seq2[dto.User, error] {
+	return func(yield func(dto.User, error) bool) {
+		users, err := r.List(ctx)
+		if err != nil {
+			yield(dto.User{}, err)
+			return
+		}
+
+		for _, user := range users {
+			if err := ctx.Err(); err != nil {
+				yield(dto.User{}, err)
+				return
+			}
+			if !yield(user, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (r *memoryUserRepo) Get(ctx context.Context, id uint) (*dto.User, error) {
+	return r.get(ctx, id, false)
+}
+
+func (r *memoryUserRepo) GetDeleted(ctx context.Context, id uint) (*dto.User, error) {
+	return r.get(ctx, id, true)
+}
+
+func (r *memoryUserRepo) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.insert(tenantID, user, time.Now())
+
+	return user, nil
+}
+
+func (r *memoryUserRepo) CreateBatch(ctx context.Context, users []dto.User) ([]dto.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range users {
+		r.insert(tenantID, &users[i], now)
+	}
+
+	return users, nil
+}
+
+// Update replaces the name and age of user, zero values included, and leaves
+// deleted users alone.
+func (r *memoryUserRepo) Update(ctx context.Context, user *dto.User, id uint) error {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	user.TenantID = tenantID
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.users[id]
+	if !ok || current.TenantID != tenantID {
+		return nil
+	}
+
+	if !current.DeletedAt.Valid {
+		current.Name = user.Name
+		current.Age = user.Age
+		current.UpdatedAt = time.Now()
+		r.users[id] = current
+	}
+	r.recordVersion(current)
+
+	return nil
+}
+
+func (r *memoryUserRepo) Delete(ctx context.Context, id uint) error {
+	return r.setDeletedAt(ctx, id, gorm.DeletedAt{Time: time.Now(), Valid: true})
+}
+
+func (r *memoryUserRepo) Restore(ctx context.Context, id uint) error {
+	return r.setDeletedAt(ctx, id, gorm.DeletedAt{})
+}
+
+func (r *memoryUserRepo) Purge(ctx context.Context, id uint) error {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID {
+		return nil
+	}
+
+	delete(r.users, id)
+	delete(r.current, id)
+	r.versions = slices.DeleteFunc(r.versions, func(version dto.UserVersion) bool { return version.UserID == id })
+	for i, version := range r.versions {
+		if version.ValidTo == nil {
+			r.current[version.UserID] = i
+		}
+	}
+
+	// The tombstone of userRepo.recordPurge.
+	now := time.Now().UTC()
+	r.lastVersionID++
+	r.versions = append(r.versions, dto.UserVersion{
+		ID:        r.lastVersionID,
+		UserID:    id,
+		TenantID:  tenantID,
+		DeletedAt: &now,
+		ValidFrom: now,
+		ValidTo:   &now,
+	})
+
+	return nil
+}
+
+func (r *memoryUserRepo) Exists(ctx context.Context, id uint) (bool, error) {
+	_, err := r.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (r *memoryUserRepo) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, version := range r.versions {
+		if version.UserID == id && version.TenantID == tenantID && version.DeletedAt == nil && versionValidAt(version, at) {
+			return version.User(), nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepo) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []dto.User{}
+	for _, version := range r.versions {
+		if version.TenantID == tenantID && version.DeletedAt == nil && versionValidAt(version, at) {
+			users = append(users, *version.User())
+		}
+	}
+	slices.SortFunc(users, func(a, b dto.User) int { return cmp.Compare(a.ID, b.ID) })
+
+	return users, nil
+}
+
+func (r *memoryUserRepo) LastModified(ctx context.Context) (time.Time, error) {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Versions are appended in the order they start.
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if r.versions[i].TenantID == tenantID {
+			return r.versions[i].ValidFrom, nil
+		}
+	}
+
+	return time.Time{}, nil
+}
+
+func (r *memoryUserRepo) get(ctx context.Context, id uint, deleted bool) (*dto.User, error) {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID || user.DeletedAt.Valid != deleted {
+		return nil, ErrNotFound
+	}
+
+	return &user, nil
+}
+
+// insert must be called with r.mu held.
+func (r *memoryUserRepo) insert(tenantID string, user *dto.User, now time.Time) {
+	r.lastID++
+	user.ID = r.lastID
+	user.TenantID = tenantID
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+
+	r.users[user.ID] = *user
+	r.recordVersion(*user)
+}
+
+// setDeletedAt flips a user between live and deleted; users already in the
+// requested state are left alone.
+func (r *memoryUserRepo) setDeletedAt(ctx context.Context, id uint, deletedAt gorm.DeletedAt) error {
+	tenantID, err := memoryTenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID {
+		return nil
+	}
+
+	if user.DeletedAt.Valid != deletedAt.Valid {
+		user.DeletedAt = deletedAt
+		r.users[id] = user
+	}
+	r.recordVersion(user)
+
+	return nil
+}
+
+// recordVersion closes the user's current version and appends its new state;
+// it must be called with r.mu held.
+func (r *memoryUserRepo) recordVersion(user dto.User) {
+	now := time.Now().UTC()
+
+	if i, ok := r.current[user.ID]; ok {
+		r.versions[i].ValidTo = &now
+	}
+
+	r.lastVersionID++
+	version := dto.UserVersion{
+		ID:        r.lastVersionID,
+		UserID:    user.ID,
+		TenantID:  user.TenantID,
+		Name:      user.Name,
+		Age:       user.Age,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		ValidFrom: now,
+	}
+	if user.DeletedAt.Valid {
+		version.DeletedAt = &user.DeletedAt.Time
+	}
+
+	r.current[user.ID] = len(r.versions)
+	r.versions = append(r.versions, version)
+}
+
+func versionValidAt(version dto.UserVersion, at time.Time) bool {
+	return !version.ValidFrom.After(at) && (version.ValidTo == nil || version.ValidTo.After(at))
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+type memoryUserAuditRepo struct {
+	mu      sync.RWMutex
+	entries []dto.UserAudit
+}
+
+func NewMemoryUserAuditRepo() UserAuditRepo {
+	return &memoryUserAuditRepo{}
+}
+
+func (r *memoryUserAuditRepo) Append(ctx context.Context, entry *dto.UserAudit) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.ID = uint(len(r.entries) + 1)
+	entry.TenantID = tenantID
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	r.entries = append(r.entries, *entry)
+
+	return nil
+}
+
+func (r *memoryUserAuditRepo) ListByUser(ctx context.Context, userID uint, page dto.PageRequest) ([]dto.UserAudit, int64, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []dto.UserAudit
+	for _, entry := range slices.Backward(r.entries) {
+		if entry.TenantID == tenantID && entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return paginate(entries, page), int64(len(entries)), nil
+}
@@ -0,0 +1,14 @@
+package repository_test
+
+import (
+	"testing"
+
+	"crud_app/repository"
+	"crud_app/repository/repositorytest"
+)
+
+func TestMemoryUserRepo(t *testing.T) {
+	repositorytest.RunUserRepo(t, func(t *testing.T) repository.UserRepo {
+		return repository.NewMemoryUserRepo()
+	})
+}
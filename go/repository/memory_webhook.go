@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+// memoryWebhookSubscriptionRepo deletes subscriptions outright, like the
+// table does; their deliveries, which live in a separate repo here, are not
+// cascaded and fail once the dispatcher cannot find the subscription.
+type memoryWebhookSubscriptionRepo struct {
+	mu            sync.RWMutex
+	lastID        uint
+	subscriptions map[uint]dto.WebhookSubscription
+}
+
+func NewMemoryWebhookSubscriptionRepo() WebhookSubscriptionRepo {
+	return &memoryWebhookSubscriptionRepo{subscriptions: map[uint]dto.WebhookSubscription{}}
+}
+
+func (r *memoryWebhookSubscriptionRepo) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(func(s dto.WebhookSubscription) bool { return s.TenantID == tenantID }), nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := r.GetForDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.TenantID != tenantID {
+		return nil, ErrNotFound
+	}
+
+	return subscription, nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.lastID++
+	subscription.ID = r.lastID
+	subscription.TenantID = tenantID
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	subscription.EventTypes = slices.Clone(subscription.EventTypes)
+	r.subscriptions[subscription.ID] = *subscription
+
+	return subscription, nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.subscriptions[id]
+	if !ok || current.TenantID != tenantID {
+		return nil
+	}
+
+	current.URL = subscription.URL
+	current.EventTypes = slices.Clone(subscription.EventTypes)
+	current.UpdatedAt = time.Now()
+	if subscription.Secret != "" {
+		current.Secret = subscription.Secret
+	}
+	if subscription.Active != nil {
+		active := *subscription.Active
+		current.Active = &active
+		if active {
+			current.ConsecutiveFailures = 0
+			current.DisabledAt = nil
+		}
+	}
+	r.subscriptions[id] = current
+
+	return nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) Delete(ctx context.Context, id uint) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.subscriptions[id]; ok && current.TenantID == tenantID {
+		delete(r.subscriptions, id)
+	}
+
+	return nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) ListActive(ctx context.Context, tenantID string) ([]dto.WebhookSubscription, error) {
+	return r.list(func(s dto.WebhookSubscription) bool { return s.TenantID == tenantID && s.Active != nil && *s.Active }), nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) GetForDelivery(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	subscription.EventTypes = slices.Clone(subscription.EventTypes)
+
+	return &subscription, nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) RecordResult(ctx context.Context, id uint, success bool, maxFailures int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil
+	}
+
+	if success {
+		subscription.ConsecutiveFailures = 0
+	} else {
+		subscription.ConsecutiveFailures++
+		if subscription.ConsecutiveFailures >= maxFailures {
+			now := time.Now().UTC()
+			active := false
+			subscription.Active = &active
+			subscription.DisabledAt = &now
+		}
+	}
+	r.subscriptions[id] = subscription
+
+	return nil
+}
+
+func (r *memoryWebhookSubscriptionRepo) list(keep func(dto.WebhookSubscription) bool) []dto.WebhookSubscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := []dto.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if keep(subscription) {
+			subscription.EventTypes = slices.Clone(subscription.EventTypes)
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	slices.SortFunc(subscriptions, func(a, b dto.WebhookSubscription) int { return cmp.Compare(a.ID, b.ID) })
+
+	return subscriptions
+}
+
+// memoryWebhookDeliveryRepo leases claimed deliveries the same way as
+// webhookDeliveryRepo, by pushing their next attempt past the lease.
+type memoryWebhookDeliveryRepo struct {
+	mu         sync.RWMutex
+	deliveries []dto.WebhookDelivery
+}
+
+func NewMemoryWebhookDeliveryRepo() WebhookDeliveryRepo {
+	return &memoryWebhookDeliveryRepo{}
+}
+
+func (r *memoryWebhookDeliveryRepo) Enqueue(ctx context.Context, deliveries []dto.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		if slices.ContainsFunc(r.deliveries, func(d dto.WebhookDelivery) bool {
+			return d.SubscriptionID == delivery.SubscriptionID && d.EventID == delivery.EventID
+		}) {
+			continue
+		}
+
+		now := time.Now()
+		delivery.ID = uint64(len(r.deliveries) + 1)
+		if delivery.Status == "" {
+			delivery.Status = dto.WebhookDeliveryPending
+		}
+		if delivery.NextAttemptAt.IsZero() {
+			delivery.NextAttemptAt = now.UTC()
+		}
+		delivery.CreatedAt = now
+		r.deliveries = append(r.deliveries, delivery)
+	}
+
+	return nil
+}
+
+func (r *memoryWebhookDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]dto.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deliveries []dto.WebhookDelivery
+	for i := range r.deliveries {
+		if len(deliveries) == limit {
+			break
+		}
+
+		delivery := &r.deliveries[i]
+		if delivery.Status != dto.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		deliveries = append(deliveries, *delivery)
+		delivery.NextAttemptAt = now.Add(lease).UTC()
+	}
+
+	return deliveries, nil
+}
+
+func (r *memoryWebhookDeliveryRepo) MarkSucceeded(ctx context.Context, id uint64, statusCode int, at time.Time) error {
+	r.update(id, func(delivery *dto.WebhookDelivery) {
+		at := at.UTC()
+		delivery.Status = dto.WebhookDeliverySucceeded
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = ""
+		delivery.DeliveredAt = &at
+	})
+
+	return nil
+}
+
+func (r *memoryWebhookDeliveryRepo) MarkRetry(ctx context.Context, id uint64, statusCode int, lastError string, nextAttemptAt time.Time) error {
+	r.update(id, func(delivery *dto.WebhookDelivery) {
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = lastError
+		delivery.NextAttemptAt = nextAttemptAt.UTC()
+	})
+
+	return nil
+}
+
+func (r *memoryWebhookDeliveryRepo) MarkFailed(ctx context.Context, id uint64, statusCode int, lastError string) error {
+	r.update(id, func(delivery *dto.WebhookDelivery) {
+		delivery.Status = dto.WebhookDeliveryFailed
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = lastError
+	})
+
+	return nil
+}
+
+func (r *memoryWebhookDeliveryRepo) ListBySubscription(ctx context.Context, subscriptionID uint, page dto.PageRequest) ([]dto.WebhookDelivery, int64, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deliveries []dto.WebhookDelivery
+	for _, delivery := range slices.Backward(r.deliveries) {
+		if delivery.TenantID == tenantID && delivery.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return paginate(deliveries, page), int64(len(deliveries)), nil
+}
+
+func (r *memoryWebhookDeliveryRepo) update(id uint64, fn func(*dto.WebhookDelivery)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id >= 1 && id <= uint64(len(r.deliveries)) {
+		fn(&r.deliveries[id-1])
+	}
+}
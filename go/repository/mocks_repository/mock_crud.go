@@ -0,0 +1,205 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: crud.go
+//
+// Generated by this command:
+//
+//	mockgen -source=crud.go -destination=./mocks_repository/mock_crud.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	iter "iter"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepo is a mock of Repo interface.
+type MockRepo[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoMockRecorder[T]
+	isgomock struct{}
+}
+
+// MockRepoMockRecorder is the mock recorder for MockRepo.
+type MockRepoMockRecorder[T any] struct {
+	mock *MockRepo[T]
+}
+
+// NewMockRepo creates a new mock instance.
+func NewMockRepo[T any](ctrl *gomock.Controller) *MockRepo[T] {
+	mock := &MockRepo[T]{ctrl: ctrl}
+	mock.recorder = &MockRepoMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepo[T]) EXPECT() *MockRepoMockRecorder[T] {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepo[T]) Create(ctx context.Context, entity *T) (*T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, entity)
+	ret0, _ := ret[0].(*T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepoMockRecorder[T]) Create(ctx, entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepo[T])(nil).Create), ctx, entity)
+}
+
+// CreateBatch mocks base method.
+func (m *MockRepo[T]) CreateBatch(ctx context.Context, entities []T) ([]T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, entities)
+	ret0, _ := ret[0].([]T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockRepoMockRecorder[T]) CreateBatch(ctx, entities any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockRepo[T])(nil).CreateBatch), ctx, entities)
+}
+
+// Delete mocks base method.
+func (m *MockRepo[T]) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepoMockRecorder[T]) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepo[T])(nil).Delete), ctx, id)
+}
+
+// Exists mocks base method.
+func (m *MockRepo[T]) Exists(ctx context.Context, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockRepoMockRecorder[T]) Exists(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockRepo[T])(nil).Exists), ctx, id)
+}
+
+// Find mocks base method.
+func (m *MockRepo[T]) Find(ctx context.Context, query dto.ListQuery) ([]T, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, query)
+	ret0, _ := ret[0].([]T)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockRepoMockRecorder[T]) Find(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepo[T])(nil).Find), ctx, query)
+}
+
+// Get mocks base method.
+func (m *MockRepo[T]) Get(ctx context.Context, id uint) (*T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepoMockRecorder[T]) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepo[T])(nil).Get), ctx, id)
+}
+
+// GetDeleted mocks base method.
+func (m *MockRepo[T]) GetDeleted(ctx context.Context, id uint) (*T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", ctx, id)
+	ret0, _ := ret[0].(*T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockRepoMockRecorder[T]) GetDeleted(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockRepo[T])(nil).GetDeleted), ctx, id)
+}
+
+// Iterate mocks base method.
+func (m *MockRepo[T]) Iterate(ctx context.Context) iter.Seq2[T, error] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", ctx)
+	ret0, _ := ret[0].(iter.Seq2[T, error])
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate.
+func (mr *MockRepoMockRecorder[T]) Iterate(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockRepo[T])(nil).Iterate), ctx)
+}
+
+// List mocks base method.
+func (m *MockRepo[T]) List(ctx context.Context) ([]T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepoMockRecorder[T]) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepo[T])(nil).List), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockRepo[T]) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepoMockRecorder[T]) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepo[T])(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockRepo[T]) Update(ctx context.Context, entity *T, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, entity, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepoMockRecorder[T]) Update(ctx, entity, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepo[T])(nil).Update), ctx, entity, id)
+}
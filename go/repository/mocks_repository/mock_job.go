@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=job.go -destination=./mocks_repository/mock_job.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobRepo is a mock of JobRepo interface.
+type MockJobRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockJobRepoMockRecorder is the mock recorder for MockJobRepo.
+type MockJobRepoMockRecorder struct {
+	mock *MockJobRepo
+}
+
+// NewMockJobRepo creates a new mock instance.
+func NewMockJobRepo(ctrl *gomock.Controller) *MockJobRepo {
+	mock := &MockJobRepo{ctrl: ctrl}
+	mock.recorder = &MockJobRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepo) EXPECT() *MockJobRepoMockRecorder {
+	return m.recorder
+}
+
+// AddErrors mocks base method.
+func (m *MockJobRepo) AddErrors(ctx context.Context, jobErrors []dto.JobError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddErrors", ctx, jobErrors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddErrors indicates an expected call of AddErrors.
+func (mr *MockJobRepoMockRecorder) AddErrors(ctx, jobErrors any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddErrors", reflect.TypeOf((*MockJobRepo)(nil).AddErrors), ctx, jobErrors)
+}
+
+// Create mocks base method.
+func (m *MockJobRepo) Create(ctx context.Context, job *dto.Job) (*dto.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, job)
+	ret0, _ := ret[0].(*dto.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockJobRepoMockRecorder) Create(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockJobRepo)(nil).Create), ctx, job)
+}
+
+// FailExpired mocks base method.
+func (m *MockJobRepo) FailExpired(ctx context.Context, renewedBefore time.Time, lastError string, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailExpired", ctx, renewedBefore, lastError, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailExpired indicates an expected call of FailExpired.
+func (mr *MockJobRepoMockRecorder) FailExpired(ctx, renewedBefore, lastError, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailExpired", reflect.TypeOf((*MockJobRepo)(nil).FailExpired), ctx, renewedBefore, lastError, at)
+}
+
+// FailOwned mocks base method.
+func (m *MockJobRepo) FailOwned(ctx context.Context, owner, lastError string, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailOwned", ctx, owner, lastError, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailOwned indicates an expected call of FailOwned.
+func (mr *MockJobRepoMockRecorder) FailOwned(ctx, owner, lastError, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailOwned", reflect.TypeOf((*MockJobRepo)(nil).FailOwned), ctx, owner, lastError, at)
+}
+
+// Finish mocks base method.
+func (m *MockJobRepo) Finish(ctx context.Context, id uint64, status, lastError string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, id, status, lastError, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockJobRepoMockRecorder) Finish(ctx, id, status, lastError, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockJobRepo)(nil).Finish), ctx, id, status, lastError, at)
+}
+
+// Get mocks base method.
+func (m *MockJobRepo) Get(ctx context.Context, id uint64) (*dto.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*dto.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockJobRepoMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockJobRepo)(nil).Get), ctx, id)
+}
+
+// ListErrors mocks base method.
+func (m *MockJobRepo) ListErrors(ctx context.Context, id uint64) ([]dto.JobError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListErrors", ctx, id)
+	ret0, _ := ret[0].([]dto.JobError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListErrors indicates an expected call of ListErrors.
+func (mr *MockJobRepoMockRecorder) ListErrors(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListErrors", reflect.TypeOf((*MockJobRepo)(nil).ListErrors), ctx, id)
+}
+
+// Renew mocks base method.
+func (m *MockJobRepo) Renew(ctx context.Context, owner string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, owner, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockJobRepoMockRecorder) Renew(ctx, owner, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockJobRepo)(nil).Renew), ctx, owner, at)
+}
+
+// Start mocks base method.
+func (m *MockJobRepo) Start(ctx context.Context, id uint64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockJobRepoMockRecorder) Start(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockJobRepo)(nil).Start), ctx, id, at)
+}
+
+// UpdateProgress mocks base method.
+func (m *MockJobRepo) UpdateProgress(ctx context.Context, id uint64, progress dto.JobProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProgress", ctx, id, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress.
+func (mr *MockJobRepoMockRecorder) UpdateProgress(ctx, id, progress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockJobRepo)(nil).UpdateProgress), ctx, id, progress)
+}
@@ -12,7 +12,9 @@ package mock_repository
 import (
 	context "context"
 	dto "crud_app/dto"
+	iter "iter"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,18 +44,33 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockUserRepo) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+func (m *MockUserRepo) Create(ctx context.Context, entity *dto.User) (*dto.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, user)
+	ret := m.ctrl.Call(m, "Create", ctx, entity)
 	ret0, _ := ret[0].(*dto.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserRepoMockRecorder) Create(ctx, user any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Create(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepo)(nil).Create), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepo)(nil).Create), ctx, entity)
+}
+
+// CreateBatch mocks base method.
+func (m *MockUserRepo) CreateBatch(ctx context.Context, entities []dto.User) ([]dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, entities)
+	ret0, _ := ret[0].([]dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockUserRepoMockRecorder) CreateBatch(ctx, entities any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockUserRepo)(nil).CreateBatch), ctx, entities)
 }
 
 // Delete mocks base method.
@@ -85,6 +102,96 @@ func (mr *MockUserRepoMockRecorder) Exists(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockUserRepo)(nil).Exists), ctx, id)
 }
 
+// Find mocks base method.
+func (m *MockUserRepo) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, query)
+	ret0, _ := ret[0].([]dto.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockUserRepoMockRecorder) Find(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockUserRepo)(nil).Find), ctx, query)
+}
+
+// Get mocks base method.
+func (m *MockUserRepo) Get(ctx context.Context, id uint) (*dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserRepoMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserRepo)(nil).Get), ctx, id)
+}
+
+// GetAsOf mocks base method.
+func (m *MockUserRepo) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAsOf", ctx, id, at)
+	ret0, _ := ret[0].(*dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAsOf indicates an expected call of GetAsOf.
+func (mr *MockUserRepoMockRecorder) GetAsOf(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAsOf", reflect.TypeOf((*MockUserRepo)(nil).GetAsOf), ctx, id, at)
+}
+
+// GetDeleted mocks base method.
+func (m *MockUserRepo) GetDeleted(ctx context.Context, id uint) (*dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", ctx, id)
+	ret0, _ := ret[0].(*dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockUserRepoMockRecorder) GetDeleted(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockUserRepo)(nil).GetDeleted), ctx, id)
+}
+
+// Iterate mocks base method.
+func (m *MockUserRepo) Iterate(ctx context.Context) iter.Seq2[dto.User, error] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", ctx)
+	ret0, _ := ret[0].(iter.Seq2[dto.User, error])
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate.
+func (mr *MockUserRepoMockRecorder) Iterate(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockUserRepo)(nil).Iterate), ctx)
+}
+
+// LastModified mocks base method.
+func (m *MockUserRepo) LastModified(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastModified", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastModified indicates an expected call of LastModified.
+func (mr *MockUserRepoMockRecorder) LastModified(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastModified", reflect.TypeOf((*MockUserRepo)(nil).LastModified), ctx)
+}
+
 // List mocks base method.
 func (m *MockUserRepo) List(ctx context.Context) ([]dto.User, error) {
 	m.ctrl.T.Helper()
@@ -100,16 +207,59 @@ func (mr *MockUserRepoMockRecorder) List(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepo)(nil).List), ctx)
 }
 
+// ListAsOf mocks base method.
+func (m *MockUserRepo) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAsOf", ctx, at)
+	ret0, _ := ret[0].([]dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAsOf indicates an expected call of ListAsOf.
+func (mr *MockUserRepoMockRecorder) ListAsOf(ctx, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAsOf", reflect.TypeOf((*MockUserRepo)(nil).ListAsOf), ctx, at)
+}
+
+// Purge mocks base method.
+func (m *MockUserRepo) Purge(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockUserRepoMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockUserRepo)(nil).Purge), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockUserRepo) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserRepoMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserRepo)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockUserRepo) Update(ctx context.Context, user *dto.User, id uint) error {
+func (m *MockUserRepo) Update(ctx context.Context, entity *dto.User, id uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, user, id)
+	ret := m.ctrl.Call(m, "Update", ctx, entity, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserRepoMockRecorder) Update(ctx, user, id any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Update(ctx, entity, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepo)(nil).Update), ctx, user, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepo)(nil).Update), ctx, entity, id)
 }
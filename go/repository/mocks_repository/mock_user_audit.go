@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: user_audit.go
+//
+// Generated by this command:
+//
+//	mockgen -source=user_audit.go -destination=./mocks_repository/mock_user_audit.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserAuditRepo is a mock of UserAuditRepo interface.
+type MockUserAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserAuditRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockUserAuditRepoMockRecorder is the mock recorder for MockUserAuditRepo.
+type MockUserAuditRepoMockRecorder struct {
+	mock *MockUserAuditRepo
+}
+
+// NewMockUserAuditRepo creates a new mock instance.
+func NewMockUserAuditRepo(ctrl *gomock.Controller) *MockUserAuditRepo {
+	mock := &MockUserAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockUserAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserAuditRepo) EXPECT() *MockUserAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockUserAuditRepo) Append(ctx context.Context, entry *dto.UserAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockUserAuditRepoMockRecorder) Append(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockUserAuditRepo)(nil).Append), ctx, entry)
+}
+
+// ListByUser mocks base method.
+func (m *MockUserAuditRepo) ListByUser(ctx context.Context, userID uint, page dto.PageRequest) ([]dto.UserAudit, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, page)
+	ret0, _ := ret[0].([]dto.UserAudit)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockUserAuditRepoMockRecorder) ListByUser(ctx, userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockUserAuditRepo)(nil).ListByUser), ctx, userID, page)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook_delivery.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook_delivery.go -destination=./mocks_repository/mock_webhook_delivery.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookDeliveryRepo is a mock of WebhookDeliveryRepo interface.
+type MockWebhookDeliveryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookDeliveryRepoMockRecorder is the mock recorder for MockWebhookDeliveryRepo.
+type MockWebhookDeliveryRepoMockRecorder struct {
+	mock *MockWebhookDeliveryRepo
+}
+
+// NewMockWebhookDeliveryRepo creates a new mock instance.
+func NewMockWebhookDeliveryRepo(ctrl *gomock.Controller) *MockWebhookDeliveryRepo {
+	mock := &MockWebhookDeliveryRepo{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryRepo) EXPECT() *MockWebhookDeliveryRepoMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockWebhookDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]dto.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]dto.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockWebhookDeliveryRepoMockRecorder) ClaimDue(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).ClaimDue), ctx, now, lease, limit)
+}
+
+// Enqueue mocks base method.
+func (m *MockWebhookDeliveryRepo) Enqueue(ctx context.Context, deliveries []dto.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockWebhookDeliveryRepoMockRecorder) Enqueue(ctx, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).Enqueue), ctx, deliveries)
+}
+
+// ListBySubscription mocks base method.
+func (m *MockWebhookDeliveryRepo) ListBySubscription(ctx context.Context, subscriptionID uint, page dto.PageRequest) ([]dto.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySubscription", ctx, subscriptionID, page)
+	ret0, _ := ret[0].([]dto.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBySubscription indicates an expected call of ListBySubscription.
+func (mr *MockWebhookDeliveryRepoMockRecorder) ListBySubscription(ctx, subscriptionID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySubscription", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).ListBySubscription), ctx, subscriptionID, page)
+}
+
+// MarkFailed mocks base method.
+func (m *MockWebhookDeliveryRepo) MarkFailed(ctx context.Context, id uint64, statusCode int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, statusCode, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockWebhookDeliveryRepoMockRecorder) MarkFailed(ctx, id, statusCode, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).MarkFailed), ctx, id, statusCode, lastError)
+}
+
+// MarkRetry mocks base method.
+func (m *MockWebhookDeliveryRepo) MarkRetry(ctx context.Context, id uint64, statusCode int, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRetry", ctx, id, statusCode, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRetry indicates an expected call of MarkRetry.
+func (mr *MockWebhookDeliveryRepoMockRecorder) MarkRetry(ctx, id, statusCode, lastError, nextAttemptAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRetry", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).MarkRetry), ctx, id, statusCode, lastError, nextAttemptAt)
+}
+
+// MarkSucceeded mocks base method.
+func (m *MockWebhookDeliveryRepo) MarkSucceeded(ctx context.Context, id uint64, statusCode int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSucceeded", ctx, id, statusCode, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSucceeded indicates an expected call of MarkSucceeded.
+func (mr *MockWebhookDeliveryRepoMockRecorder) MarkSucceeded(ctx, id, statusCode, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSucceeded", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).MarkSucceeded), ctx, id, statusCode, at)
+}
@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook_subscription.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook_subscription.go -destination=./mocks_repository/mock_webhook_subscription.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookSubscriptionRepo is a mock of WebhookSubscriptionRepo interface.
+type MockWebhookSubscriptionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSubscriptionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookSubscriptionRepoMockRecorder is the mock recorder for MockWebhookSubscriptionRepo.
+type MockWebhookSubscriptionRepoMockRecorder struct {
+	mock *MockWebhookSubscriptionRepo
+}
+
+// NewMockWebhookSubscriptionRepo creates a new mock instance.
+func NewMockWebhookSubscriptionRepo(ctrl *gomock.Controller) *MockWebhookSubscriptionRepo {
+	mock := &MockWebhookSubscriptionRepo{ctrl: ctrl}
+	mock.recorder = &MockWebhookSubscriptionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookSubscriptionRepo) EXPECT() *MockWebhookSubscriptionRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookSubscriptionRepo) Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subscription)
+	ret0, _ := ret[0].(*dto.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) Create(ctx, subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).Create), ctx, subscription)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookSubscriptionRepo) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockWebhookSubscriptionRepo) Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*dto.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).Get), ctx, id)
+}
+
+// GetForDelivery mocks base method.
+func (m *MockWebhookSubscriptionRepo) GetForDelivery(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForDelivery", ctx, id)
+	ret0, _ := ret[0].(*dto.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForDelivery indicates an expected call of GetForDelivery.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) GetForDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForDelivery", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).GetForDelivery), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhookSubscriptionRepo) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]dto.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).List), ctx)
+}
+
+// ListActive mocks base method.
+func (m *MockWebhookSubscriptionRepo) ListActive(ctx context.Context, tenantID string) ([]dto.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx, tenantID)
+	ret0, _ := ret[0].([]dto.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) ListActive(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).ListActive), ctx, tenantID)
+}
+
+// RecordResult mocks base method.
+func (m *MockWebhookSubscriptionRepo) RecordResult(ctx context.Context, id uint, success bool, maxFailures int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordResult", ctx, id, success, maxFailures)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordResult indicates an expected call of RecordResult.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) RecordResult(ctx, id, success, maxFailures any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordResult", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).RecordResult), ctx, id, success, maxFailures)
+}
+
+// Update mocks base method.
+func (m *MockWebhookSubscriptionRepo) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, subscription, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookSubscriptionRepoMockRecorder) Update(ctx, subscription, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookSubscriptionRepo)(nil).Update), ctx, subscription, id)
+}
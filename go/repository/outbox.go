@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+const (
+	outboxTableName         string = "outbox"
+	outboxSequenceTableName string = "outbox_sequence"
+)
+
+type OutboxRepo interface {
+	Add(ctx context.Context, event *dto.OutboxEvent) error
+	ClaimPending(ctx context.Context, now time.Time, limit int) ([]dto.OutboxEvent, error)
+	AssignSequences(ctx context.Context, events []dto.OutboxEvent) error
+	MarkPublished(ctx context.Context, id uint64, at time.Time) error
+	MarkFailed(ctx context.Context, id uint64, nextAttemptAt time.Time, lastError string) error
+	ListSince(ctx context.Context, afterSequence uint64, eventTypes []string, limit int) ([]dto.OutboxEvent, error)
+	ListSequenced(ctx context.Context, afterSequence uint64, limit int) ([]dto.OutboxEvent, error)
+	LastSequence(ctx context.Context) (uint64, error)
+}
+
+type outboxRepo struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepo(db *gorm.DB) OutboxRepo {
+	return &outboxRepo{db: db}
+}
+
+func (r *outboxRepo) Add(ctx context.Context, event *dto.OutboxEvent) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	event.TenantID = tenantID
+
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = time.Now().UTC()
+	}
+
+	return conn(ctx, r.db).
+		Table(outboxTableName).
+		Create(event).
+		Error
+}
+
+// ClaimPending locks due events until the surrounding transaction ends, so
+// concurrent relays never pick up the same event.
+func (r *outboxRepo) ClaimPending(ctx context.Context, now time.Time, limit int) ([]dto.OutboxEvent, error) {
+	var events []dto.OutboxEvent
+
+	return events, conn(ctx, r.db).
+		Table(outboxTableName).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at is null").
+		Where("next_attempt_at <= ?", now.UTC()).
+		Order("id").
+		Limit(limit).
+		Find(&events).
+		Error
+}
+
+// AssignSequences numbers the events that have no sequence yet, in order,
+// and sets Sequence on them. Taking the numbers locks the counter row until
+// the surrounding transaction ends, so transactions hold increasing numbers
+// in the order they commit and a reader never sees a number before a lower
+// one.
+func (r *outboxRepo) AssignSequences(ctx context.Context, events []dto.OutboxEvent) error {
+	var unsequenced []int
+	for i := range events {
+		if events[i].Sequence == nil {
+			unsequenced = append(unsequenced, i)
+		}
+	}
+	if len(unsequenced) == 0 {
+		return nil
+	}
+
+	db := conn(ctx, r.db)
+
+	var last uint64
+	err := db.
+		Raw("UPDATE "+outboxSequenceTableName+" SET value = value + ? WHERE id = 1 RETURNING value", len(unsequenced)).
+		Scan(&last).
+		Error
+	if err != nil {
+		return err
+	}
+
+	first := last - uint64(len(unsequenced)) + 1
+	for n, i := range unsequenced {
+		sequence := first + uint64(n)
+		err := db.
+			Table(outboxTableName).
+			Where("id = ?", events[i].ID).
+			Update("sequence", sequence).
+			Error
+		if err != nil {
+			return err
+		}
+		events[i].Sequence = &sequence
+	}
+
+	return nil
+}
+
+func (r *outboxRepo) MarkPublished(ctx context.Context, id uint64, at time.Time) error {
+	return conn(ctx, r.db).
+		Table(outboxTableName).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"published_at": at.UTC(),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		}).
+		Error
+}
+
+func (r *outboxRepo) MarkFailed(ctx context.Context, id uint64, nextAttemptAt time.Time, lastError string) error {
+	return conn(ctx, r.db).
+		Table(outboxTableName).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"next_attempt_at": nextAttemptAt.UTC(),
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+		}).
+		Error
+}
+
+// ListSince reads the tenant's change log in sequence order, starting after
+// afterSequence; an empty eventTypes matches every type. Events the relay has
+// not sequenced yet are not part of the log.
+func (r *outboxRepo) ListSince(ctx context.Context, afterSequence uint64, eventTypes []string, limit int) ([]dto.OutboxEvent, error) {
+	var events []dto.OutboxEvent
+
+	query := conn(ctx, r.db).
+		Table(outboxTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("sequence > ?", afterSequence)
+	if len(eventTypes) > 0 {
+		query = query.Where("event_type in ?", eventTypes)
+	}
+
+	return events, query.
+		Order("sequence").
+		Limit(limit).
+		Find(&events).
+		Error
+}
+
+// ListSequenced is ListSince across every tenant.
+func (r *outboxRepo) ListSequenced(ctx context.Context, afterSequence uint64, limit int) ([]dto.OutboxEvent, error) {
+	var events []dto.OutboxEvent
+
+	return events, conn(ctx, r.db).
+		Table(outboxTableName).
+		Where("sequence > ?", afterSequence).
+		Order("sequence").
+		Limit(limit).
+		Find(&events).
+		Error
+}
+
+func (r *outboxRepo) LastSequence(ctx context.Context) (uint64, error) {
+	var last uint64
+
+	return last, conn(ctx, r.db).
+		Table(outboxTableName).
+		Select("COALESCE(MAX(sequence), 0)").
+		Scan(&last).
+		Error
+}
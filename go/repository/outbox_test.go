@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/config"
+	"crud_app/dto"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+func newSQLiteOutbox(t *testing.T) (repository.OutboxRepo, repository.Transactor) {
+	t.Setenv("DB_DRIVER", config.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "crud_app.db"))
+
+	db, err := config.ConnectDB()
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	return repository.NewOutboxRepo(db), repository.NewTransactor(db)
+}
+
+// relay sequences what is pending the way the relay does and returns the
+// sequences it assigned.
+func relay(t *testing.T, outboxRepo repository.OutboxRepo, transactor repository.Transactor) []uint64 {
+	t.Helper()
+
+	var sequences []uint64
+	err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		pending, err := outboxRepo.ClaimPending(ctx, time.Now().Add(time.Minute), 10)
+		if err != nil {
+			return err
+		}
+		if err := outboxRepo.AssignSequences(ctx, pending); err != nil {
+			return err
+		}
+		for _, event := range pending {
+			sequences = append(sequences, *event.Sequence)
+			if err := outboxRepo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	return sequences
+}
+
+// TestOutboxRepo_OutOfOrderCommit has a write that took ID 1 commit after
+// the write that took ID 2, as concurrent transactions do, and checks that
+// a reader who already saw the second still gets the first.
+func TestOutboxRepo_OutOfOrderCommit(t *testing.T) {
+	outboxRepo, transactor := newSQLiteOutbox(t)
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	require.NoError(t, outboxRepo.Add(ctx, &dto.OutboxEvent{ID: 2, EventType: "UserUpdated", AggregateID: 8, Payload: json.RawMessage(`{}`)}))
+	require.Equal(t, []uint64{1}, relay(t, outboxRepo, transactor))
+
+	seen, err := outboxRepo.ListSince(ctx, 0, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, seen, 1)
+	require.EqualValues(t, 2, seen[0].ID)
+	lastSeen := *seen[0].Sequence
+
+	require.NoError(t, outboxRepo.Add(ctx, &dto.OutboxEvent{ID: 1, EventType: "UserCreated", AggregateID: 7, Payload: json.RawMessage(`{}`)}))
+
+	// Not in the log before the relay has sequenced it.
+	missed, err := outboxRepo.ListSince(ctx, lastSeen, nil, 10)
+	require.NoError(t, err)
+	require.Empty(t, missed)
+
+	require.Equal(t, []uint64{2}, relay(t, outboxRepo, transactor))
+
+	missed, err = outboxRepo.ListSince(ctx, lastSeen, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, missed, 1)
+	require.EqualValues(t, 1, missed[0].ID)
+	require.EqualValues(t, 2, *missed[0].Sequence)
+}
+
+func TestOutboxRepo_Sequences(t *testing.T) {
+	outboxRepo, transactor := newSQLiteOutbox(t)
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+
+	last, err := outboxRepo.LastSequence(acme)
+	require.NoError(t, err)
+	require.Zero(t, last)
+
+	require.NoError(t, outboxRepo.Add(acme, &dto.OutboxEvent{EventType: "UserCreated", AggregateID: 1, Payload: json.RawMessage(`{}`)}))
+	require.NoError(t, outboxRepo.Add(globex, &dto.OutboxEvent{EventType: "UserCreated", AggregateID: 1, Payload: json.RawMessage(`{}`)}))
+	require.NoError(t, outboxRepo.Add(acme, &dto.OutboxEvent{EventType: "UserDeleted", AggregateID: 1, Payload: json.RawMessage(`{}`)}))
+	require.Equal(t, []uint64{1, 2, 3}, relay(t, outboxRepo, transactor))
+
+	// Published events are not claimed, so nothing is numbered twice.
+	require.Empty(t, relay(t, outboxRepo, transactor))
+
+	last, err = outboxRepo.LastSequence(acme)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, last)
+
+	all, err := outboxRepo.ListSequenced(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, "globex", all[0].TenantID)
+	require.Equal(t, "acme", all[1].TenantID)
+
+	deleted, err := outboxRepo.ListSince(acme, 0, []string{"UserDeleted"}, 10)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.EqualValues(t, 3, *deleted[0].Sequence)
+
+	// An event that already has a sequence keeps it.
+	err = transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		return outboxRepo.AssignSequences(ctx, deleted)
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, *deleted[0].Sequence)
+}
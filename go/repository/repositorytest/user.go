@@ -0,0 +1,601 @@
+// Package repositorytest holds conformance suites that every implementation
+// of a repository interface has to pass, whatever its storage.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+const (
+	testTenant  = "conformance"
+	otherTenant = "conformance-other"
+)
+
+// UserRepoFactory returns an empty UserRepo. The suite calls it once per
+// case, so implementations that persist data should hand out a fresh store
+// each time. Users of other tenants may already be there.
+type UserRepoFactory func(t *testing.T) repository.UserRepo
+
+// RunUserRepo checks that a UserRepo behaves like repository.userRepo.
+func RunUserRepo(t *testing.T, newRepo UserRepoFactory) {
+	type testCase struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, repo repository.UserRepo)
+	}
+
+	cases := []testCase{
+		{
+			name: "create assigns id and timestamps",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+				require.NoError(t, err)
+				require.NotZero(t, created.ID)
+				require.Equal(t, testTenant, created.TenantID)
+				require.False(t, created.CreatedAt.IsZero())
+				require.False(t, created.UpdatedAt.IsZero())
+
+				got, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				requireUser(t, created, got)
+			},
+		}, {
+			name: "get unknown id",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				got, err := repo.Get(ctx, 1_000_000)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+				require.Nil(t, got)
+
+				got, err = repo.GetDeleted(ctx, 1_000_000)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+				require.Nil(t, got)
+			},
+		}, {
+			name: "create batch",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created, err := repo.CreateBatch(ctx, []dto.User{{Name: "John", Age: 10}, {Name: "Jane", Age: 11}})
+				require.NoError(t, err)
+				require.Len(t, created, 2)
+				require.NotZero(t, created[0].ID)
+				require.Less(t, created[0].ID, created[1].ID)
+
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Len(t, users, 2)
+				requireUser(t, &created[0], &users[0])
+				requireUser(t, &created[1], &users[1])
+
+				empty, err := repo.CreateBatch(ctx, nil)
+				require.NoError(t, err)
+				require.Empty(t, empty)
+			},
+		}, {
+			name: "update replaces fields",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+
+				require.NoError(t, repo.Update(ctx, &dto.User{Name: "Johnny", Age: 11}, created.ID))
+
+				got, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				require.Equal(t, "Johnny", got.Name)
+				require.Equal(t, 11, got.Age)
+				require.Equal(t, testTenant, got.TenantID)
+				require.True(t, created.CreatedAt.Equal(got.CreatedAt))
+				require.False(t, got.UpdatedAt.Before(created.UpdatedAt))
+			},
+		}, {
+			name: "update sets fields back to zero",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+
+				require.NoError(t, repo.Update(ctx, &dto.User{Name: "John"}, created.ID))
+
+				got, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				require.Equal(t, "John", got.Name)
+				require.Zero(t, got.Age)
+			},
+		}, {
+			name: "update unknown id is a no-op",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				require.NoError(t, repo.Update(ctx, &dto.User{Name: "Johnny", Age: 11}, 1_000_000))
+
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Empty(t, users)
+			},
+		}, {
+			name: "deleted user is hidden",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+				kept := mustCreate(t, ctx, repo, "Jane", 11)
+
+				require.NoError(t, repo.Delete(ctx, created.ID))
+
+				_, err := repo.Get(ctx, created.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+
+				exists, err := repo.Exists(ctx, created.ID)
+				require.NoError(t, err)
+				require.False(t, exists)
+
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				require.Equal(t, kept.ID, users[0].ID)
+
+				deleted, err := repo.GetDeleted(ctx, created.ID)
+				require.NoError(t, err)
+				require.Equal(t, created.ID, deleted.ID)
+				require.Equal(t, "John", deleted.Name)
+				require.True(t, deleted.DeletedAt.Valid)
+
+				_, err = repo.GetDeleted(ctx, kept.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		}, {
+			name: "update leaves deleted user alone",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+				require.NoError(t, repo.Delete(ctx, created.ID))
+
+				require.NoError(t, repo.Update(ctx, &dto.User{Name: "Johnny", Age: 11}, created.ID))
+
+				deleted, err := repo.GetDeleted(ctx, created.ID)
+				require.NoError(t, err)
+				require.Equal(t, "John", deleted.Name)
+				require.Equal(t, 10, deleted.Age)
+			},
+		}, {
+			name: "restore brings user back",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+				require.NoError(t, repo.Delete(ctx, created.ID))
+
+				require.NoError(t, repo.Restore(ctx, created.ID))
+
+				got, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				require.False(t, got.DeletedAt.Valid)
+
+				_, err = repo.GetDeleted(ctx, created.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+
+				exists, err := repo.Exists(ctx, created.ID)
+				require.NoError(t, err)
+				require.True(t, exists)
+			},
+		}, {
+			name: "delete and restore are idempotent",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+
+				require.NoError(t, repo.Restore(ctx, created.ID))
+				_, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+
+				require.NoError(t, repo.Delete(ctx, created.ID))
+				require.NoError(t, repo.Delete(ctx, created.ID))
+				_, err = repo.GetDeleted(ctx, created.ID)
+				require.NoError(t, err)
+
+				require.NoError(t, repo.Delete(ctx, 1_000_000))
+				require.NoError(t, repo.Restore(ctx, 1_000_000))
+			},
+		}, {
+			name: "purge removes user and versions",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				live := mustCreate(t, ctx, repo, "John", 10)
+				deleted := mustCreate(t, ctx, repo, "Jane", 11)
+				kept := mustCreate(t, ctx, repo, "Jim", 12)
+				require.NoError(t, repo.Delete(ctx, deleted.ID))
+				before := time.Now()
+
+				require.NoError(t, repo.Purge(ctx, live.ID))
+				require.NoError(t, repo.Purge(ctx, deleted.ID))
+				require.NoError(t, repo.Purge(ctx, 1_000_000))
+
+				_, err := repo.Get(ctx, live.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+				_, err = repo.GetDeleted(ctx, deleted.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+				_, err = repo.GetAsOf(ctx, live.ID, before)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+
+				users, err := repo.ListAsOf(ctx, before)
+				require.NoError(t, err)
+				require.Equal(t, []uint{kept.ID}, userIDs(users))
+
+				require.NoError(t, repo.Update(ctx, &dto.User{Name: "Jimmy", Age: 13}, kept.ID))
+				got, err := repo.GetAsOf(ctx, kept.ID, time.Now())
+				require.NoError(t, err)
+				require.Equal(t, "Jimmy", got.Name)
+			},
+		}, {
+			name: "exists",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+
+				exists, err := repo.Exists(ctx, created.ID)
+				require.NoError(t, err)
+				require.True(t, exists)
+
+				exists, err = repo.Exists(ctx, 1_000_000)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+		}, {
+			name: "list and iterate in id order",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				var ids []uint
+				for i := range 5 {
+					ids = append(ids, mustCreate(t, ctx, repo, fmt.Sprintf("User %d", i), 10+i).ID)
+				}
+				require.NoError(t, repo.Delete(ctx, ids[2]))
+				require.NoError(t, repo.Update(ctx, &dto.User{Name: "Renamed", Age: 10}, ids[0]))
+
+				want := []uint{ids[0], ids[1], ids[3], ids[4]}
+
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Equal(t, want, userIDs(users))
+
+				var iterated []dto.User
+				for user, err := range repo.Iterate(ctx) {
+					require.NoError(t, err)
+					iterated = append(iterated, user)
+				}
+				require.Equal(t, want, userIDs(iterated))
+			},
+		}, {
+			name: "find filters and pages",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				var ids []uint
+				for i := range 5 {
+					ids = append(ids, mustCreate(t, ctx, repo, fmt.Sprintf("User %d", i), 10+i%2).ID)
+				}
+				require.NoError(t, repo.Delete(ctx, ids[2]))
+
+				users, total, err := repo.Find(ctx, dto.ListQuery{Filters: map[string]string{"age": "10"}})
+				require.NoError(t, err)
+				require.EqualValues(t, 2, total)
+				require.Equal(t, []uint{ids[0], ids[4]}, userIDs(users))
+
+				users, total, err = repo.Find(ctx, dto.ListQuery{PageRequest: dto.PageRequest{Limit: 2, Offset: 1}})
+				require.NoError(t, err)
+				require.EqualValues(t, 4, total)
+				require.Equal(t, []uint{ids[1], ids[3]}, userIDs(users))
+
+				users, total, err = repo.Find(ctx, dto.ListQuery{Filters: map[string]string{"Name": "User 3", "age": "11"}})
+				require.NoError(t, err)
+				require.EqualValues(t, 1, total)
+				require.Equal(t, []uint{ids[3]}, userIDs(users))
+			},
+		}, {
+			name: "find compares with conditions",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				ann := mustCreate(t, ctx, repo, "Ann", 34)
+				mustCreate(t, ctx, repo, "Bob", 17)
+				annette := mustCreate(t, ctx, repo, "Annette", 61)
+				mustCreate(t, ctx, repo, "50%_off", 40)
+
+				users, total, err := repo.Find(ctx, dto.ListQuery{Conditions: []dto.Condition{
+					{Field: "name", Op: dto.OpContains, Value: "ANN"},
+				}})
+				require.NoError(t, err)
+				require.EqualValues(t, 2, total)
+				require.Equal(t, []uint{ann.ID, annette.ID}, userIDs(users))
+
+				users, _, err = repo.Find(ctx, dto.ListQuery{Conditions: []dto.Condition{
+					{Field: "name", Op: dto.OpContains, Value: "ann"},
+					{Field: "age", Op: dto.OpGreaterOrEqual, Value: "18"},
+					{Field: "age", Op: dto.OpLessOrEqual, Value: "60"},
+				}})
+				require.NoError(t, err)
+				require.Equal(t, []uint{ann.ID}, userIDs(users))
+
+				users, total, err = repo.Find(ctx, dto.ListQuery{
+					PageRequest: dto.PageRequest{Limit: 1},
+					Conditions: []dto.Condition{
+						{Field: "id", Op: dto.OpGreater, Value: strconv.FormatUint(uint64(ann.ID), 10)},
+						{Field: "age", Op: dto.OpLess, Value: "60"},
+					},
+				})
+				require.NoError(t, err)
+				require.EqualValues(t, 2, total)
+				require.Len(t, users, 1)
+				require.Equal(t, "Bob", users[0].Name)
+
+				// LIKE wildcards in the value match themselves only.
+				users, _, err = repo.Find(ctx, dto.ListQuery{Conditions: []dto.Condition{
+					{Field: "name", Op: dto.OpContains, Value: "%_"},
+				}})
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				require.Equal(t, "50%_off", users[0].Name)
+			},
+		}, {
+			name: "find rejects invalid filters",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				filters := []map[string]string{
+					{"nickname": "Johnny"},
+					{"age": "ten"},
+					{"deleted_at": "2025-09-17"},
+					{"tenant_id": otherTenant},
+				}
+				for _, f := range filters {
+					_, _, err := repo.Find(ctx, dto.ListQuery{Filters: f})
+					require.ErrorIs(t, err, repository.ErrInvalidFilter)
+				}
+
+				conditions := []dto.Condition{
+					{Field: "age", Op: dto.OpContains, Value: "1"},
+					{Field: "name", Op: "~", Value: "J"},
+					{Field: "age", Op: dto.OpGreater, Value: "ten"},
+					{Field: "nickname", Op: dto.OpLess, Value: "J"},
+				}
+				for _, c := range conditions {
+					_, _, err := repo.Find(ctx, dto.ListQuery{Conditions: []dto.Condition{c}})
+					require.ErrorIs(t, err, repository.ErrInvalidFilter)
+				}
+			},
+		}, {
+			name: "iterate stops early",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				mustCreate(t, ctx, repo, "John", 10)
+				mustCreate(t, ctx, repo, "Jane", 11)
+
+				var seen int
+				for _, err := range repo.Iterate(ctx) {
+					require.NoError(t, err)
+					seen++
+					break
+				}
+				require.Equal(t, 1, seen)
+
+				_, err := repo.Create(ctx, &dto.User{Name: "Jack", Age: 12})
+				require.NoError(t, err)
+			},
+		}, {
+			name: "last modified follows every write",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				lastModified, err := repo.LastModified(ctx)
+				require.NoError(t, err)
+				require.True(t, lastModified.IsZero())
+
+				created := mustCreate(t, ctx, repo, "John", 10)
+				writes := []func() error{
+					func() error { return repo.Update(ctx, &dto.User{Name: "Johnny", Age: 10}, created.ID) },
+					func() error { return repo.Delete(ctx, created.ID) },
+					func() error { return repo.Restore(ctx, created.ID) },
+					func() error {
+						_, err := repo.CreateBatch(ctx, []dto.User{{Name: "Jane", Age: 11}})
+						return err
+					},
+					func() error { return repo.Purge(ctx, created.ID) },
+				}
+
+				previous, err := repo.LastModified(ctx)
+				require.NoError(t, err)
+				require.False(t, previous.IsZero())
+				for _, write := range writes {
+					require.NoError(t, write())
+
+					lastModified, err := repo.LastModified(ctx)
+					require.NoError(t, err)
+					require.True(t, lastModified.After(previous), "%s is not after %s", lastModified, previous)
+					previous = lastModified
+				}
+
+				// Writes of another tenant leave it alone.
+				mustCreate(t, tenant.WithID(ctx, otherTenant), repo, "Other", 1)
+				lastModified, err = repo.LastModified(ctx)
+				require.NoError(t, err)
+				require.Equal(t, previous, lastModified)
+			},
+		}, {
+			name: "ids are not reused",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				first := mustCreate(t, ctx, repo, "John", 10)
+				require.NoError(t, repo.Delete(ctx, first.ID))
+
+				second := mustCreate(t, ctx, repo, "Jane", 11)
+				require.Greater(t, second.ID, first.ID)
+			},
+		}, {
+			name: "tenants are isolated",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+				deleted := mustCreate(t, ctx, repo, "Jane", 11)
+				require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+				other := tenant.WithID(context.Background(), otherTenant)
+
+				users, err := repo.List(other)
+				require.NoError(t, err)
+				require.Empty(t, users)
+
+				_, err = repo.Get(other, created.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+
+				_, err = repo.GetDeleted(other, deleted.ID)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+
+				exists, err := repo.Exists(other, created.ID)
+				require.NoError(t, err)
+				require.False(t, exists)
+
+				require.NoError(t, repo.Update(other, &dto.User{Name: "Johnny", Age: 11}, created.ID))
+				require.NoError(t, repo.Delete(other, created.ID))
+				require.NoError(t, repo.Restore(other, deleted.ID))
+				require.NoError(t, repo.Purge(other, created.ID))
+
+				got, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				require.Equal(t, "John", got.Name)
+
+				_, err = repo.GetDeleted(ctx, deleted.ID)
+				require.NoError(t, err)
+			},
+		}, {
+			name: "tenant is required",
+			run: func(t *testing.T, _ context.Context, repo repository.UserRepo) {
+				ctx := context.Background()
+
+				_, err := repo.List(ctx)
+				require.ErrorIs(t, err, tenant.ErrRequired)
+
+				_, err = repo.Get(ctx, 1)
+				require.ErrorIs(t, err, tenant.ErrRequired)
+
+				_, err = repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+				require.ErrorIs(t, err, tenant.ErrRequired)
+
+				_, err = repo.CreateBatch(ctx, []dto.User{{Name: "John", Age: 10}})
+				require.ErrorIs(t, err, tenant.ErrRequired)
+
+				require.ErrorIs(t, repo.Update(ctx, &dto.User{Name: "John"}, 1), tenant.ErrRequired)
+				require.ErrorIs(t, repo.Delete(ctx, 1), tenant.ErrRequired)
+				require.ErrorIs(t, repo.Restore(ctx, 1), tenant.ErrRequired)
+
+				_, err = repo.Exists(ctx, 1)
+				require.ErrorIs(t, err, tenant.ErrRequired)
+
+				for _, err := range repo.Iterate(ctx) {
+					require.ErrorIs(t, err, tenant.ErrRequired)
+				}
+			},
+		}, {
+			name: "concurrent writes",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				const writers = 16
+
+				var wg sync.WaitGroup
+				ids := make([]uint, writers)
+				errs := make([]error, writers)
+				for i := range writers {
+					wg.Go(func() {
+						user, err := repo.Create(ctx, &dto.User{Name: fmt.Sprintf("User %d", i), Age: 10 + i})
+						if err != nil {
+							errs[i] = err
+							return
+						}
+						ids[i] = user.ID
+						errs[i] = repo.Update(ctx, &dto.User{Name: user.Name, Age: 50 + i}, user.ID)
+					})
+				}
+				wg.Wait()
+
+				for _, err := range errs {
+					require.NoError(t, err)
+				}
+				require.Len(t, uniqueIDs(ids), writers)
+
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Len(t, users, writers)
+				for _, user := range users {
+					require.GreaterOrEqual(t, user.Age, 50)
+				}
+			},
+		}, {
+			name: "canceled context",
+			run: func(t *testing.T, ctx context.Context, repo repository.UserRepo) {
+				created := mustCreate(t, ctx, repo, "John", 10)
+
+				canceled, cancel := context.WithCancel(ctx)
+				cancel()
+
+				_, err := repo.List(canceled)
+				require.ErrorIs(t, err, context.Canceled)
+
+				_, err = repo.Get(canceled, created.ID)
+				require.ErrorIs(t, err, context.Canceled)
+
+				_, err = repo.Create(canceled, &dto.User{Name: "Jane", Age: 11})
+				require.ErrorIs(t, err, context.Canceled)
+
+				_, err = repo.CreateBatch(canceled, []dto.User{{Name: "Jane", Age: 11}})
+				require.ErrorIs(t, err, context.Canceled)
+
+				require.ErrorIs(t, repo.Update(canceled, &dto.User{Name: "Johnny"}, created.ID), context.Canceled)
+				require.ErrorIs(t, repo.Delete(canceled, created.ID), context.Canceled)
+
+				_, err = repo.Exists(canceled, created.ID)
+				require.ErrorIs(t, err, context.Canceled)
+
+				var iterErr error
+				for _, err := range repo.Iterate(canceled) {
+					iterErr = err
+				}
+				require.ErrorIs(t, iterErr, context.Canceled)
+
+				got, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				requireUser(t, created, got)
+
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, tenant.WithID(t.Context(), testTenant), newRepo(t))
+		})
+	}
+}
+
+func mustCreate(t *testing.T, ctx context.Context, repo repository.UserRepo, name string, age int) *dto.User {
+	t.Helper()
+
+	user, err := repo.Create(ctx, &dto.User{Name: name, Age: age})
+	require.NoError(t, err)
+
+	return user
+}
+
+// requireUser compares the fields every store round-trips; timestamps are
+// compared to the second, the coarsest precision among the backends.
+func requireUser(t *testing.T, want, got *dto.User) {
+	t.Helper()
+
+	require.Equal(t, want.ID, got.ID)
+	require.Equal(t, want.TenantID, got.TenantID)
+	require.Equal(t, want.Name, got.Name)
+	require.Equal(t, want.Age, got.Age)
+	require.WithinDuration(t, want.CreatedAt, got.CreatedAt, time.Second)
+	require.WithinDuration(t, want.UpdatedAt, got.UpdatedAt, time.Second)
+	require.Equal(t, want.DeletedAt.Valid, got.DeletedAt.Valid)
+}
+
+func userIDs(users []dto.User) []uint {
+	ids := make([]uint, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+
+	return ids
+}
+
+func uniqueIDs(ids []uint) map[uint]struct{} {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+
+	return unique
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"crud_app/tenant"
+)
+
+// scopeTenant restricts a query to the tenant carried by ctx and fails the
+// query when there is none, so a missing filter can never leak rows.
+func scopeTenant(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenantID, err := tenant.ID(ctx)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+
+		return db.Where("tenant_id = ?", tenantID)
+	}
+}
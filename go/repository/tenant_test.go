@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"crud_app/tenant"
+)
+
+type tenantRow struct {
+	ID       uint `gorm:"primaryKey"`
+	TenantID string
+	Name     string
+}
+
+func TestScopeTenant(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tenants.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&tenantRow{}))
+
+	stored := []tenantRow{
+		{ID: 1, TenantID: "acme", Name: "John"},
+		{ID: 2, TenantID: "globex", Name: "Jane"},
+	}
+	require.NoError(t, db.Create(slices.Clone(stored)).Error)
+
+	acme := tenant.WithID(context.Background(), "acme")
+
+	type testCase struct {
+		name string
+		ctx  context.Context
+		// run returns the rows it read, if any.
+		run        func(db *gorm.DB) ([]tenantRow, error)
+		wantErr    error
+		wantRead   []tenantRow
+		wantStored []tenantRow
+	}
+
+	cases := []testCase{{
+		name: "reads only the rows of the tenant",
+		ctx:  acme,
+		run: func(db *gorm.DB) ([]tenantRow, error) {
+			var rows []tenantRow
+			err := db.Find(&rows).Error
+			return rows, err
+		},
+		wantRead:   stored[:1],
+		wantStored: stored,
+	}, {
+		name: "cannot read a row of another tenant",
+		ctx:  acme,
+		run: func(db *gorm.DB) ([]tenantRow, error) {
+			var row tenantRow
+			return nil, db.First(&row, 2).Error
+		},
+		wantErr:    gorm.ErrRecordNotFound,
+		wantStored: stored,
+	}, {
+		name: "cannot update a row of another tenant",
+		ctx:  acme,
+		run: func(db *gorm.DB) ([]tenantRow, error) {
+			return nil, db.Model(&tenantRow{}).Where("id IN ?", []uint{1, 2}).Update("name", "Changed").Error
+		},
+		wantStored: []tenantRow{
+			{ID: 1, TenantID: "acme", Name: "Changed"},
+			{ID: 2, TenantID: "globex", Name: "Jane"},
+		},
+	}, {
+		name: "cannot delete a row of another tenant",
+		ctx:  acme,
+		run: func(db *gorm.DB) ([]tenantRow, error) {
+			return nil, db.Delete(&tenantRow{}, 2).Error
+		},
+		wantStored: stored,
+	}, {
+		name: "missing tenant fails reads",
+		ctx:  context.Background(),
+		run: func(db *gorm.DB) ([]tenantRow, error) {
+			var rows []tenantRow
+			err := db.Find(&rows).Error
+			return rows, err
+		},
+		wantErr:    tenant.ErrRequired,
+		wantStored: stored,
+	}, {
+		name: "missing tenant fails writes",
+		ctx:  context.Background(),
+		run: func(db *gorm.DB) ([]tenantRow, error) {
+			return nil, db.Model(&tenantRow{}).Where("id = ?", 2).Update("name", "Changed").Error
+		},
+		wantErr:    tenant.ErrRequired,
+		wantStored: stored,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := db.Begin()
+			t.Cleanup(func() { tx.Rollback() })
+
+			read, err := tc.run(tx.WithContext(tc.ctx).Scopes(scopeTenant(tc.ctx)))
+			require.ErrorIs(t, err, tc.wantErr)
+			if tc.wantRead != nil {
+				require.Equal(t, tc.wantRead, read)
+			}
+
+			var rows []tenantRow
+			require.NoError(t, tx.Order("id").Find(&rows).Error)
+			require.Equal(t, tc.wantStored, rows)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -13,70 +14,110 @@ import (
 const tableName string = "users"
 
 type UserRepo interface {
-	List(ctx context.Context) ([]dto.User, error)
-	Create(ctx context.Context, user *dto.User) (*dto.User, error)
-	Update(ctx context.Context, user *dto.User, id uint) error
-	Delete(ctx context.Context, id uint) error
-	Exists(ctx context.Context, id uint) (bool, error)
+	Repo[dto.User]
+	GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error)
+	ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error)
+	// LastModified is when a user of the tenant was last written, deletes,
+	// restores and purges included, or zero before the first write.
+	LastModified(ctx context.Context) (time.Time, error)
+	// Purge removes a user for good, deleted or not, together with its
+	// versions. A tombstone without the user's data is left in their place,
+	// which as-of reads skip but LastModified sees. Purging an unknown user
+	// is a no-op.
+	Purge(ctx context.Context, id uint) error
 }
 
+// userRepo is the generic Repo with a version recorded for every write.
 type userRepo struct {
+	Repo[dto.User]
 	db *gorm.DB
 }
 
 func NewUserRepo(db *gorm.DB) UserRepo {
-	return &userRepo{db: db}
+	return &userRepo{Repo: NewRepo[dto.User](db), db: db}
 }
 
-func (r *userRepo) List(ctx context.Context) ([]dto.User, error) {
-	var users []dto.User
+func (r *userRepo) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		if _, err := r.Repo.Create(ctx, user); err != nil {
+			return err
+		}
+
+		return r.recordVersion(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return users, r.db.WithContext(ctx).
-		Table(tableName).
-		Where("deleted_at is null").
-		Order("id").
-		Find(&users).
-		Error
+	return user, nil
 }
 
-func (r *userRepo) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
-	err := r.db.WithContext(ctx).
-		Table(tableName).
-		Create(user).
-		Error
+// CreateBatch inserts users with a single statement and records their first
+// versions the same way.
+func (r *userRepo) CreateBatch(ctx context.Context, users []dto.User) ([]dto.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
 
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		if _, err := r.Repo.CreateBatch(ctx, users); err != nil {
+			return err
+		}
+
+		return r.recordFirstVersions(ctx, users)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return users, nil
 }
 
 func (r *userRepo) Update(ctx context.Context, user *dto.User, id uint) error {
-	return r.db.WithContext(ctx).
-		Table(tableName).
-		Where("id = ?", id).
-		Updates(user).
-		Error
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		if err := r.Repo.Update(ctx, user, id); err != nil {
+			return err
+		}
+
+		return r.recordVersion(ctx, id)
+	})
 }
 
 func (r *userRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).
-		Table(tableName).
-		Delete(&dto.User{}, id).
-		Error
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		if err := r.Repo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return r.recordVersion(ctx, id)
+	})
 }
 
-func (r *userRepo) Exists(ctx context.Context, id uint) (bool, error) {
-	var count int64
-	err := r.db.Table(tableName).
-		Where("id = ?", id).
-		Where("deleted_at is null").
-		Count(&count).Error
+func (r *userRepo) Restore(ctx context.Context, id uint) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		if err := r.Repo.Restore(ctx, id); err != nil {
+			return err
+		}
 
-	if err != nil {
-		return false, err
-	}
+		return r.recordVersion(ctx, id)
+	})
+}
 
-	return count > 0, nil
+func (r *userRepo) Purge(ctx context.Context, id uint) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		result := conn(ctx, r.db).
+			Unscoped().
+			Scopes(scopeTenant(ctx)).
+			Where("id = ?", id).
+			Delete(&dto.User{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return r.recordPurge(ctx, id)
+	})
 }
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+const userAuditTableName string = "user_audit"
+
+type UserAuditRepo interface {
+	Append(ctx context.Context, entry *dto.UserAudit) error
+	ListByUser(ctx context.Context, userID uint, page dto.PageRequest) ([]dto.UserAudit, int64, error)
+}
+
+type userAuditRepo struct {
+	db *gorm.DB
+}
+
+func NewUserAuditRepo(db *gorm.DB) UserAuditRepo {
+	return &userAuditRepo{db: db}
+}
+
+func (r *userAuditRepo) Append(ctx context.Context, entry *dto.UserAudit) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	entry.TenantID = tenantID
+
+	return conn(ctx, r.db).
+		Table(userAuditTableName).
+		Create(entry).
+		Error
+}
+
+func (r *userAuditRepo) ListByUser(ctx context.Context, userID uint, page dto.PageRequest) ([]dto.UserAudit, int64, error) {
+	query := conn(ctx, r.db).
+		Table(userAuditTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []dto.UserAudit
+	err := query.
+		Order("id desc").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&entries).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"crud_app/cache"
+	"crud_app/dto"
+	"crud_app/metrics"
+	"crud_app/tenant"
+)
+
+const userCacheName string = "users"
+
+// userRepoWithCache serves List, Find, Get and LastModified from backend and
+// passes every other read through. Keys carry the tenant and its
+// generation: a committed write moves the tenant to a new generation, so
+// everything cached before it becomes unreachable and ages out, including
+// loads that were still running.
+type userRepoWithCache struct {
+	UserRepo
+	backend cache.Backend
+	ttl     time.Duration
+	loads   singleflight.Group
+}
+
+// cachedUsers is what List and Find store.
+type cachedUsers struct {
+	Users []dto.User
+	Total int64
+}
+
+func NewUserRepoWithCache(next UserRepo, backend cache.Backend, ttl time.Duration) UserRepo {
+	return &userRepoWithCache{UserRepo: next, backend: backend, ttl: ttl}
+}
+
+func (r *userRepoWithCache) List(ctx context.Context) ([]dto.User, error) {
+	cached, err := cachedLoad(ctx, r, "list", "list", func(ctx context.Context) (cachedUsers, error) {
+		users, err := r.UserRepo.List(ctx)
+		return cachedUsers{Users: users}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nonNil(cached.Users), nil
+}
+
+func (r *userRepoWithCache) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	// json.Marshal sorts the filters, so equal queries get equal keys.
+	data, err := json.Marshal(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	sum := sha256.Sum256(data)
+
+	cached, err := cachedLoad(ctx, r, "find", "find:"+hex.EncodeToString(sum[:]), func(ctx context.Context) (cachedUsers, error) {
+		users, total, err := r.UserRepo.Find(ctx, query)
+		return cachedUsers{Users: users, Total: total}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return nonNil(cached.Users), cached.Total, nil
+}
+
+func (r *userRepoWithCache) Get(ctx context.Context, id uint) (*dto.User, error) {
+	return cachedLoad(ctx, r, "get", "get:"+strconv.FormatUint(uint64(id), 10), func(ctx context.Context) (*dto.User, error) {
+		return r.UserRepo.Get(ctx, id)
+	})
+}
+
+func (r *userRepoWithCache) LastModified(ctx context.Context) (time.Time, error) {
+	return cachedLoad(ctx, r, "last_modified", "last_modified", r.UserRepo.LastModified)
+}
+
+func (r *userRepoWithCache) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	defer r.invalidate(ctx)
+
+	return r.UserRepo.Create(ctx, user)
+}
+
+func (r *userRepoWithCache) CreateBatch(ctx context.Context, users []dto.User) ([]dto.User, error) {
+	defer r.invalidate(ctx)
+
+	return r.UserRepo.CreateBatch(ctx, users)
+}
+
+func (r *userRepoWithCache) Update(ctx context.Context, user *dto.User, id uint) error {
+	defer r.invalidate(ctx)
+
+	return r.UserRepo.Update(ctx, user, id)
+}
+
+func (r *userRepoWithCache) Delete(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx)
+
+	return r.UserRepo.Delete(ctx, id)
+}
+
+func (r *userRepoWithCache) Restore(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx)
+
+	return r.UserRepo.Restore(ctx, id)
+}
+
+func (r *userRepoWithCache) Purge(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx)
+
+	return r.UserRepo.Purge(ctx, id)
+}
+
+// cachedLoad returns the value cached under key or loads and caches it.
+// Concurrent misses on a key share one load, and each caller decodes its own
+// copy of the result. Reads inside a transaction bypass the cache, since
+// they may see writes that are not committed yet, and a failing backend
+// degrades to loading every time.
+func cachedLoad[V any](ctx context.Context, r *userRepoWithCache, operation, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if inTx(ctx) {
+		return load(ctx)
+	}
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return load(ctx)
+	}
+
+	generation, err := r.generation(ctx, tenantID)
+	if err != nil {
+		r.backendFailed(ctx, operation, err)
+		return load(ctx)
+	}
+	key = userCacheKey(tenantID, generation+":"+key)
+
+	data, ok, err := r.backend.Get(ctx, key)
+	if err != nil {
+		r.backendFailed(ctx, operation, err)
+		return load(ctx)
+	}
+	if ok {
+		if v, err := decodeCached[V](data); err == nil {
+			observeCache(operation, "hit")
+			return v, nil
+		}
+	}
+	observeCache(operation, "miss")
+
+	results := r.loads.DoChan(key, func() (any, error) {
+		// The load outlives a caller that gives up, since others may wait
+		// for it.
+		ctx := context.WithoutCancel(ctx)
+
+		v, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var b bytes.Buffer
+		if err := gob.NewEncoder(&b).Encode(v); err != nil {
+			return nil, err
+		}
+		if err := r.backend.Set(ctx, key, b.Bytes(), r.ttl); err != nil {
+			slog.WarnContext(ctx, "user cache write failed", slog.Any("error", err))
+		}
+
+		return b.Bytes(), nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-results:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return decodeCached[V](res.Val.([]byte))
+	}
+}
+
+// invalidate moves the tenant to a new generation once the write carried by
+// ctx commits. It runs whether or not the write succeeded.
+func (r *userRepoWithCache) invalidate(ctx context.Context) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return
+	}
+
+	AfterCommit(ctx, func() {
+		if _, err := r.newGeneration(context.WithoutCancel(ctx), tenantID); err != nil {
+			slog.WarnContext(ctx, "user cache invalidation failed", slog.String("tenant_id", tenantID), slog.Any("error", err))
+		}
+	})
+}
+
+func (r *userRepoWithCache) generation(ctx context.Context, tenantID string) (string, error) {
+	data, ok, err := r.backend.Get(ctx, userCacheKey(tenantID, "generation"))
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return string(data), nil
+	}
+
+	return r.newGeneration(ctx, tenantID)
+}
+
+// newGeneration is random rather than a counter, so a generation that was
+// evicted is never reused and cannot bring back the entries cached under it.
+func (r *userRepoWithCache) newGeneration(ctx context.Context, tenantID string) (string, error) {
+	generation := strconv.FormatUint(rand.Uint64(), 36)
+
+	return generation, r.backend.Set(ctx, userCacheKey(tenantID, "generation"), []byte(generation), 0)
+}
+
+func (r *userRepoWithCache) backendFailed(ctx context.Context, operation string, err error) {
+	observeCache(operation, "error")
+	slog.WarnContext(ctx, "user cache read failed", slog.String("operation", operation), slog.Any("error", err))
+}
+
+func userCacheKey(tenantID, key string) string {
+	return userCacheName + ":" + tenantID + ":" + key
+}
+
+func decodeCached[V any](data []byte) (V, error) {
+	var v V
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+
+	return v, err
+}
+
+// inTx reports whether ctx carries a transaction or a snapshot.
+func inTx(ctx context.Context) bool {
+	return ctx.Value(afterCommitKey{}) != nil || ctx.Value(txKey{}) != nil
+}
+
+// nonNil keeps List and Find returning an empty slice, which gob decodes as
+// nil.
+func nonNil(users []dto.User) []dto.User {
+	if users == nil {
+		return []dto.User{}
+	}
+
+	return users
+}
+
+func observeCache(operation, result string) {
+	metrics.CacheRequestsTotal.WithLabelValues(userCacheName, operation, result).Inc()
+}
@@ -0,0 +1,391 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"crud_app/cache"
+	"crud_app/config"
+	"crud_app/dto"
+	"crud_app/metrics"
+	"crud_app/repository"
+	"crud_app/repository/repositorytest"
+	"crud_app/tenant"
+)
+
+func TestUserRepoWithCache_Conformance(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		repositorytest.RunUserRepo(t, func(t *testing.T) repository.UserRepo {
+			return repository.NewUserRepoWithCache(repository.NewMemoryUserRepo(), cache.NewLRU(100), time.Minute)
+		})
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		repositorytest.RunUserRepo(t, func(t *testing.T) repository.UserRepo {
+			t.Setenv("DB_DRIVER", config.DriverSQLite)
+			t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "crud_app.db"))
+
+			db, err := config.ConnectDB()
+			require.NoError(t, err)
+
+			sqlDB, err := db.DB()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = sqlDB.Close() })
+
+			return repository.NewUserRepoWithCache(repository.NewUserRepo(db), cache.NewLRU(100), time.Minute)
+		})
+	})
+}
+
+// countingUserRepo counts the reads that reach the repository behind the
+// cache. A non-nil gate holds List until it is closed.
+type countingUserRepo struct {
+	repository.UserRepo
+	lists atomic.Int32
+	finds atomic.Int32
+	gets  atomic.Int32
+	gate  chan struct{}
+}
+
+func (r *countingUserRepo) List(ctx context.Context) ([]dto.User, error) {
+	r.lists.Add(1)
+	if r.gate != nil {
+		<-r.gate
+	}
+
+	return r.UserRepo.List(ctx)
+}
+
+func (r *countingUserRepo) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	r.finds.Add(1)
+
+	return r.UserRepo.Find(ctx, query)
+}
+
+func (r *countingUserRepo) Get(ctx context.Context, id uint) (*dto.User, error) {
+	r.gets.Add(1)
+
+	return r.UserRepo.Get(ctx, id)
+}
+
+// failingBackend fails every call.
+type failingBackend struct{}
+
+var errBackend = errors.New("backend is down")
+
+func (failingBackend) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errBackend
+}
+
+func (failingBackend) Set(context.Context, string, []byte, time.Duration) error {
+	return errBackend
+}
+
+func (failingBackend) Delete(context.Context, ...string) error {
+	return errBackend
+}
+
+func TestUserRepoWithCache(t *testing.T) {
+	type testCase struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo)
+	}
+
+	cases := []testCase{{
+		name: "repeated reads hit the cache",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			created, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+			require.NoError(t, err)
+
+			query := dto.ListQuery{Filters: map[string]string{"name": "John"}}
+			for range 3 {
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+
+				users, total, err := repo.Find(ctx, query)
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				require.EqualValues(t, 1, total)
+
+				user, err := repo.Get(ctx, created.ID)
+				require.NoError(t, err)
+				require.Equal(t, "John", user.Name)
+				require.Equal(t, created.TenantID, user.TenantID)
+			}
+
+			require.EqualValues(t, 1, next.lists.Load())
+			require.EqualValues(t, 1, next.finds.Load())
+			require.EqualValues(t, 1, next.gets.Load())
+		},
+	}, {
+		name: "different queries are cached apart",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			_, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+			require.NoError(t, err)
+			_, err = repo.Create(ctx, &dto.User{Name: "Jane", Age: 20})
+			require.NoError(t, err)
+
+			users, _, err := repo.Find(ctx, dto.ListQuery{Filters: map[string]string{"name": "John"}})
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			require.Equal(t, "John", users[0].Name)
+
+			users, _, err = repo.Find(ctx, dto.ListQuery{Filters: map[string]string{"name": "Jane"}})
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			require.Equal(t, "Jane", users[0].Name)
+
+			users, total, err := repo.Find(ctx, dto.ListQuery{PageRequest: dto.PageRequest{Limit: 1}})
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			require.EqualValues(t, 2, total)
+
+			require.EqualValues(t, 3, next.finds.Load())
+		},
+	}, {
+		name: "writes invalidate",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			created, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+			require.NoError(t, err)
+			_, err = repo.Get(ctx, created.ID)
+			require.NoError(t, err)
+			_, err = repo.List(ctx)
+			require.NoError(t, err)
+
+			require.NoError(t, repo.Update(ctx, &dto.User{Name: "Johnny", Age: 11}, created.ID))
+			user, err := repo.Get(ctx, created.ID)
+			require.NoError(t, err)
+			require.Equal(t, "Johnny", user.Name)
+
+			_, err = repo.CreateBatch(ctx, []dto.User{{Name: "Jane", Age: 20}})
+			require.NoError(t, err)
+			users, err := repo.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, users, 2)
+
+			require.NoError(t, repo.Delete(ctx, created.ID))
+			_, err = repo.Get(ctx, created.ID)
+			require.ErrorIs(t, err, repository.ErrNotFound)
+			users, err = repo.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+
+			require.NoError(t, repo.Restore(ctx, created.ID))
+			users, err = repo.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, users, 2)
+		},
+	}, {
+		name: "writes in a transaction invalidate on commit",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			transactor := repository.NewMemoryTransactor()
+			_, err := repo.List(ctx)
+			require.NoError(t, err)
+
+			err = transactor.WithinTx(ctx, func(txCtx context.Context) error {
+				if _, err := repo.Create(txCtx, &dto.User{Name: "John", Age: 10}); err != nil {
+					return err
+				}
+
+				// Reads in the transaction bypass the cache, and reads
+				// outside it keep the committed state.
+				users, err := repo.List(txCtx)
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				require.EqualValues(t, 2, next.lists.Load())
+
+				users, err = repo.List(ctx)
+				require.NoError(t, err)
+				require.Empty(t, users)
+				require.EqualValues(t, 2, next.lists.Load())
+
+				return nil
+			})
+			require.NoError(t, err)
+
+			users, err := repo.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			require.EqualValues(t, 3, next.lists.Load())
+		},
+	}, {
+		name: "tenants are cached apart",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			_, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+			require.NoError(t, err)
+			users, err := repo.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+
+			otherCtx := tenant.WithID(context.Background(), "other")
+			users, err = repo.List(otherCtx)
+			require.NoError(t, err)
+			require.Empty(t, users)
+
+			// A write in one tenant keeps the other's entries.
+			_, err = repo.Create(otherCtx, &dto.User{Name: "Jane", Age: 20})
+			require.NoError(t, err)
+			_, err = repo.List(ctx)
+			require.NoError(t, err)
+
+			require.EqualValues(t, 2, next.lists.Load())
+		},
+	}, {
+		name: "callers get their own copies",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			created, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+			require.NoError(t, err)
+
+			users, err := repo.List(ctx)
+			require.NoError(t, err)
+			users[0].Name = "Changed"
+
+			user, err := repo.Get(ctx, created.ID)
+			require.NoError(t, err)
+			user.Name = "Changed"
+
+			users, err = repo.List(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "John", users[0].Name)
+
+			user, err = repo.Get(ctx, created.ID)
+			require.NoError(t, err)
+			require.Equal(t, "John", user.Name)
+		},
+	}, {
+		name: "errors are not cached",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			for range 2 {
+				_, err := repo.Get(ctx, 1_000_000)
+				require.ErrorIs(t, err, repository.ErrNotFound)
+			}
+			require.EqualValues(t, 2, next.gets.Load())
+
+			_, _, err := repo.Find(ctx, dto.ListQuery{Filters: map[string]string{"unknown": "x"}})
+			require.ErrorIs(t, err, repository.ErrInvalidFilter)
+		},
+	}, {
+		name: "empty lists stay empty slices",
+		run: func(t *testing.T, ctx context.Context, next *countingUserRepo, repo repository.UserRepo) {
+			for range 2 {
+				users, err := repo.List(ctx)
+				require.NoError(t, err)
+				require.NotNil(t, users)
+				require.Empty(t, users)
+			}
+			require.EqualValues(t, 1, next.lists.Load())
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := tenant.WithID(context.Background(), "default")
+			next := &countingUserRepo{UserRepo: repository.NewMemoryUserRepo()}
+			repo := repository.NewUserRepoWithCache(next, cache.NewLRU(100), time.Minute)
+
+			tc.run(t, ctx, next, repo)
+		})
+	}
+}
+
+func TestUserRepoWithCache_Metrics(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "default")
+	repo := repository.NewUserRepoWithCache(repository.NewMemoryUserRepo(), cache.NewLRU(100), time.Minute)
+	hits := metrics.CacheRequestsTotal.WithLabelValues("users", "list", "hit")
+	misses := metrics.CacheRequestsTotal.WithLabelValues("users", "list", "miss")
+	hitsBefore, missesBefore := testutil.ToFloat64(hits), testutil.ToFloat64(misses)
+
+	for range 3 {
+		_, err := repo.List(ctx)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, hitsBefore+2, testutil.ToFloat64(hits))
+	require.Equal(t, missesBefore+1, testutil.ToFloat64(misses))
+}
+
+func TestUserRepoWithCache_BackendFailure(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "default")
+	next := &countingUserRepo{UserRepo: repository.NewMemoryUserRepo()}
+	repo := repository.NewUserRepoWithCache(next, failingBackend{}, time.Minute)
+	failures := metrics.CacheRequestsTotal.WithLabelValues("users", "list", "error")
+	before := testutil.ToFloat64(failures)
+
+	_, err := repo.Create(ctx, &dto.User{Name: "John", Age: 10})
+	require.NoError(t, err)
+
+	for range 2 {
+		users, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+	}
+
+	require.EqualValues(t, 2, next.lists.Load())
+	require.Equal(t, before+2, testutil.ToFloat64(failures))
+}
+
+func TestUserRepoWithCache_SharesConcurrentMisses(t *testing.T) {
+	const callers = 8
+
+	ctx := tenant.WithID(context.Background(), "default")
+	next := &countingUserRepo{UserRepo: repository.NewMemoryUserRepo(), gate: make(chan struct{})}
+	repo := repository.NewUserRepoWithCache(next, cache.NewLRU(100), time.Minute)
+	misses := metrics.CacheRequestsTotal.WithLabelValues("users", "list", "miss")
+	before := testutil.ToFloat64(misses)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Go(func() {
+			_, err := repo.List(ctx)
+			errs <- err
+		})
+	}
+
+	// Hold the load until every caller has missed and joined it.
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(misses) == before+callers
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(next.gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, next.lists.Load())
+}
+
+func TestUserRepoWithCache_CallerCancels(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "default")
+	next := &countingUserRepo{UserRepo: repository.NewMemoryUserRepo(), gate: make(chan struct{})}
+	repo := repository.NewUserRepoWithCache(next, cache.NewLRU(100), time.Minute)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := repo.List(cancelCtx)
+		errs <- err
+	}()
+	require.Eventually(t, func() bool { return next.lists.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+
+	// The load goes on for the callers that wait, and its result is cached.
+	close(next.gate)
+	require.Eventually(t, func() bool {
+		_, err := repo.List(ctx)
+		return err == nil
+	}, time.Second, time.Millisecond)
+	require.EqualValues(t, 1, next.lists.Load())
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+const historyTableName string = "users_history"
+
+// recordVersion closes the current version of a user and appends its new
+// state, so every write leaves a [valid_from, valid_to) interval behind.
+func (r *userRepo) recordVersion(ctx context.Context, id uint) error {
+	var current dto.User
+	err := conn(ctx, r.db).
+		Unscoped().
+		Table(tableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Take(&current).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	err = conn(ctx, r.db).
+		Table(historyTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("user_id = ?", id).
+		Where("valid_to is null").
+		Update("valid_to", now).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	version := dto.UserVersion{
+		UserID:    current.ID,
+		TenantID:  current.TenantID,
+		Name:      current.Name,
+		Age:       current.Age,
+		CreatedAt: current.CreatedAt,
+		UpdatedAt: current.UpdatedAt,
+		ValidFrom: now,
+	}
+	if current.DeletedAt.Valid {
+		version.DeletedAt = &current.DeletedAt.Time
+	}
+
+	return conn(ctx, r.db).
+		Table(historyTableName).
+		Create(&version).
+		Error
+}
+
+// recordPurge replaces the versions of a purged user with a tombstone. It is
+// valid for an empty interval, so no as-of read finds it, and it keeps
+// LastModified from going back to the write before the purge.
+func (r *userRepo) recordPurge(ctx context.Context, id uint) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn(ctx, r.db).
+		Table(historyTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("user_id = ?", id).
+		Delete(&dto.UserVersion{}).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	tombstone := dto.UserVersion{
+		UserID:    id,
+		TenantID:  tenantID,
+		DeletedAt: &now,
+		ValidFrom: now,
+		ValidTo:   &now,
+	}
+
+	return conn(ctx, r.db).
+		Table(historyTableName).
+		Create(&tombstone).
+		Error
+}
+
+// recordFirstVersions is recordVersion for users that were just inserted and
+// so have no version to close yet.
+func (r *userRepo) recordFirstVersions(ctx context.Context, users []dto.User) error {
+	now := time.Now().UTC()
+
+	versions := make([]dto.UserVersion, len(users))
+	for i, user := range users {
+		versions[i] = dto.UserVersion{
+			UserID:    user.ID,
+			TenantID:  user.TenantID,
+			Name:      user.Name,
+			Age:       user.Age,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+			ValidFrom: now,
+		}
+	}
+
+	return conn(ctx, r.db).
+		Table(historyTableName).
+		Create(&versions).
+		Error
+}
+
+func (r *userRepo) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	var version dto.UserVersion
+	err := conn(ctx, r.db).
+		Table(historyTableName).
+		Scopes(scopeTenant(ctx), scopeValidAt(at)).
+		Where("user_id = ?", id).
+		Where("deleted_at is null").
+		Take(&version).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return version.User(), nil
+}
+
+func (r *userRepo) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	var versions []dto.UserVersion
+	err := conn(ctx, r.db).
+		Table(historyTableName).
+		Scopes(scopeTenant(ctx), scopeValidAt(at)).
+		Where("deleted_at is null").
+		Order("user_id").
+		Find(&versions).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]dto.User, 0, len(versions))
+	for _, version := range versions {
+		users = append(users, *version.User())
+	}
+
+	return users, nil
+}
+
+// LastModified is the start of the newest version, since every write
+// records one.
+func (r *userRepo) LastModified(ctx context.Context) (time.Time, error) {
+	var version dto.UserVersion
+	err := conn(ctx, r.db).
+		Table(historyTableName).
+		Scopes(scopeTenant(ctx)).
+		Select("valid_from").
+		Order("valid_from desc").
+		Take(&version).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return version.ValidFrom, nil
+}
+
+func scopeValidAt(at time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		at = at.UTC()
+
+		return db.
+			Where("valid_from <= ?", at).
+			Where("valid_to is null or valid_to > ?", at)
+	}
+}
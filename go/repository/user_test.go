@@ -0,0 +1,30 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/config"
+	"crud_app/repository"
+	"crud_app/repository/repositorytest"
+)
+
+// TestUserRepo runs the suite on a SQLite file per case, opened the way the
+// app opens it, so the migrations and pragmas under test are the real ones.
+func TestUserRepo(t *testing.T) {
+	repositorytest.RunUserRepo(t, func(t *testing.T) repository.UserRepo {
+		t.Setenv("DB_DRIVER", config.DriverSQLite)
+		t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "crud_app.db"))
+
+		db, err := config.ConnectDB()
+		require.NoError(t, err)
+
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = sqlDB.Close() })
+
+		return repository.NewUserRepo(db)
+	})
+}
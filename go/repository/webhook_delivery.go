@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crud_app/dto"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+const webhookDeliveriesTableName string = "webhook_deliveries"
+
+type WebhookDeliveryRepo interface {
+	Enqueue(ctx context.Context, deliveries []dto.WebhookDelivery) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]dto.WebhookDelivery, error)
+	MarkSucceeded(ctx context.Context, id uint64, statusCode int, at time.Time) error
+	MarkRetry(ctx context.Context, id uint64, statusCode int, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id uint64, statusCode int, lastError string) error
+	ListBySubscription(ctx context.Context, subscriptionID uint, page dto.PageRequest) ([]dto.WebhookDelivery, int64, error)
+}
+
+type webhookDeliveryRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepo(db *gorm.DB) WebhookDeliveryRepo {
+	return &webhookDeliveryRepo{db: db}
+}
+
+// Enqueue ignores deliveries that already exist for the same subscription and
+// event, which keeps redelivered outbox events from fanning out twice.
+func (r *webhookDeliveryRepo) Enqueue(ctx context.Context, deliveries []dto.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return conn(ctx, r.db).
+		Table(webhookDeliveriesTableName).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).
+		Error
+}
+
+// ClaimDue leases due deliveries to the caller by pushing their next attempt
+// past the lease, so the HTTP calls can happen outside of a transaction.
+func (r *webhookDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]dto.WebhookDelivery, error) {
+	var deliveries []dto.WebhookDelivery
+
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		err := conn(ctx, r.db).
+			Table(webhookDeliveriesTableName).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", dto.WebhookDeliveryPending).
+			Where("next_attempt_at <= ?", now.UTC()).
+			Order("id").
+			Limit(limit).
+			Find(&deliveries).
+			Error
+
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+
+		return conn(ctx, r.db).
+			Table(webhookDeliveriesTableName).
+			Where("id in ?", ids).
+			Update("next_attempt_at", now.Add(lease).UTC()).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepo) MarkSucceeded(ctx context.Context, id uint64, statusCode int, at time.Time) error {
+	return r.update(ctx, id, map[string]any{
+		"status":           dto.WebhookDeliverySucceeded,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       "",
+		"delivered_at":     at.UTC(),
+	})
+}
+
+func (r *webhookDeliveryRepo) MarkRetry(ctx context.Context, id uint64, statusCode int, lastError string, nextAttemptAt time.Time) error {
+	return r.update(ctx, id, map[string]any{
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+		"next_attempt_at":  nextAttemptAt.UTC(),
+	})
+}
+
+func (r *webhookDeliveryRepo) MarkFailed(ctx context.Context, id uint64, statusCode int, lastError string) error {
+	return r.update(ctx, id, map[string]any{
+		"status":           dto.WebhookDeliveryFailed,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+	})
+}
+
+func (r *webhookDeliveryRepo) ListBySubscription(ctx context.Context, subscriptionID uint, page dto.PageRequest) ([]dto.WebhookDelivery, int64, error) {
+	query := conn(ctx, r.db).
+		Table(webhookDeliveriesTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("subscription_id = ?", subscriptionID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []dto.WebhookDelivery
+	err := query.
+		Order("id desc").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&deliveries).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *webhookDeliveryRepo) update(ctx context.Context, id uint64, fields map[string]any) error {
+	return conn(ctx, r.db).
+		Table(webhookDeliveriesTableName).
+		Where("id = ?", id).
+		Updates(fields).
+		Error
+}
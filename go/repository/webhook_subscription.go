@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crud_app/dto"
+	"crud_app/tenant"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+const webhookSubscriptionsTableName string = "webhook_subscriptions"
+
+type WebhookSubscriptionRepo interface {
+	List(ctx context.Context) ([]dto.WebhookSubscription, error)
+	Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error)
+	Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error
+	Delete(ctx context.Context, id uint) error
+
+	// The methods below serve background workers and ignore the tenant
+	// carried by ctx.
+	ListActive(ctx context.Context, tenantID string) ([]dto.WebhookSubscription, error)
+	GetForDelivery(ctx context.Context, id uint) (*dto.WebhookSubscription, error)
+	RecordResult(ctx context.Context, id uint, success bool, maxFailures int) error
+}
+
+type webhookSubscriptionRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookSubscriptionRepo(db *gorm.DB) WebhookSubscriptionRepo {
+	return &webhookSubscriptionRepo{db: db}
+}
+
+func (r *webhookSubscriptionRepo) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	var subscriptions []dto.WebhookSubscription
+
+	return subscriptions, conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Scopes(scopeTenant(ctx)).
+		Order("id").
+		Find(&subscriptions).
+		Error
+}
+
+func (r *webhookSubscriptionRepo) Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	var subscription dto.WebhookSubscription
+	err := conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Take(&subscription).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (r *webhookSubscriptionRepo) Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subscription.TenantID = tenantID
+
+	err = conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Create(subscription).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookSubscriptionRepo) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]any{
+		"url":         subscription.URL,
+		"event_types": string(eventTypes),
+		"updated_at":  time.Now(),
+	}
+	if subscription.Secret != "" {
+		fields["secret"] = subscription.Secret
+	}
+	if subscription.Active != nil {
+		fields["active"] = *subscription.Active
+		if *subscription.Active {
+			fields["consecutive_failures"] = 0
+			fields["disabled_at"] = nil
+		}
+	}
+
+	return conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Scopes(scopeTenant(ctx)).
+		Where("id = ?", id).
+		Updates(fields).
+		Error
+}
+
+func (r *webhookSubscriptionRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Scopes(scopeTenant(ctx)).
+		Delete(&dto.WebhookSubscription{}, id).
+		Error
+}
+
+func (r *webhookSubscriptionRepo) ListActive(ctx context.Context, tenantID string) ([]dto.WebhookSubscription, error) {
+	var subscriptions []dto.WebhookSubscription
+
+	return subscriptions, conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Where("tenant_id = ?", tenantID).
+		Where("active = ?", true).
+		Order("id").
+		Find(&subscriptions).
+		Error
+}
+
+func (r *webhookSubscriptionRepo) GetForDelivery(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	var subscription dto.WebhookSubscription
+	err := conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Where("id = ?", id).
+		Take(&subscription).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// RecordResult resets the failure streak on success and otherwise extends
+// it, disabling the subscription once it reaches maxFailures.
+func (r *webhookSubscriptionRepo) RecordResult(ctx context.Context, id uint, success bool, maxFailures int) error {
+	query := conn(ctx, r.db).
+		Table(webhookSubscriptionsTableName).
+		Where("id = ?", id)
+
+	if success {
+		return query.Update("consecutive_failures", 0).Error
+	}
+
+	return query.Updates(map[string]any{
+		"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+		"active":               gorm.Expr("consecutive_failures + 1 < ?", maxFailures),
+		"disabled_at":          gorm.Expr("CASE WHEN consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END", maxFailures, time.Now().UTC()),
+	}).Error
+}
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/config"
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+func TestWebhookSubscriptionRepo_Update(t *testing.T) {
+	factories := map[string]func(t *testing.T) repository.WebhookSubscriptionRepo{
+		"memory": func(t *testing.T) repository.WebhookSubscriptionRepo {
+			return repository.NewMemoryWebhookSubscriptionRepo()
+		},
+		"sqlite": func(t *testing.T) repository.WebhookSubscriptionRepo {
+			t.Setenv("DB_DRIVER", config.DriverSQLite)
+			t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "crud_app.db"))
+
+			db, err := config.ConnectDB()
+			require.NoError(t, err)
+			sqlDB, err := db.DB()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = sqlDB.Close() })
+
+			return repository.NewWebhookSubscriptionRepo(db)
+		},
+	}
+
+	active, inactive := true, false
+
+	type testCase struct {
+		name string
+		// failures are recorded before the update; two disable it.
+		failures     int
+		active       *bool
+		wantActive   bool
+		wantFailures int
+	}
+
+	cases := []testCase{
+		{name: "without active keeps an active subscription active", failures: 1, active: nil, wantActive: true, wantFailures: 1},
+		{name: "without active keeps a disabled subscription disabled", failures: 2, active: nil, wantActive: false, wantFailures: 2},
+		{name: "active re-enables it and resets its failures", failures: 2, active: &active, wantActive: true, wantFailures: 0},
+		{name: "inactive deactivates it", failures: 1, active: &inactive, wantActive: false, wantFailures: 1},
+	}
+
+	for name, newRepo := range factories {
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					repo := newRepo(t)
+					ctx := tenant.WithID(context.Background(), "acme")
+
+					created, err := repo.Create(ctx, &dto.WebhookSubscription{
+						URL:        "https://example.com/hook",
+						EventTypes: []string{events.UserCreated},
+						Secret:     "0123456789abcdef",
+						Active:     &active,
+					})
+					require.NoError(t, err)
+					for range tc.failures {
+						require.NoError(t, repo.RecordResult(ctx, created.ID, false, 2))
+					}
+
+					require.NoError(t, repo.Update(ctx, &dto.WebhookSubscription{
+						URL:        "https://example.com/other",
+						EventTypes: []string{events.UserDeleted},
+						Active:     tc.active,
+					}, created.ID))
+
+					got, err := repo.Get(ctx, created.ID)
+					require.NoError(t, err)
+					require.Equal(t, "https://example.com/other", got.URL)
+					require.Equal(t, "0123456789abcdef", got.Secret)
+					require.NotNil(t, got.Active)
+					require.Equal(t, tc.wantActive, *got.Active)
+					require.Equal(t, tc.wantFailures, got.ConsecutiveFailures)
+					if tc.wantActive {
+						require.Nil(t, got.DisabledAt)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"crud_app/errkind"
+	"crud_app/service"
+)
+
+const errorDomain string = "crud_app"
+
+// statusFromError reports err with the gRPC code of its errkind. Validation
+// failures carry their rule as the ErrorInfo reason.
+func statusFromError(ctx context.Context, err error) error {
+	var code codes.Code
+
+	switch errkind.Of(err) {
+	case errkind.Unauthenticated:
+		code = codes.Unauthenticated
+	case errkind.Forbidden:
+		code = codes.PermissionDenied
+	case errkind.Invalid:
+		code = codes.InvalidArgument
+	case errkind.NotFound:
+		code = codes.NotFound
+	case errkind.Unavailable:
+		code = codes.Unavailable
+	case errkind.Canceled:
+		code = codes.Canceled
+	case errkind.DeadlineExceeded:
+		code = codes.DeadlineExceeded
+	default:
+		slog.ErrorContext(ctx, "grpc request failed", slog.Any("error", err))
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	var validationErr *service.ValidationError
+	if !errors.As(err, &validationErr) {
+		return status.Error(code, err.Error())
+	}
+
+	st, detailErr := status.New(code, validationErr.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: validationErr.Rule,
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, validationErr.Message)
+	}
+
+	return st.Err()
+}
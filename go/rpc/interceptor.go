@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"crud_app/auth"
+	"crud_app/tenant"
+)
+
+const tenantMetadataKey string = "x-tenant-id"
+
+// publicServicePrefixes are served without credentials, like /metrics on the
+// HTTP side.
+var publicServicePrefixes = []string{
+	"/grpc.health.v1.",
+	"/grpc.reflection.",
+}
+
+// authenticate resolves the principal and tenant the way the Authenticate and
+// ResolveTenant HTTP middleware do, reading the same headers from metadata.
+func authenticate(authenticator auth.Authenticator, policy *auth.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		for _, prefix := range publicServicePrefixes {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return handler(ctx, req)
+			}
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		principal, err := authenticator.Authenticate(ctx, requestFromMetadata(ctx, md))
+		if err != nil {
+			return nil, statusFromError(ctx, err)
+		}
+
+		var requested string
+		if values := md.Get(tenantMetadataKey); len(values) > 0 {
+			requested = values[0]
+		}
+		tenantID, err := auth.ResolveTenant(policy, principal, requested)
+		if err != nil {
+			return nil, statusFromError(ctx, err)
+		}
+
+		ctx = auth.WithPrincipal(ctx, principal)
+		ctx = tenant.WithID(ctx, tenantID)
+
+		return handler(ctx, req)
+	}
+}
+
+// requestFromMetadata lets the HTTP-oriented authenticator read credentials
+// from gRPC metadata.
+func requestFromMetadata(ctx context.Context, md metadata.MD) *http.Request {
+	header := make(http.Header, len(md))
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	r := &http.Request{Header: header}
+
+	return r.WithContext(ctx)
+}
@@ -0,0 +1,27 @@
+package rpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"crud_app/auth"
+	"crud_app/proto/userpb"
+	"crud_app/service"
+)
+
+func NewServer(userService service.User, authenticator auth.Authenticator, policy *auth.Policy) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(authenticate(authenticator, policy)))
+
+	userpb.RegisterUserServiceServer(server, NewUserServer(userService))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(userpb.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
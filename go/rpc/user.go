@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"crud_app/dto"
+	"crud_app/errkind"
+	"crud_app/proto/userpb"
+	"crud_app/service"
+)
+
+const (
+	defaultPageSize int32 = 50
+	maxPageSize     int32 = 500
+)
+
+var errInvalidRequest = errkind.ErrInvalidRequest
+
+type userServer struct {
+	userpb.UnimplementedUserServiceServer
+
+	userService service.User
+}
+
+func NewUserServer(userService service.User) userpb.UserServiceServer {
+	return &userServer{userService: userService}
+}
+
+func (s *userServer) ListUsers(ctx context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	pageSize := req.GetPageSize()
+	switch {
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize < 0 || pageSize > maxPageSize:
+		return nil, statusFromError(ctx, fmt.Errorf("%w: page_size must be between 1 and %d", errInvalidRequest, maxPageSize))
+	}
+
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	// Find pages in ID order, so offsets stay stable between pages as long as
+	// nobody is deleted in between.
+	users, total, err := s.userService.Find(ctx, dto.ListQuery{
+		PageRequest: dto.PageRequest{Limit: int(pageSize), Offset: offset},
+	})
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	resp := &userpb.ListUsersResponse{TotalSize: total}
+	for i := range users {
+		resp.Users = append(resp.Users, toProtoUser(&users[i]))
+	}
+	if end := offset + len(users); int64(end) < total {
+		resp.NextPageToken = encodePageToken(end)
+	}
+
+	return resp, nil
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.User, error) {
+	id, err := userID(req.GetId())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	user, err := s.userService.Get(ctx, id)
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	return toProtoUser(user), nil
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.User, error) {
+	user, err := s.userService.Create(ctx, &dto.User{Name: req.GetName(), Age: int(req.GetAge())})
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	return toProtoUser(user), nil
+}
+
+func (s *userServer) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.User, error) {
+	id, err := userID(req.GetId())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	if err := s.userService.Update(ctx, &dto.User{Name: req.GetName(), Age: int(req.GetAge())}, id); err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	user, err := s.userService.Get(ctx, id)
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	return toProtoUser(user), nil
+}
+
+func (s *userServer) DeleteUser(ctx context.Context, req *userpb.DeleteUserRequest) (*emptypb.Empty, error) {
+	id, err := userID(req.GetId())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	if err := s.userService.Delete(ctx, id); err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func toProtoUser(user *dto.User) *userpb.User {
+	return &userpb.User{
+		Id:         uint64(user.ID),
+		Name:       user.Name,
+		Age:        int32(user.Age),
+		CreateTime: timestamppb.New(user.CreatedAt),
+		UpdateTime: timestamppb.New(user.UpdatedAt),
+	}
+}
+
+func userID(id uint64) (uint, error) {
+	if id == 0 {
+		return 0, fmt.Errorf("%w: id is required", errInvalidRequest)
+	}
+
+	return uint(id), nil
+}
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed page_token", errInvalidRequest)
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: malformed page_token", errInvalidRequest)
+	}
+
+	return offset, nil
+}
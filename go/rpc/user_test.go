@@ -0,0 +1,286 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"crud_app/auth"
+	"crud_app/dto"
+	"crud_app/proto/userpb"
+	"crud_app/repository"
+	"crud_app/service"
+	mock_service "crud_app/service/mocks_service"
+	"crud_app/tenant"
+)
+
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Authenticate(_ context.Context, r *http.Request) (*auth.Principal, error) {
+	switch r.Header.Get("Authorization") {
+	case "Bearer good":
+		return &auth.Principal{Subject: "svc", TenantID: "acme"}, nil
+	case "Bearer claimless":
+		return &auth.Principal{Subject: "svc", Method: auth.MethodJWT}, nil
+	default:
+		return nil, auth.ErrUnauthenticated
+	}
+}
+
+func newTestClient(t *testing.T, userService service.User) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(userService, tokenAuthenticator{}, &auth.Policy{Roles: map[string][]string{"reader": {auth.PermissionUsersRead}}})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func authorized(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer good")
+}
+
+func inTenant(tenantID string) any {
+	return gomock.Cond(func(ctx context.Context) bool {
+		id, err := tenant.ID(ctx)
+		return err == nil && id == tenantID
+	})
+}
+
+func TestUserServer_ListUsers(t *testing.T) {
+	users := []dto.User{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}, {ID: 3, Name: "Cid"}}
+
+	type testCase struct {
+		name     string
+		request  *userpb.ListUsersRequest
+		wantPage dto.PageRequest
+		wantIDs  []uint64
+		wantNext bool
+		wantCode codes.Code
+	}
+
+	cases := []testCase{
+		{
+			name:     "first page",
+			request:  &userpb.ListUsersRequest{PageSize: 2},
+			wantPage: dto.PageRequest{Limit: 2},
+			wantIDs:  []uint64{1, 2},
+			wantNext: true,
+		}, {
+			name:     "last page",
+			request:  &userpb.ListUsersRequest{PageSize: 2, PageToken: encodePageToken(2)},
+			wantPage: dto.PageRequest{Limit: 2, Offset: 2},
+			wantIDs:  []uint64{3},
+		}, {
+			name:     "default page size",
+			request:  &userpb.ListUsersRequest{},
+			wantPage: dto.PageRequest{Limit: 50},
+			wantIDs:  []uint64{1, 2, 3},
+		}, {
+			name:     "page size too large",
+			request:  &userpb.ListUsersRequest{PageSize: 501},
+			wantCode: codes.InvalidArgument,
+		}, {
+			name:     "malformed token",
+			request:  &userpb.ListUsersRequest{PageToken: "%%%"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userService := mock_service.NewMockUser(ctrl)
+			if tc.wantCode == codes.OK {
+				page := users[tc.wantPage.Offset:min(tc.wantPage.Offset+tc.wantPage.Limit, len(users))]
+				userService.EXPECT().
+					Find(inTenant("acme"), dto.ListQuery{PageRequest: tc.wantPage}).
+					Return(page, int64(len(users)), nil)
+			}
+
+			client := userpb.NewUserServiceClient(newTestClient(t, userService))
+			resp, err := client.ListUsers(authorized(t.Context()), tc.request)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			var ids []uint64
+			for _, user := range resp.GetUsers() {
+				ids = append(ids, user.GetId())
+			}
+			require.Equal(t, tc.wantIDs, ids)
+			require.Equal(t, tc.wantNext, resp.GetNextPageToken() != "")
+			require.Equal(t, int64(3), resp.GetTotalSize())
+		})
+	}
+}
+
+func TestUserServer_Errors(t *testing.T) {
+	type testCase struct {
+		name       string
+		setupMocks func(*mock_service.MockUser)
+		call       func(context.Context, userpb.UserServiceClient) error
+		wantCode   codes.Code
+		wantReason string
+	}
+
+	cases := []testCase{
+		{
+			name: "validation error",
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Create(gomock.Any(), &dto.User{Name: "A", Age: 20}).
+					Return(nil, &service.ValidationError{Rule: "name_min_length", Message: "name must be at least 2 characters long"})
+			},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.CreateUser(ctx, &userpb.CreateUserRequest{Name: "A", Age: 20})
+				return err
+			},
+			wantCode:   codes.InvalidArgument,
+			wantReason: "name_min_length",
+		}, {
+			name: "missing user on update",
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().
+					Update(gomock.Any(), gomock.Any(), uint(9)).
+					Return(&service.ValidationError{Rule: "user_exists", Message: "user with ID 9 not found", Err: repository.ErrNotFound})
+			},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.UpdateUser(ctx, &userpb.UpdateUserRequest{Id: 9, Name: "Ann", Age: 20})
+				return err
+			},
+			wantCode:   codes.NotFound,
+			wantReason: "user_exists",
+		}, {
+			name: "not found",
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Get(gomock.Any(), uint(9)).Return(nil, repository.ErrNotFound)
+			},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.GetUser(ctx, &userpb.GetUserRequest{Id: 9})
+				return err
+			},
+			wantCode: codes.NotFound,
+		}, {
+			name: "forbidden",
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Delete(gomock.Any(), uint(9)).Return(auth.ErrForbidden)
+			},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: 9})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		}, {
+			name: "internal error is hidden",
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Get(gomock.Any(), uint(9)).Return(nil, errors.New("connection refused"))
+			},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.GetUser(ctx, &userpb.GetUserRequest{Id: 9})
+				require.Equal(t, "internal error", status.Convert(err).Message())
+				return err
+			},
+			wantCode: codes.Internal,
+		}, {
+			name: "canceled",
+			setupMocks: func(ms *mock_service.MockUser) {
+				ms.EXPECT().Get(gomock.Any(), uint(9)).Return(nil, context.Canceled)
+			},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.GetUser(ctx, &userpb.GetUserRequest{Id: 9})
+				return err
+			},
+			wantCode: codes.Canceled,
+		}, {
+			name:       "missing id",
+			setupMocks: func(ms *mock_service.MockUser) {},
+			call: func(ctx context.Context, c userpb.UserServiceClient) error {
+				_, err := c.GetUser(ctx, &userpb.GetUserRequest{})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userService := mock_service.NewMockUser(ctrl)
+			tc.setupMocks(userService)
+
+			client := userpb.NewUserServiceClient(newTestClient(t, userService))
+			err := tc.call(authorized(t.Context()), client)
+
+			st := status.Convert(err)
+			require.Equal(t, tc.wantCode, st.Code())
+			if tc.wantReason != "" {
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, tc.wantReason, info.GetReason())
+			}
+		})
+	}
+}
+
+func TestUserServer_UpdateReturnsUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userService := mock_service.NewMockUser(ctrl)
+	userService.EXPECT().Update(gomock.Any(), &dto.User{Name: "Ann", Age: 31}, uint(1)).Return(nil)
+	userService.EXPECT().Get(gomock.Any(), uint(1)).Return(&dto.User{ID: 1, Name: "Ann", Age: 31}, nil)
+
+	client := userpb.NewUserServiceClient(newTestClient(t, userService))
+	user, err := client.UpdateUser(authorized(t.Context()), &userpb.UpdateUserRequest{Id: 1, Name: "Ann", Age: 31})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), user.GetId())
+	require.Equal(t, int32(31), user.GetAge())
+}
+
+func TestServer_Authentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	conn := newTestClient(t, mock_service.NewMockUser(ctrl))
+
+	_, err := userpb.NewUserServiceClient(conn).GetUser(t.Context(), &userpb.GetUserRequest{Id: 1})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(authorized(t.Context()), tenantMetadataKey, "globex")
+	_, err = userpb.NewUserServiceClient(conn).GetUser(ctx, &userpb.GetUserRequest{Id: 1})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(t.Context(), "authorization", "Bearer claimless", tenantMetadataKey, "globex")
+	_, err = userpb.NewUserServiceClient(conn).GetUser(ctx, &userpb.GetUserRequest{Id: 1})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	resp, err := healthpb.NewHealthClient(conn).Check(t.Context(), &healthpb.HealthCheckRequest{
+		Service: userpb.UserService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
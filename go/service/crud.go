@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+// Validator checks a write before Service applies it.
+type Validator[T any] interface {
+	Create(ctx context.Context, entity *T) error
+	Update(ctx context.Context, entity *T, id uint) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+}
+
+type Service[T any] interface {
+	Find(ctx context.Context, query dto.ListQuery) ([]T, int64, error)
+	Get(ctx context.Context, id uint) (*T, error)
+	Create(ctx context.Context, entity *T) (*T, error)
+	Update(ctx context.Context, entity *T, id uint) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+}
+
+// ChangeHook runs inside the transaction of every write with the record as
+// it was before and after; operation is one of the dto.AuditOperation
+// constants and before is nil on create. An error rolls the write back.
+type ChangeHook[T any] func(ctx context.Context, operation string, before, after *T) error
+
+type crudService[T any] struct {
+	validator  Validator[T]
+	repo       repository.Repo[T]
+	transactor repository.Transactor
+	onChange   ChangeHook[T]
+}
+
+// NewService validates every write and applies it in a transaction together
+// with onChange, which may be nil.
+func NewService[T any](
+	validator Validator[T],
+	repo repository.Repo[T],
+	transactor repository.Transactor,
+	onChange ChangeHook[T],
+) Service[T] {
+	if onChange == nil {
+		onChange = func(context.Context, string, *T, *T) error { return nil }
+	}
+
+	return &crudService[T]{
+		validator:  validator,
+		repo:       repo,
+		transactor: transactor,
+		onChange:   onChange,
+	}
+}
+
+func (s *crudService[T]) Find(ctx context.Context, query dto.ListQuery) ([]T, int64, error) {
+	return s.repo.Find(ctx, query)
+}
+
+func (s *crudService[T]) Get(ctx context.Context, id uint) (*T, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *crudService[T]) Create(ctx context.Context, entity *T) (*T, error) {
+	err := s.validator.Create(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, entity)
+		if err != nil {
+			return err
+		}
+		entity = created
+
+		return s.onChange(ctx, dto.AuditOperationCreate, nil, entity)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+func (s *crudService[T]) Update(ctx context.Context, entity *T, id uint) error {
+	err := s.validator.Update(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.Update(ctx, entity, id)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.onChange(ctx, dto.AuditOperationUpdate, before, after)
+	})
+}
+
+func (s *crudService[T]) Delete(ctx context.Context, id uint) error {
+	err := s.validator.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.onChange(ctx, dto.AuditOperationDelete, before, after)
+	})
+}
+
+func (s *crudService[T]) Restore(ctx context.Context, id uint) error {
+	err := s.validator.Restore(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.onChange(ctx, dto.AuditOperationRestore, before, after)
+	})
+}
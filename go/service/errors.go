@@ -0,0 +1,28 @@
+package service
+
+import "crud_app/repository"
+
+// ValidationError reports the rule a request broke. Err, when set, is the
+// sentinel the failure also stands for, such as repository.ErrNotFound for a
+// record that does not exist.
+type ValidationError struct {
+	Rule    string
+	Message string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func newValidationError(rule, message string) error {
+	return &ValidationError{Rule: rule, Message: message}
+}
+
+func newNotFoundError(rule, message string) error {
+	return &ValidationError{Rule: rule, Message: message, Err: repository.ErrNotFound}
+}
@@ -0,0 +1,210 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: crud.go
+//
+// Generated by this command:
+//
+//	mockgen -source=crud.go -destination=./mocks_service/mock_crud.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockValidator is a mock of Validator interface.
+type MockValidator[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockValidatorMockRecorder[T]
+	isgomock struct{}
+}
+
+// MockValidatorMockRecorder is the mock recorder for MockValidator.
+type MockValidatorMockRecorder[T any] struct {
+	mock *MockValidator[T]
+}
+
+// NewMockValidator creates a new mock instance.
+func NewMockValidator[T any](ctrl *gomock.Controller) *MockValidator[T] {
+	mock := &MockValidator[T]{ctrl: ctrl}
+	mock.recorder = &MockValidatorMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValidator[T]) EXPECT() *MockValidatorMockRecorder[T] {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockValidator[T]) Create(ctx context.Context, entity *T) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, entity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockValidatorMockRecorder[T]) Create(ctx, entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockValidator[T])(nil).Create), ctx, entity)
+}
+
+// Delete mocks base method.
+func (m *MockValidator[T]) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockValidatorMockRecorder[T]) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockValidator[T])(nil).Delete), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockValidator[T]) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockValidatorMockRecorder[T]) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockValidator[T])(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockValidator[T]) Update(ctx context.Context, entity *T, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, entity, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockValidatorMockRecorder[T]) Update(ctx, entity, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockValidator[T])(nil).Update), ctx, entity, id)
+}
+
+// MockService is a mock of Service interface.
+type MockService[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder[T]
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder[T any] struct {
+	mock *MockService[T]
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService[T any](ctrl *gomock.Controller) *MockService[T] {
+	mock := &MockService[T]{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService[T]) EXPECT() *MockServiceMockRecorder[T] {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockService[T]) Create(ctx context.Context, entity *T) (*T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, entity)
+	ret0, _ := ret[0].(*T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceMockRecorder[T]) Create(ctx, entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService[T])(nil).Create), ctx, entity)
+}
+
+// Delete mocks base method.
+func (m *MockService[T]) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceMockRecorder[T]) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService[T])(nil).Delete), ctx, id)
+}
+
+// Find mocks base method.
+func (m *MockService[T]) Find(ctx context.Context, query dto.ListQuery) ([]T, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, query)
+	ret0, _ := ret[0].([]T)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockServiceMockRecorder[T]) Find(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService[T])(nil).Find), ctx, query)
+}
+
+// Get mocks base method.
+func (m *MockService[T]) Get(ctx context.Context, id uint) (*T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockServiceMockRecorder[T]) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockService[T])(nil).Get), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockService[T]) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder[T]) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService[T])(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockService[T]) Update(ctx context.Context, entity *T, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, entity, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockServiceMockRecorder[T]) Update(ctx, entity, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService[T])(nil).Update), ctx, entity, id)
+}
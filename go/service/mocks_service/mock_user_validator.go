@@ -42,17 +42,17 @@ func (m *MockUserValidator) EXPECT() *MockUserValidatorMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockUserValidator) Create(ctx context.Context, user *dto.User) error {
+func (m *MockUserValidator) Create(ctx context.Context, entity *dto.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, user)
+	ret := m.ctrl.Call(m, "Create", ctx, entity)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserValidatorMockRecorder) Create(ctx, user any) *gomock.Call {
+func (mr *MockUserValidatorMockRecorder) Create(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserValidator)(nil).Create), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserValidator)(nil).Create), ctx, entity)
 }
 
 // Delete mocks base method.
@@ -69,16 +69,30 @@ func (mr *MockUserValidatorMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserValidator)(nil).Delete), ctx, id)
 }
 
+// Restore mocks base method.
+func (m *MockUserValidator) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserValidatorMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserValidator)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockUserValidator) Update(ctx context.Context, user *dto.User, id uint) error {
+func (m *MockUserValidator) Update(ctx context.Context, entity *dto.User, id uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, user, id)
+	ret := m.ctrl.Call(m, "Update", ctx, entity, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserValidatorMockRecorder) Update(ctx, user, id any) *gomock.Call {
+func (mr *MockUserValidatorMockRecorder) Update(ctx, entity, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserValidator)(nil).Update), ctx, user, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserValidator)(nil).Update), ctx, entity, id)
 }
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook_validator.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook_validator.go -destination=./mocks_service/mock_webhook_validator.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	dto "crud_app/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookValidator is a mock of WebhookValidator interface.
+type MockWebhookValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookValidatorMockRecorder is the mock recorder for MockWebhookValidator.
+type MockWebhookValidatorMockRecorder struct {
+	mock *MockWebhookValidator
+}
+
+// NewMockWebhookValidator creates a new mock instance.
+func NewMockWebhookValidator(ctrl *gomock.Controller) *MockWebhookValidator {
+	mock := &MockWebhookValidator{ctrl: ctrl}
+	mock.recorder = &MockWebhookValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookValidator) EXPECT() *MockWebhookValidatorMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookValidator) Create(ctx context.Context, subscription *dto.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookValidatorMockRecorder) Create(ctx, subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookValidator)(nil).Create), ctx, subscription)
+}
+
+// Update mocks base method.
+func (m *MockWebhookValidator) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, subscription, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookValidatorMockRecorder) Update(ctx, subscription, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookValidator)(nil).Update), ctx, subscription, id)
+}
@@ -2,28 +2,62 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
 
 	"crud_app/dto"
+	"crud_app/events"
 	"crud_app/repository"
 )
 
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
 type User interface {
+	Service[dto.User]
 	List(ctx context.Context) ([]dto.User, error)
-	Create(ctx context.Context, user *dto.User) (*dto.User, error)
-	Update(ctx context.Context, user *dto.User, id uint) error
-	Delete(ctx context.Context, id uint) error
+	Export(ctx context.Context) iter.Seq2[dto.User, error]
+	ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error)
+	// LastModified is when any user last changed, deletes and restores
+	// included, or zero before the first write.
+	LastModified(ctx context.Context) (time.Time, error)
+	GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error)
+	History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error)
+	Revert(ctx context.Context, id uint, at time.Time) error
+	// Purge removes a user and its history for good, where Delete can be
+	// undone. Only the audit trail keeps a record of it.
+	Purge(ctx context.Context, id uint) error
 }
 
+// user is the generic Service with an audit entry and a domain event
+// recorded for every write.
 type user struct {
+	Service[dto.User]
 	userValidator UserValidator
 	userRepo      repository.UserRepo
+	transactor    repository.Transactor
+	auditRepo     repository.UserAuditRepo
+	eventRecorder events.Recorder
 }
 
-func NewUser(userValidator UserValidator, userRepo repository.UserRepo) User {
-	return &user{
+func NewUser(
+	userValidator UserValidator,
+	userRepo repository.UserRepo,
+	transactor repository.Transactor,
+	auditRepo repository.UserAuditRepo,
+	eventRecorder events.Recorder,
+) User {
+	s := &user{
 		userValidator: userValidator,
 		userRepo:      userRepo,
+		transactor:    transactor,
+		auditRepo:     auditRepo,
+		eventRecorder: eventRecorder,
 	}
+	s.Service = NewService(userValidator, userRepo, transactor, s.onChange)
+
+	return s
 }
 
 func (s *user) List(ctx context.Context) ([]dto.User, error) {
@@ -35,45 +69,111 @@ func (s *user) List(ctx context.Context) ([]dto.User, error) {
 	return users, err
 }
 
-func (s *user) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
-	err := s.userValidator.Create(ctx, user)
+// Export is List for callers that cannot hold every user at once.
+func (s *user) Export(ctx context.Context) iter.Seq2[dto.User, error] {
+	return s.userRepo.Iterate(ctx)
+}
 
-	if err != nil {
-		return nil, err
-	}
+func (s *user) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	return s.userRepo.ListAsOf(ctx, at)
+}
 
-	user, err = s.userRepo.Create(ctx, user)
-	if err != nil {
-		return nil, err
-	}
+func (s *user) LastModified(ctx context.Context) (time.Time, error) {
+	return s.userRepo.LastModified(ctx)
+}
 
-	return user, nil
+func (s *user) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	return s.userRepo.GetAsOf(ctx, id, at)
 }
 
-func (s *user) Update(ctx context.Context, user *dto.User, id uint) error {
-	err := s.userValidator.Update(ctx, user, id)
+func (s *user) History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error) {
+	entries, total, err := s.auditRepo.ListByUser(ctx, id, page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = s.userRepo.Update(ctx, user, id)
-	if err != nil {
-		return err
-	}
+	return &dto.Page[dto.UserAudit]{
+		Items:  entries,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}, nil
+}
 
-	return nil
+// Revert brings a user back to the state it had at the given instant,
+// restoring it first if it has been deleted since. Both steps go through the
+// validator like any other write.
+func (s *user) Revert(ctx context.Context, id uint, at time.Time) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		target, err := s.userRepo.GetAsOf(ctx, id, at)
+		if errors.Is(err, repository.ErrNotFound) {
+			return newValidationError("user_version", fmt.Sprintf("user with ID %d did not exist at %s", id, at.Format(time.RFC3339)))
+		}
+		if err != nil {
+			return err
+		}
+
+		before, err := s.userRepo.GetDeleted(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			before, err = s.userRepo.Get(ctx, id)
+		} else if err == nil {
+			if err := s.userValidator.Restore(ctx, id); err != nil {
+				return err
+			}
+			err = s.userRepo.Restore(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		reverted := &dto.User{Name: target.Name, Age: target.Age}
+		if err := s.userValidator.Update(ctx, reverted, id); err != nil {
+			return err
+		}
+		if err := s.userRepo.Update(ctx, reverted, id); err != nil {
+			return err
+		}
+
+		after, err := s.userRepo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.recordChange(ctx, dto.AuditOperationRevert, events.UserUpdated, id, before, after)
+	})
 }
 
-func (s *user) Delete(ctx context.Context, id uint) error {
-	err := s.userValidator.Delete(ctx, id)
-	if err != nil {
-		return err
-	}
+func (s *user) Purge(ctx context.Context, id uint) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.userRepo.GetDeleted(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			before, err = s.userRepo.Get(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Purge(ctx, id); err != nil {
+			return err
+		}
+
+		return s.recordChange(ctx, dto.AuditOperationPurge, events.UserPurged, id, before, nil)
+	})
+}
 
-	err = s.userRepo.Delete(ctx, id)
-	if err != nil {
-		return err
-	}
+// userEventTypes maps the operations of the generic service to the events
+// they publish.
+var userEventTypes = map[string]string{
+	dto.AuditOperationCreate:  events.UserCreated,
+	dto.AuditOperationUpdate:  events.UserUpdated,
+	dto.AuditOperationDelete:  events.UserDeleted,
+	dto.AuditOperationRestore: events.UserUpdated,
+}
+
+func (s *user) onChange(ctx context.Context, operation string, before, after *dto.User) error {
+	return s.recordChange(ctx, operation, userEventTypes[operation], after.ID, before, after)
+}
 
-	return nil
+func (s *user) recordChange(ctx context.Context, operation, eventType string, id uint, before, after *dto.User) error {
+	return recordUserChange(ctx, s.auditRepo, s.eventRecorder, operation, eventType, id, before, after)
 }
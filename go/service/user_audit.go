@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"crud_app/auth"
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/logging"
+	"crud_app/repository"
+)
+
+const anonymousActor string = "anonymous"
+
+type fieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// userDiff returns the fields that differ between two snapshots of a user;
+// a nil snapshot stands for "did not exist".
+func userDiff(before, after *dto.User) (json.RawMessage, error) {
+	oldFields := userFields(before)
+	newFields := userFields(after)
+
+	diff := map[string]fieldChange{}
+	for _, field := range []string{"name", "age", "deleted_at"} {
+		if oldFields[field] != newFields[field] {
+			diff[field] = fieldChange{Old: oldFields[field], New: newFields[field]}
+		}
+	}
+
+	return json.Marshal(diff)
+}
+
+func userFields(user *dto.User) map[string]any {
+	fields := map[string]any{"name": nil, "age": nil, "deleted_at": nil}
+	if user == nil {
+		return fields
+	}
+
+	fields["name"] = user.Name
+	fields["age"] = user.Age
+	if user.DeletedAt.Valid {
+		fields["deleted_at"] = user.DeletedAt.Time.UTC().Format(time.RFC3339Nano)
+	}
+
+	return fields
+}
+
+func newUserAudit(ctx context.Context, operation string, userID uint, before, after *dto.User) (*dto.UserAudit, error) {
+	diff, err := userDiff(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UserAudit{
+		UserID:    userID,
+		Operation: operation,
+		Actor:     actorFrom(ctx),
+		RequestID: logging.RequestID(ctx),
+		Diff:      diff,
+	}, nil
+}
+
+func actorFrom(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		return principal.Subject
+	}
+
+	return anonymousActor
+}
+
+// recordUserChange writes the audit entry and the domain event for a
+// mutation; it must run inside the mutation's transaction.
+func recordUserChange(
+	ctx context.Context,
+	auditRepo repository.UserAuditRepo,
+	eventRecorder events.Recorder,
+	operation, eventType string,
+	id uint,
+	before, after *dto.User,
+) error {
+	entry, err := newUserAudit(ctx, operation, id, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := auditRepo.Append(ctx, entry); err != nil {
+		return err
+	}
+
+	return eventRecorder.Record(ctx, eventType, id, after)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"crud_app/auth"
+	"crud_app/dto"
+)
+
+type userWithAuthorization struct {
+	next   User
+	policy *auth.Policy
+}
+
+func NewUserWithAuthorization(next User, policy *auth.Policy) User {
+	return &userWithAuthorization{next: next, policy: policy}
+}
+
+func (s *userWithAuthorization) List(ctx context.Context) ([]dto.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.List(ctx)
+}
+
+func (s *userWithAuthorization) Export(ctx context.Context) iter.Seq2[dto.User, error] {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return func(yield func(dto.User, error) bool) {
+			yield(dto.User{}, err)
+		}
+	}
+
+	return s.next.Export(ctx)
+}
+
+func (s *userWithAuthorization) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.ListAsOf(ctx, at)
+}
+
+func (s *userWithAuthorization) LastModified(ctx context.Context) (time.Time, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return time.Time{}, err
+	}
+
+	return s.next.LastModified(ctx)
+}
+
+func (s *userWithAuthorization) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, 0, err
+	}
+
+	return s.next.Find(ctx, query)
+}
+
+func (s *userWithAuthorization) Get(ctx context.Context, id uint) (*dto.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.Get(ctx, id)
+}
+
+func (s *userWithAuthorization) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.GetAsOf(ctx, id, at)
+}
+
+func (s *userWithAuthorization) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
+	return s.next.Create(ctx, user)
+}
+
+func (s *userWithAuthorization) Update(ctx context.Context, user *dto.User, id uint) error {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return err
+	}
+
+	return s.next.Update(ctx, user, id)
+}
+
+func (s *userWithAuthorization) Delete(ctx context.Context, id uint) error {
+	if err := s.authorize(ctx, auth.PermissionUsersDelete); err != nil {
+		return err
+	}
+
+	return s.next.Delete(ctx, id)
+}
+
+func (s *userWithAuthorization) Restore(ctx context.Context, id uint) error {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return err
+	}
+
+	return s.next.Restore(ctx, id)
+}
+
+func (s *userWithAuthorization) History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.History(ctx, id, page)
+}
+
+func (s *userWithAuthorization) Revert(ctx context.Context, id uint, at time.Time) error {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return err
+	}
+
+	return s.next.Revert(ctx, id, at)
+}
+
+func (s *userWithAuthorization) Purge(ctx context.Context, id uint) error {
+	if err := s.authorize(ctx, auth.PermissionUsersPurge); err != nil {
+		return err
+	}
+
+	return s.next.Purge(ctx, id)
+}
+
+func (s *userWithAuthorization) authorize(ctx context.Context, permission string) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+
+	return s.policy.Authorize(principal, permission)
+}
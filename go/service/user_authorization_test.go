@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"crud_app/auth"
+	"crud_app/dto"
+)
+
+var testPolicy = &auth.Policy{
+	Roles: map[string][]string{
+		"reader":  {auth.PermissionUsersRead},
+		"editor":  {auth.PermissionUsersRead, auth.PermissionUsersWrite},
+		"admin":   {auth.PermissionUsersRead, auth.PermissionUsersWrite, auth.PermissionUsersDelete, auth.PermissionUsersPurge},
+		"remover": {auth.PermissionUsersRead, auth.PermissionUsersDelete},
+	},
+}
+
+type allowAllUser struct{}
+
+func (allowAllUser) List(ctx context.Context) ([]dto.User, error) { return testUsers, nil }
+func (allowAllUser) Export(ctx context.Context) iter.Seq2[dto.User, error] {
+	return func(yield func(dto.User, error) bool) {}
+}
+func (allowAllUser) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	return testUsers, nil
+}
+func (allowAllUser) LastModified(ctx context.Context) (time.Time, error) {
+	return time.Time{}, nil
+}
+func (allowAllUser) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	return testUsers, int64(len(testUsers)), nil
+}
+func (allowAllUser) Get(ctx context.Context, id uint) (*dto.User, error) { return testUserWithID, nil }
+func (allowAllUser) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	return testUserWithID, nil
+}
+func (allowAllUser) Revert(ctx context.Context, id uint, at time.Time) error { return nil }
+func (allowAllUser) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	return testUserWithID, nil
+}
+func (allowAllUser) Update(ctx context.Context, user *dto.User, id uint) error { return nil }
+func (allowAllUser) Delete(ctx context.Context, id uint) error                 { return nil }
+func (allowAllUser) Restore(ctx context.Context, id uint) error                { return nil }
+func (allowAllUser) Purge(ctx context.Context, id uint) error                  { return nil }
+func (allowAllUser) History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error) {
+	return &dto.Page[dto.UserAudit]{}, nil
+}
+
+func TestUserWithAuthorization(t *testing.T) {
+	type testCase struct {
+		name          string
+		principal     *auth.Principal
+		call          func(User, context.Context) error
+		expectedError error
+	}
+
+	list := func(s User, ctx context.Context) error { _, err := s.List(ctx); return err }
+	find := func(s User, ctx context.Context) error { _, _, err := s.Find(ctx, dto.ListQuery{}); return err }
+	lastModified := func(s User, ctx context.Context) error { _, err := s.LastModified(ctx); return err }
+	create := func(s User, ctx context.Context) error { _, err := s.Create(ctx, testUser); return err }
+	update := func(s User, ctx context.Context) error { return s.Update(ctx, testUser, id) }
+	remove := func(s User, ctx context.Context) error { return s.Delete(ctx, id) }
+	purge := func(s User, ctx context.Context) error { return s.Purge(ctx, id) }
+	export := func(s User, ctx context.Context) error {
+		for _, err := range s.Export(ctx) {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	reader := &auth.Principal{Subject: "r", Roles: []string{"reader"}}
+	editor := &auth.Principal{Subject: "e", Roles: []string{"editor"}}
+	admin := &auth.Principal{Subject: "a", Roles: []string{"unknown", "admin"}}
+	remover := &auth.Principal{Subject: "d", Roles: []string{"remover"}}
+
+	cases := []testCase{
+		{name: "reader can list", principal: reader, call: list},
+		{name: "reader cannot create", principal: reader, call: create, expectedError: auth.ErrForbidden},
+		{name: "reader cannot delete", principal: reader, call: remove, expectedError: auth.ErrForbidden},
+		{name: "editor can update", principal: editor, call: update},
+		{name: "editor cannot delete", principal: editor, call: remove, expectedError: auth.ErrForbidden},
+		{name: "admin can delete", principal: admin, call: remove},
+		{name: "admin can purge", principal: admin, call: purge},
+		{name: "delete does not grant purge", principal: remover, call: purge, expectedError: auth.ErrForbidden},
+		{name: "anonymous cannot list", principal: nil, call: list, expectedError: auth.ErrUnauthenticated},
+		{name: "reader can export", principal: reader, call: export},
+		{name: "reader can find", principal: reader, call: find},
+		{name: "anonymous cannot find", principal: nil, call: find, expectedError: auth.ErrUnauthenticated},
+		{name: "reader can read last modified", principal: reader, call: lastModified},
+		{name: "anonymous cannot read last modified", principal: nil, call: lastModified, expectedError: auth.ErrUnauthenticated},
+		{name: "anonymous cannot export", principal: nil, call: export, expectedError: auth.ErrUnauthenticated},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = auth.WithPrincipal(ctx, tc.principal)
+			}
+
+			err := tc.call(NewUserWithAuthorization(allowAllUser{}, testPolicy), ctx)
+
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
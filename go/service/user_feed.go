@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"crud_app/events"
+	"crud_app/repository"
+	"crud_app/tenant"
+)
+
+// UserFeed exposes user changes as a sequence-numbered log that can be read
+// from any point and followed live.
+type UserFeed interface {
+	Changes(ctx context.Context, afterID uint64, eventTypes []string, limit int) ([]events.Event, error)
+	Subscribe(ctx context.Context, eventTypes []string) (*events.Subscription, error)
+}
+
+type userFeed struct {
+	outboxRepo repository.OutboxRepo
+	broker     *events.Broker
+}
+
+func NewUserFeed(outboxRepo repository.OutboxRepo, broker *events.Broker) UserFeed {
+	return &userFeed{outboxRepo: outboxRepo, broker: broker}
+}
+
+func (s *userFeed) Changes(ctx context.Context, afterID uint64, eventTypes []string, limit int) ([]events.Event, error) {
+	outboxEvents, err := s.outboxRepo.ListSince(ctx, afterID, eventTypes, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]events.Event, 0, len(outboxEvents))
+	for _, outboxEvent := range outboxEvents {
+		changes = append(changes, events.FromOutbox(outboxEvent))
+	}
+
+	return changes, nil
+}
+
+func (s *userFeed) Subscribe(ctx context.Context, eventTypes []string) (*events.Subscription, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.broker.Subscribe(tenantID, eventTypes), nil
+}
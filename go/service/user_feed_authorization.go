@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"crud_app/auth"
+	"crud_app/events"
+)
+
+type userFeedWithAuthorization struct {
+	next   UserFeed
+	policy *auth.Policy
+}
+
+func NewUserFeedWithAuthorization(next UserFeed, policy *auth.Policy) UserFeed {
+	return &userFeedWithAuthorization{next: next, policy: policy}
+}
+
+func (s *userFeedWithAuthorization) Changes(ctx context.Context, afterID uint64, eventTypes []string, limit int) ([]events.Event, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.next.Changes(ctx, afterID, eventTypes, limit)
+}
+
+func (s *userFeedWithAuthorization) Subscribe(ctx context.Context, eventTypes []string) (*events.Subscription, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.next.Subscribe(ctx, eventTypes)
+}
+
+func (s *userFeedWithAuthorization) authorize(ctx context.Context) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+
+	return s.policy.Authorize(principal, auth.PermissionUsersRead)
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/jobs"
+	"crud_app/repository"
+)
+
+const (
+	ImportFormatCSV    string = "csv"
+	ImportFormatNDJSON string = "ndjson"
+)
+
+// UserImport loads users in bulk from uploaded files. Imports run as
+// background jobs: Start returns once the upload is stored, and the job
+// reports progress and rejected rows as it goes.
+type UserImport interface {
+	Start(ctx context.Context, body io.Reader, format string, dryRun bool) (*dto.Job, error)
+	Job(ctx context.Context, id uint64) (*dto.Job, error)
+	Errors(ctx context.Context, id uint64) ([]dto.JobError, error)
+}
+
+type UserImportConfig struct {
+	// BatchSize is the number of rows written per transaction and between
+	// progress updates.
+	BatchSize int
+	// SpoolDir holds uploads until their job has run; empty means the
+	// system temp directory.
+	SpoolDir string
+	// MaxErrors caps the rejected rows stored per job; rows beyond it are
+	// only counted.
+	MaxErrors int
+	// MaxUploadSize is the largest upload in bytes; larger ones fail with
+	// ErrUploadTooLarge. Zero means no limit.
+	MaxUploadSize int64
+	// Owner names this process on the jobs it starts, see jobs.Lease.
+	Owner string
+}
+
+func DefaultUserImportConfig() UserImportConfig {
+	return UserImportConfig{
+		BatchSize: 500,
+		MaxErrors: 10000,
+	}
+}
+
+var ErrUploadTooLarge = errors.New("upload too large")
+
+type userImport struct {
+	userValidator UserValidator
+	userRepo      repository.UserRepo
+	transactor    repository.Transactor
+	auditRepo     repository.UserAuditRepo
+	eventRecorder events.Recorder
+	jobRepo       repository.JobRepo
+	runner        *jobs.Runner
+	cfg           UserImportConfig
+	now           func() time.Time
+}
+
+func NewUserImport(
+	userValidator UserValidator,
+	userRepo repository.UserRepo,
+	transactor repository.Transactor,
+	auditRepo repository.UserAuditRepo,
+	eventRecorder events.Recorder,
+	jobRepo repository.JobRepo,
+	runner *jobs.Runner,
+	cfg UserImportConfig,
+) UserImport {
+	return &userImport{
+		userValidator: userValidator,
+		userRepo:      userRepo,
+		transactor:    transactor,
+		auditRepo:     auditRepo,
+		eventRecorder: eventRecorder,
+		jobRepo:       jobRepo,
+		runner:        runner,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+func (s *userImport) Start(ctx context.Context, body io.Reader, format string, dryRun bool) (*dto.Job, error) {
+	if format != ImportFormatCSV && format != ImportFormatNDJSON {
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+
+	upload, err := os.CreateTemp(s.cfg.SpoolDir, "user-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool upload: %w", err)
+	}
+
+	// One byte past the limit tells an upload of exactly the limit from a
+	// larger one.
+	if s.cfg.MaxUploadSize > 0 {
+		body = io.LimitReader(body, s.cfg.MaxUploadSize+1)
+	}
+	size, err := io.Copy(upload, body)
+	if err == nil {
+		_, err = upload.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		discardUpload(upload)
+		return nil, fmt.Errorf("failed to spool upload: %w", err)
+	}
+	if s.cfg.MaxUploadSize > 0 && size > s.cfg.MaxUploadSize {
+		discardUpload(upload)
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrUploadTooLarge, s.cfg.MaxUploadSize)
+	}
+
+	now := s.now()
+	job, err := s.jobRepo.Create(ctx, &dto.Job{
+		Kind:       dto.JobKindUserImport,
+		Status:     dto.JobStatusPending,
+		DryRun:     dryRun,
+		CreatedBy:  actorFrom(ctx),
+		BytesTotal: size,
+		Owner:      s.cfg.Owner,
+		RenewedAt:  &now,
+	})
+	if err != nil {
+		discardUpload(upload)
+		return nil, err
+	}
+
+	err = s.runner.Submit(ctx, func(ctx context.Context) {
+		defer discardUpload(upload)
+		s.run(ctx, job.ID, upload, format, dryRun)
+	})
+	if err != nil {
+		discardUpload(upload)
+		if err := s.jobRepo.Finish(ctx, job.ID, dto.JobStatusFailed, err.Error(), s.now()); err != nil {
+			slog.ErrorContext(ctx, "import job could not be finished", slog.Uint64("job_id", job.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *userImport) Job(ctx context.Context, id uint64) (*dto.Job, error) {
+	return s.jobRepo.Get(ctx, id)
+}
+
+func (s *userImport) Errors(ctx context.Context, id uint64) ([]dto.JobError, error) {
+	return s.jobRepo.ListErrors(ctx, id)
+}
+
+func (s *userImport) run(ctx context.Context, jobID uint64, upload io.Reader, format string, dryRun bool) {
+	err := s.jobRepo.Start(ctx, jobID, s.now())
+	if err == nil {
+		err = s.process(ctx, jobID, upload, format, dryRun)
+	}
+
+	status, lastError := dto.JobStatusSucceeded, ""
+	if err != nil {
+		status, lastError = dto.JobStatusFailed, err.Error()
+		slog.WarnContext(ctx, "import job failed", slog.Uint64("job_id", jobID), slog.Any("error", err))
+	}
+
+	// The job is recorded as failed even when the runner is shutting down.
+	if err := s.jobRepo.Finish(context.WithoutCancel(ctx), jobID, status, lastError, s.now()); err != nil {
+		slog.ErrorContext(ctx, "import job could not be finished", slog.Uint64("job_id", jobID), slog.Any("error", err))
+	}
+}
+
+// process validates every row and, unless dryRun is set, writes the valid
+// ones batch by batch. Batches already written stay written if a later one
+// fails.
+func (s *userImport) process(ctx context.Context, jobID uint64, upload io.Reader, format string, dryRun bool) error {
+	counter := &countingReader{r: upload}
+	rows, err := newImportRows(counter, format)
+	if err != nil {
+		return err
+	}
+
+	var progress dto.JobProgress
+	var jobErrors []dto.JobError
+	batch := make([]dto.User, 0, s.cfg.BatchSize)
+
+	flush := func() error {
+		if !dryRun && len(batch) > 0 {
+			if err := s.importBatch(ctx, batch); err != nil {
+				return err
+			}
+			progress.Imported += len(batch)
+		}
+		batch = batch[:0]
+
+		if err := s.jobRepo.AddErrors(ctx, jobErrors); err != nil {
+			return err
+		}
+		jobErrors = jobErrors[:0]
+
+		progress.BytesRead = counter.n
+		return s.jobRepo.UpdateProgress(ctx, jobID, progress)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		progress.Processed++
+		if row.err == nil {
+			row.err = s.userValidator.Create(ctx, &row.user)
+
+			var validationErr *ValidationError
+			if row.err != nil && !errors.As(row.err, &validationErr) {
+				return row.err
+			}
+		}
+
+		if row.err != nil {
+			if progress.Invalid < s.cfg.MaxErrors {
+				jobErrors = append(jobErrors, dto.JobError{JobID: jobID, Line: row.line, Message: row.err.Error()})
+			}
+			progress.Invalid++
+		} else {
+			progress.Valid++
+			batch = append(batch, row.user)
+		}
+
+		if progress.Processed%s.cfg.BatchSize == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+func (s *userImport) importBatch(ctx context.Context, users []dto.User) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := s.userRepo.CreateBatch(ctx, users)
+		if err != nil {
+			return err
+		}
+
+		for i := range created {
+			user := &created[i]
+			if err := recordUserChange(ctx, s.auditRepo, s.eventRecorder, dto.AuditOperationImport, events.UserCreated, user.ID, nil, user); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func discardUpload(upload *os.File) {
+	upload.Close()
+	os.Remove(upload.Name())
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"crud_app/auth"
+	"crud_app/dto"
+)
+
+type userImportWithAuthorization struct {
+	next   UserImport
+	policy *auth.Policy
+}
+
+func NewUserImportWithAuthorization(next UserImport, policy *auth.Policy) UserImport {
+	return &userImportWithAuthorization{next: next, policy: policy}
+}
+
+func (s *userImportWithAuthorization) Start(ctx context.Context, body io.Reader, format string, dryRun bool) (*dto.Job, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
+	return s.next.Start(ctx, body, format, dryRun)
+}
+
+func (s *userImportWithAuthorization) Job(ctx context.Context, id uint64) (*dto.Job, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.Job(ctx, id)
+}
+
+func (s *userImportWithAuthorization) Errors(ctx context.Context, id uint64) ([]dto.JobError, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.Errors(ctx, id)
+}
+
+func (s *userImportWithAuthorization) authorize(ctx context.Context, permission string) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+
+	return s.policy.Authorize(principal, permission)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"crud_app/dto"
+)
+
+const maxNDJSONLineSize int = 1 << 20
+
+// importRow is one record of an upload. err is set when the record cannot be
+// read as a user; such rows are reported, not fatal.
+type importRow struct {
+	line int
+	user dto.User
+	err  error
+}
+
+// importRows yields the rows of an upload one at a time, returning io.EOF
+// after the last one.
+type importRows interface {
+	Next() (importRow, error)
+}
+
+func newImportRows(r io.Reader, format string) (importRows, error) {
+	switch format {
+	case ImportFormatCSV:
+		return newCSVImportRows(r)
+	case ImportFormatNDJSON:
+		return newNDJSONImportRows(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// csvImportRows reads the name and age columns, found by their header in any
+// order and case; other columns are ignored.
+type csvImportRows struct {
+	reader *csv.Reader
+	name   int
+	age    int
+}
+
+func newCSVImportRows(r io.Reader) (*csvImportRows, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV upload has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CSV header cannot be read: %w", err)
+	}
+
+	rows := &csvImportRows{reader: reader, name: -1, age: -1}
+	for i, column := range header {
+		// Spreadsheet exports often start with a byte order mark.
+		column = strings.TrimPrefix(column, "\ufeff")
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "name":
+			rows.name = i
+		case "age":
+			rows.age = i
+		}
+	}
+	if rows.name < 0 || rows.age < 0 {
+		return nil, errors.New("CSV header must have name and age columns")
+	}
+
+	return rows, nil
+}
+
+func (c *csvImportRows) Next() (importRow, error) {
+	record, err := c.reader.Read()
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return importRow{line: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return importRow{}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	row := importRow{line: line}
+
+	if c.name >= len(record) || c.age >= len(record) {
+		row.err = errors.New("row has no name or age column")
+		return row, nil
+	}
+
+	row.user.Name = record[c.name]
+
+	age := strings.TrimSpace(record[c.age])
+	row.user.Age, err = strconv.Atoi(age)
+	if err != nil {
+		row.err = fmt.Errorf("age %q is not a whole number", age)
+	}
+
+	return row, nil
+}
+
+// ndjsonImportRows reads one JSON user per line; blank lines are skipped.
+type ndjsonImportRows struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONImportRows(r io.Reader) *ndjsonImportRows {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	return &ndjsonImportRows{scanner: scanner}
+}
+
+func (n *ndjsonImportRows) Next() (importRow, error) {
+	for n.scanner.Scan() {
+		n.line++
+
+		data := bytes.TrimSpace(n.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var record struct {
+			Name string `json:"name"`
+			Age  int    `json:"age"`
+		}
+		if err := json.Unmarshal(data, &record); err != nil {
+			return importRow{line: n.line, err: fmt.Errorf("invalid JSON: %w", err)}, nil
+		}
+
+		return importRow{line: n.line, user: dto.User{Name: record.Name, Age: record.Age}}, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return importRow{}, fmt.Errorf("line %d is longer than %d bytes", n.line+1, maxNDJSONLineSize)
+		}
+		return importRow{}, err
+	}
+
+	return importRow{}, io.EOF
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/jobs"
+	mock_repository "crud_app/repository/mocks_repository"
+)
+
+const testJobID uint64 = 7
+
+func TestUserImport_Process(t *testing.T) {
+	type testCase struct {
+		name       string
+		format     string
+		upload     string
+		dryRun     bool
+		batches    []int
+		progress   dto.JobProgress
+		errorLines []int
+		wantError  string
+	}
+
+	cases := []testCase{
+		{
+			name:     "csv in batches",
+			format:   ImportFormatCSV,
+			upload:   "Age,email,Name\n30,a@example.com,Alice\n40,b@example.com,Bob\n50,c@example.com,Carol\n",
+			batches:  []int{2, 1},
+			progress: dto.JobProgress{Processed: 3, Valid: 3, Imported: 3},
+		}, {
+			name:       "csv with invalid rows",
+			format:     ImportFormatCSV,
+			upload:     "name,age\nAlice,30\nB,40\nCarol,old\n\"Dave,20\nEve,25\n",
+			batches:    []int{1},
+			progress:   dto.JobProgress{Processed: 4, Valid: 1, Invalid: 3, Imported: 1},
+			errorLines: []int{3, 4, 5},
+		}, {
+			name:       "csv dry run",
+			format:     ImportFormatCSV,
+			upload:     "name,age\nAlice,30\nBob,0\n",
+			dryRun:     true,
+			progress:   dto.JobProgress{Processed: 2, Valid: 1, Invalid: 1},
+			errorLines: []int{3},
+		}, {
+			name:      "csv without age column",
+			format:    ImportFormatCSV,
+			upload:    "name\nAlice\n",
+			wantError: "CSV header must have name and age columns",
+		}, {
+			name:       "ndjson",
+			format:     ImportFormatNDJSON,
+			upload:     "{\"name\":\"Alice\",\"age\":30}\n\n{\"name\":\"Bob\"\n{\"name\":\"Carol\",\"age\":50,\"id\":99}\n",
+			batches:    []int{1, 1},
+			progress:   dto.JobProgress{Processed: 3, Valid: 2, Invalid: 1, Imported: 2},
+			errorLines: []int{3},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := newUserMocks(ctrl)
+			jobRepo := mock_repository.NewMockJobRepo(ctrl)
+
+			s := &userImport{
+				userValidator: NewUserValidator(m.repo),
+				userRepo:      m.repo,
+				transactor:    m.transactor,
+				auditRepo:     m.audit,
+				eventRecorder: m.events,
+				jobRepo:       jobRepo,
+				cfg:           UserImportConfig{BatchSize: 2, MaxErrors: 10},
+			}
+
+			var batches []int
+			nextID := uint(1)
+			m.repo.EXPECT().
+				CreateBatch(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, users []dto.User) ([]dto.User, error) {
+					batches = append(batches, len(users))
+					for i := range users {
+						users[i].ID = nextID
+						nextID++
+					}
+					return users, nil
+				}).
+				AnyTimes()
+			m.audit.EXPECT().
+				Append(gomock.Any(), auditOperation(dto.AuditOperationImport)).
+				Return(nil).
+				AnyTimes()
+			m.events.EXPECT().
+				Record(gomock.Any(), events.UserCreated, gomock.Any(), gomock.Any()).
+				Return(nil).
+				AnyTimes()
+
+			var errorLines []int
+			jobRepo.EXPECT().
+				AddErrors(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, jobErrors []dto.JobError) error {
+					for _, jobError := range jobErrors {
+						require.Equal(t, testJobID, jobError.JobID)
+						errorLines = append(errorLines, jobError.Line)
+					}
+					return nil
+				}).
+				AnyTimes()
+
+			var progress dto.JobProgress
+			jobRepo.EXPECT().
+				UpdateProgress(gomock.Any(), testJobID, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ uint64, p dto.JobProgress) error {
+					progress = p
+					return nil
+				}).
+				AnyTimes()
+
+			err := s.process(t.Context(), testJobID, strings.NewReader(tc.upload), tc.format, tc.dryRun)
+
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.batches, batches)
+			require.Equal(t, tc.errorLines, errorLines)
+
+			tc.progress.BytesRead = int64(len(tc.upload))
+			require.Equal(t, tc.progress, progress)
+		})
+	}
+}
+
+func TestUserImport_Start(t *testing.T) {
+	type testCase struct {
+		name          string
+		maxUploadSize int64
+		upload        string
+		format        string
+		wantJob       bool
+		wantError     error
+	}
+
+	cases := []testCase{
+		{name: "within the limit", maxUploadSize: 14, upload: "name,age\n", format: ImportFormatCSV, wantJob: true},
+		{name: "at the limit", maxUploadSize: 14, upload: "name,age\nAl,1\n", format: ImportFormatCSV, wantJob: true},
+		{name: "over the limit", maxUploadSize: 14, upload: "name,age\nAl,10\n", format: ImportFormatCSV, wantError: ErrUploadTooLarge},
+		{
+			name:          "no limit by default",
+			maxUploadSize: DefaultUserImportConfig().MaxUploadSize,
+			upload:        "name,age\n" + strings.Repeat("Al,10\n", 100_000),
+			format:        ImportFormatCSV,
+			wantJob:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jobRepo := mock_repository.NewMockJobRepo(ctrl)
+			spoolDir := t.TempDir()
+			now := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+
+			s := &userImport{
+				jobRepo: jobRepo,
+				runner:  jobs.NewRunner(1, 1),
+				cfg:     UserImportConfig{SpoolDir: spoolDir, MaxUploadSize: tc.maxUploadSize, Owner: "node-1"},
+				now:     func() time.Time { return now },
+			}
+
+			if tc.wantJob {
+				jobRepo.EXPECT().
+					Create(gomock.Any(), &dto.Job{
+						Kind:       dto.JobKindUserImport,
+						Status:     dto.JobStatusPending,
+						CreatedBy:  actorFrom(t.Context()),
+						BytesTotal: int64(len(tc.upload)),
+						Owner:      "node-1",
+						RenewedAt:  &now,
+					}).
+					DoAndReturn(func(_ context.Context, job *dto.Job) (*dto.Job, error) {
+						job.ID = testJobID
+						return job, nil
+					})
+			}
+
+			job, err := s.Start(t.Context(), strings.NewReader(tc.upload), tc.format, false)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				// A rejected upload leaves nothing behind.
+				spooled, err := os.ReadDir(spoolDir)
+				require.NoError(t, err)
+				require.Empty(t, spooled)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testJobID, job.ID)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/metrics"
+)
+
+const userEntity string = "user"
+
+var errExportAborted = errors.New("export aborted")
+
+type userWithMetrics struct {
+	next User
+}
+
+func NewUserWithMetrics(next User) User {
+	return &userWithMetrics{next: next}
+}
+
+func (s *userWithMetrics) List(ctx context.Context) ([]dto.User, error) {
+	users, err := s.next.List(ctx)
+	observeOperation("list", err)
+
+	return users, err
+}
+
+// Export observes the operation once the sequence has been consumed. A
+// consumer that panics out of the loop, as the HTTP handler does to abort a
+// response it cannot finish, is observed as an error.
+func (s *userWithMetrics) Export(ctx context.Context) iter.Seq2[dto.User, error] {
+	return func(yield func(dto.User, error) bool) {
+		var exportErr error
+		completed := false
+		defer func() {
+			if !completed && exportErr == nil {
+				exportErr = errExportAborted
+			}
+			observeOperation("export", exportErr)
+		}()
+
+		for user, err := range s.next.Export(ctx) {
+			if err != nil {
+				exportErr = err
+			}
+			if !yield(user, err) {
+				break
+			}
+		}
+		completed = true
+	}
+}
+
+func (s *userWithMetrics) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	users, err := s.next.ListAsOf(ctx, at)
+	observeOperation("list_as_of", err)
+
+	return users, err
+}
+
+func (s *userWithMetrics) LastModified(ctx context.Context) (time.Time, error) {
+	lastModified, err := s.next.LastModified(ctx)
+	observeOperation("last_modified", err)
+
+	return lastModified, err
+}
+
+func (s *userWithMetrics) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	users, total, err := s.next.Find(ctx, query)
+	observeOperation("find", err)
+
+	return users, total, err
+}
+
+func (s *userWithMetrics) Get(ctx context.Context, id uint) (*dto.User, error) {
+	user, err := s.next.Get(ctx, id)
+	observeOperation("get", err)
+
+	return user, err
+}
+
+func (s *userWithMetrics) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	user, err := s.next.GetAsOf(ctx, id, at)
+	observeOperation("get_as_of", err)
+
+	return user, err
+}
+
+func (s *userWithMetrics) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	user, err := s.next.Create(ctx, user)
+	observeOperation("create", err)
+
+	return user, err
+}
+
+func (s *userWithMetrics) Update(ctx context.Context, user *dto.User, id uint) error {
+	err := s.next.Update(ctx, user, id)
+	observeOperation("update", err)
+
+	return err
+}
+
+func (s *userWithMetrics) Delete(ctx context.Context, id uint) error {
+	err := s.next.Delete(ctx, id)
+	observeOperation("delete", err)
+
+	return err
+}
+
+func (s *userWithMetrics) Restore(ctx context.Context, id uint) error {
+	err := s.next.Restore(ctx, id)
+	observeOperation("restore", err)
+
+	return err
+}
+
+func (s *userWithMetrics) History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error) {
+	history, err := s.next.History(ctx, id, page)
+	observeOperation("history", err)
+
+	return history, err
+}
+
+func (s *userWithMetrics) Revert(ctx context.Context, id uint, at time.Time) error {
+	err := s.next.Revert(ctx, id, at)
+	observeOperation("revert", err)
+
+	return err
+}
+
+func (s *userWithMetrics) Purge(ctx context.Context, id uint) error {
+	err := s.next.Purge(ctx, id)
+	observeOperation("purge", err)
+
+	return err
+}
+
+type userValidatorWithMetrics struct {
+	next UserValidator
+}
+
+func NewUserValidatorWithMetrics(next UserValidator) UserValidator {
+	return &userValidatorWithMetrics{next: next}
+}
+
+func (v *userValidatorWithMetrics) Create(ctx context.Context, user *dto.User) error {
+	err := v.next.Create(ctx, user)
+	observeValidation("create", err)
+
+	return err
+}
+
+func (v *userValidatorWithMetrics) Update(ctx context.Context, user *dto.User, id uint) error {
+	err := v.next.Update(ctx, user, id)
+	observeValidation("update", err)
+
+	return err
+}
+
+func (v *userValidatorWithMetrics) Delete(ctx context.Context, id uint) error {
+	err := v.next.Delete(ctx, id)
+	observeValidation("delete", err)
+
+	return err
+}
+
+func (v *userValidatorWithMetrics) Restore(ctx context.Context, id uint) error {
+	err := v.next.Restore(ctx, id)
+	observeValidation("restore", err)
+
+	return err
+}
+
+func observeOperation(operation string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	metrics.ServiceOperationsTotal.WithLabelValues(userEntity, operation, result).Inc()
+}
+
+func observeValidation(operation string, err error) {
+	if err == nil {
+		return
+	}
+
+	rule := "internal"
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		rule = validationErr.Rule
+	}
+
+	metrics.ValidationFailuresTotal.WithLabelValues(userEntity, operation, rule).Inc()
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/metrics"
+	mock_repository "crud_app/repository/mocks_repository"
+	mock_service "crud_app/service/mocks_service"
+)
+
+func TestUserValidatorWithMetrics(t *testing.T) {
+	type testCase struct {
+		name      string
+		input     *dto.User
+		operation string
+		rule      string
+	}
+
+	cases := []testCase{
+		{name: "name is required", input: testUserNameEmpty, operation: "create", rule: "name_required"},
+		{name: "name too long", input: testUserNameLong, operation: "create", rule: "name_max_length"},
+		{name: "age must be positive", input: testUserAgeNeg, operation: "create", rule: "age_positive"},
+		{name: "user object nil", input: nil, operation: "create", rule: "user_not_nil"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockUserRepo(ctrl)
+			validator := NewUserValidatorWithMetrics(NewUserValidator(mockRepo))
+			counter := metrics.ValidationFailuresTotal.WithLabelValues(userEntity, tc.operation, tc.rule)
+			before := testutil.ToFloat64(counter)
+
+			err := validator.Create(context.Background(), tc.input)
+
+			require.Error(t, err)
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestUserValidatorWithMetrics_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepo(ctrl)
+	mockRepo.EXPECT().
+		Exists(gomock.Any(), id).
+		Return(false, nil)
+
+	validator := NewUserValidatorWithMetrics(NewUserValidator(mockRepo))
+	counter := metrics.ValidationFailuresTotal.WithLabelValues(userEntity, "delete", "user_exists")
+	before := testutil.ToFloat64(counter)
+
+	err := validator.Delete(context.Background(), id)
+
+	require.Error(t, err)
+	require.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestUserWithMetrics_Export(t *testing.T) {
+	type testCase struct {
+		name    string
+		records []error
+		// consume ranges over the export the way a caller would.
+		consume    func(seq iter.Seq2[dto.User, error])
+		wantResult string
+	}
+
+	drain := func(seq iter.Seq2[dto.User, error]) {
+		for range seq {
+		}
+	}
+
+	cases := []testCase{
+		{name: "consumed", records: []error{nil, nil}, consume: drain, wantResult: "ok"},
+		{name: "failed", records: []error{nil, errRepo}, consume: drain, wantResult: "error"},
+		{
+			name:    "aborted by the consumer",
+			records: []error{nil, nil},
+			consume: func(seq iter.Seq2[dto.User, error]) {
+				defer func() { require.Equal(t, http.ErrAbortHandler, recover()) }()
+				for range seq {
+					panic(http.ErrAbortHandler)
+				}
+			},
+			wantResult: "error",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			next := mock_service.NewMockUser(ctrl)
+			next.EXPECT().Export(gomock.Any()).
+				Return(func(yield func(dto.User, error) bool) {
+					for i, err := range tc.records {
+						if !yield(dto.User{ID: uint(i + 1)}, err) {
+							return
+						}
+					}
+				})
+			counter := metrics.ServiceOperationsTotal.WithLabelValues(userEntity, "export", tc.wantResult)
+			before := testutil.ToFloat64(counter)
+
+			tc.consume(NewUserWithMetrics(next).Export(context.Background()))
+
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
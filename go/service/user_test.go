@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 
 	"crud_app/dto"
+	"crud_app/events"
+	mock_events "crud_app/events/mocks_events"
+	"crud_app/repository"
 	mock_repository "crud_app/repository/mocks_repository"
 	mock_service "crud_app/service/mocks_service"
 )
@@ -30,6 +36,12 @@ var (
 		Name: "John",
 		Age:  10,
 	}
+	testDeletedUser = &dto.User{
+		ID:        1,
+		Name:      "John",
+		Age:       10,
+		DeletedAt: gorm.DeletedAt{Time: time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC), Valid: true},
+	}
 	testUserNameEmpty = &dto.User{
 		Name: "",
 		Age:  10,
@@ -63,10 +75,65 @@ var (
 	errUserNotFound = errors.New("user with ID not found")
 )
 
+type userMocks struct {
+	validator  *mock_service.MockUserValidator
+	repo       *mock_repository.MockUserRepo
+	transactor *mock_repository.MockTransactor
+	audit      *mock_repository.MockUserAuditRepo
+	events     *mock_events.MockRecorder
+}
+
+func newUserMocks(ctrl *gomock.Controller) *userMocks {
+	m := &userMocks{
+		validator:  mock_service.NewMockUserValidator(ctrl),
+		repo:       mock_repository.NewMockUserRepo(ctrl),
+		transactor: mock_repository.NewMockTransactor(ctrl),
+		audit:      mock_repository.NewMockUserAuditRepo(ctrl),
+		events:     mock_events.NewMockRecorder(ctrl),
+	}
+
+	m.transactor.EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	return m
+}
+
+func (m *userMocks) newService() User {
+	return NewUser(m.validator, m.repo, m.transactor, m.audit, m.events)
+}
+
+func (m *userMocks) expectChange(operation, eventType string) {
+	m.audit.EXPECT().
+		Append(gomock.Any(), auditOperation(operation)).
+		Return(nil)
+	m.events.EXPECT().
+		Record(gomock.Any(), eventType, id, gomock.Any()).
+		Return(nil)
+}
+
+type auditOperationMatcher string
+
+func auditOperation(operation string) gomock.Matcher {
+	return auditOperationMatcher(operation)
+}
+
+func (o auditOperationMatcher) Matches(x any) bool {
+	entry, ok := x.(*dto.UserAudit)
+	return ok && entry.Operation == string(o)
+}
+
+func (o auditOperationMatcher) String() string {
+	return "is audit entry for " + string(o)
+}
+
 func TestUser_List(t *testing.T) {
 	type testCase struct {
 		name          string
-		setupMocks    func(*mock_repository.MockUserRepo)
+		setupMocks    func(*userMocks)
 		expectedUsers []dto.User
 		wantError     bool
 		expectedError error
@@ -75,9 +142,9 @@ func TestUser_List(t *testing.T) {
 	cases := []testCase{
 		{
 			name: "successful list with users",
-			setupMocks: func(mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedUsers := testUsers
-				mockRepo.EXPECT().
+				m.repo.EXPECT().
 					List(gomock.Any()).
 					Return(expectedUsers, nil)
 			},
@@ -86,9 +153,9 @@ func TestUser_List(t *testing.T) {
 			expectedError: nil,
 		}, {
 			name: "error repository list",
-			setupMocks: func(mr *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errRepo
-				mr.EXPECT().
+				m.repo.EXPECT().
 					List(gomock.Any()).
 					Return(nil, expectedError)
 			},
@@ -97,8 +164,8 @@ func TestUser_List(t *testing.T) {
 			expectedError: errRepo,
 		}, {
 			name: "empty result",
-			setupMocks: func(mr *mock_repository.MockUserRepo) {
-				mr.EXPECT().
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
 					List(gomock.Any()).
 					Return([]dto.User{}, nil)
 			},
@@ -113,12 +180,10 @@ func TestUser_List(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockValidator := mock_service.NewMockUserValidator(ctrl)
-			mockRepo := mock_repository.NewMockUserRepo(ctrl)
-
-			tc.setupMocks(mockRepo)
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
 
-			service := NewUser(mockValidator, mockRepo)
+			service := m.newService()
 			result, err := service.List(context.Background())
 
 			if tc.wantError {
@@ -137,7 +202,7 @@ func TestUser_Create(t *testing.T) {
 	type testCase struct {
 		name          string
 		input         *dto.User
-		setupMocks    func(*mock_service.MockUserValidator, *mock_repository.MockUserRepo)
+		setupMocks    func(*userMocks)
 		expectedUser  *dto.User
 		wantError     bool
 		expectedError error
@@ -147,14 +212,15 @@ func TestUser_Create(t *testing.T) {
 		{
 			name:  "successful creation",
 			input: testUser,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedUser := testUserWithID
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUser).
 					Return(nil)
-				mockRepo.EXPECT().
+				m.repo.EXPECT().
 					Create(gomock.Any(), testUser).
 					Return(expectedUser, nil)
+				m.expectChange(dto.AuditOperationCreate, events.UserCreated)
 			},
 			expectedUser:  testUserWithID,
 			wantError:     false,
@@ -162,12 +228,12 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error repository create",
 			input: testUser,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
-				mockValidator.EXPECT().
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUser).
 					Return(nil)
 				expectedError := errRepo
-				mockRepo.EXPECT().
+				m.repo.EXPECT().
 					Create(gomock.Any(), testUser).
 					Return(nil, expectedError)
 			},
@@ -177,9 +243,9 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error name is required",
 			input: testUserNameEmpty,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errNameEmpty
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUserNameEmpty).
 					Return(expectedError)
 			},
@@ -189,9 +255,9 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error name must be at least 2 characters long",
 			input: testUserNameShort,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errNameTooShort
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUserNameShort).
 					Return(expectedError)
 			},
@@ -201,9 +267,9 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error name cannot exceed 100 characters",
 			input: testUserNameLong,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errNameTooLong
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUserNameLong).
 					Return(expectedError)
 			},
@@ -213,9 +279,9 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error age must be positive",
 			input: testUserAgeNeg,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errAgeNeg
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUserAgeNeg).
 					Return(expectedError)
 			},
@@ -225,9 +291,9 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error age seems unrealistic",
 			input: testUserAgeUnreal,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errAgeUnreal
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), testUserAgeUnreal).
 					Return(expectedError)
 			},
@@ -237,9 +303,9 @@ func TestUser_Create(t *testing.T) {
 		}, {
 			name:  "error user object cannot be nil",
 			input: nil,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errUserNil
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Create(gomock.Any(), nil).
 					Return(expectedError)
 			},
@@ -254,12 +320,10 @@ func TestUser_Create(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockValidator := mock_service.NewMockUserValidator(ctrl)
-			mockRepo := mock_repository.NewMockUserRepo(ctrl)
-
-			tc.setupMocks(mockValidator, mockRepo)
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
 
-			service := NewUser(mockValidator, mockRepo)
+			service := m.newService()
 			result, err := service.Create(context.Background(), tc.input)
 
 			if tc.wantError {
@@ -279,7 +343,7 @@ func TestUser_Update(t *testing.T) {
 		name          string
 		user          *dto.User
 		id            uint
-		setupMocks    func(*mock_service.MockUserValidator, *mock_repository.MockUserRepo)
+		setupMocks    func(*userMocks)
 		wantError     bool
 		expectedError error
 	}
@@ -289,13 +353,22 @@ func TestUser_Update(t *testing.T) {
 			name: "successful updation",
 			user: testUser,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
-				mockValidator.EXPECT().
-					Update(gomock.Any(), testUser, id).
-					Return(nil)
-				mockRepo.EXPECT().
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUser, id).
 					Return(nil)
+				gomock.InOrder(
+					m.repo.EXPECT().
+						Get(gomock.Any(), id).
+						Return(testUserWithID, nil),
+					m.repo.EXPECT().
+						Update(gomock.Any(), testUser, id).
+						Return(nil),
+					m.repo.EXPECT().
+						Get(gomock.Any(), id).
+						Return(testUserWithID, nil),
+				)
+				m.expectChange(dto.AuditOperationUpdate, events.UserUpdated)
 			},
 			wantError:     false,
 			expectedError: nil,
@@ -303,12 +376,15 @@ func TestUser_Update(t *testing.T) {
 			name: "error repository update",
 			user: testUser,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
-				mockValidator.EXPECT().
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUser, id).
 					Return(nil)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
 				expectedError := errRepo
-				mockRepo.EXPECT().
+				m.repo.EXPECT().
 					Update(gomock.Any(), testUser, id).
 					Return(expectedError)
 			},
@@ -318,9 +394,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error name is required",
 			user: testUserNameEmpty,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errNameEmpty
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUserNameEmpty, id).
 					Return(expectedError)
 			},
@@ -330,9 +406,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error name must be at least 2 characters long",
 			user: testUserNameShort,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errNameTooShort
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUserNameShort, id).
 					Return(expectedError)
 			},
@@ -342,9 +418,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error name cannot exceed 100 characters",
 			user: testUserNameLong,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errNameTooLong
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUserNameLong, id).
 					Return(expectedError)
 			},
@@ -354,9 +430,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error age must be positive",
 			user: testUserAgeNeg,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errAgeNeg
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUserAgeNeg, id).
 					Return(expectedError)
 			},
@@ -366,9 +442,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error age seems unrealistic",
 			user: testUserAgeUnreal,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errAgeUnreal
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUserAgeUnreal, id).
 					Return(expectedError)
 			},
@@ -378,9 +454,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error user object cannot be nil",
 			user: nil,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errUserNil
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), nil, id).
 					Return(expectedError)
 			},
@@ -390,9 +466,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error repository exists",
 			user: testUser,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errRepo
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUser, id).
 					Return(expectedError)
 			},
@@ -402,9 +478,9 @@ func TestUser_Update(t *testing.T) {
 			name: "error user with ID not found",
 			user: testUser,
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errUserNotFound
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Update(gomock.Any(), testUser, id).
 					Return(expectedError)
 			},
@@ -418,12 +494,10 @@ func TestUser_Update(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockValidator := mock_service.NewMockUserValidator(ctrl)
-			mockRepo := mock_repository.NewMockUserRepo(ctrl)
-
-			tc.setupMocks(mockValidator, mockRepo)
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
 
-			service := NewUser(mockValidator, mockRepo)
+			service := m.newService()
 			err := service.Update(context.Background(), tc.user, tc.id)
 
 			if tc.wantError {
@@ -440,7 +514,7 @@ func TestUser_Delete(t *testing.T) {
 	type testCase struct {
 		name          string
 		id            uint
-		setupMocks    func(*mock_service.MockUserValidator, *mock_repository.MockUserRepo)
+		setupMocks    func(*userMocks)
 		wantError     bool
 		expectedError error
 	}
@@ -449,25 +523,35 @@ func TestUser_Delete(t *testing.T) {
 		{
 			name: "successful deletion",
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
-				mockValidator.EXPECT().
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
 					Delete(gomock.Any(), id).
 					Return(nil)
-				mockRepo.EXPECT().
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
+				m.repo.EXPECT().
 					Delete(gomock.Any(), id).
 					Return(nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.expectChange(dto.AuditOperationDelete, events.UserDeleted)
 			},
 			wantError:     false,
 			expectedError: nil,
 		}, {
 			name: "error repository delete",
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
-				mockValidator.EXPECT().
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
 					Delete(gomock.Any(), id).
 					Return(nil)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
 				expectedError := errRepo
-				mockRepo.EXPECT().
+				m.repo.EXPECT().
 					Delete(gomock.Any(), id).
 					Return(expectedError)
 			},
@@ -476,9 +560,9 @@ func TestUser_Delete(t *testing.T) {
 		}, {
 			name: "error repository exists",
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errRepo
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Delete(gomock.Any(), id).
 					Return(expectedError)
 			},
@@ -487,9 +571,9 @@ func TestUser_Delete(t *testing.T) {
 		}, {
 			name: "error user with ID not found",
 			id:   id,
-			setupMocks: func(mockValidator *mock_service.MockUserValidator, mockRepo *mock_repository.MockUserRepo) {
+			setupMocks: func(m *userMocks) {
 				expectedError := errUserNotFound
-				mockValidator.EXPECT().
+				m.validator.EXPECT().
 					Delete(gomock.Any(), id).
 					Return(expectedError)
 			},
@@ -503,12 +587,10 @@ func TestUser_Delete(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockValidator := mock_service.NewMockUserValidator(ctrl)
-			mockRepo := mock_repository.NewMockUserRepo(ctrl)
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
 
-			tc.setupMocks(mockValidator, mockRepo)
-
-			service := NewUser(mockValidator, mockRepo)
+			service := m.newService()
 			err := service.Delete(context.Background(), tc.id)
 
 			if tc.wantError {
@@ -520,3 +602,374 @@ func TestUser_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_Restore(t *testing.T) {
+	type testCase struct {
+		name          string
+		id            uint
+		setupMocks    func(*userMocks)
+		wantError     bool
+		expectedError error
+	}
+
+	cases := []testCase{
+		{
+			name: "successful restoration",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.repo.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
+				m.expectChange(dto.AuditOperationRestore, events.UserUpdated)
+			},
+			wantError:     false,
+			expectedError: nil,
+		}, {
+			name: "error repository restore",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.repo.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(errRepo)
+			},
+			wantError:     true,
+			expectedError: errRepo,
+		}, {
+			name: "error audit append",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.repo.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
+				m.audit.EXPECT().
+					Append(gomock.Any(), gomock.Any()).
+					Return(errRepo)
+			},
+			wantError:     true,
+			expectedError: errRepo,
+		}, {
+			name: "error deleted user not found",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.validator.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(errUserNotFound)
+			},
+			wantError:     true,
+			expectedError: errUserNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
+
+			service := m.newService()
+			err := service.Restore(context.Background(), tc.id)
+
+			if tc.wantError {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedError, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUser_Purge(t *testing.T) {
+	type testCase struct {
+		name          string
+		id            uint
+		setupMocks    func(*userMocks)
+		expectedError error
+	}
+
+	cases := []testCase{
+		{
+			name: "purges live user",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(nil, repository.ErrNotFound)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
+				m.repo.EXPECT().
+					Purge(gomock.Any(), id).
+					Return(nil)
+				m.expectChange(dto.AuditOperationPurge, events.UserPurged)
+			},
+		}, {
+			name: "purges deleted user",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.repo.EXPECT().
+					Purge(gomock.Any(), id).
+					Return(nil)
+				m.expectChange(dto.AuditOperationPurge, events.UserPurged)
+			},
+		}, {
+			name: "error user not found",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(nil, repository.ErrNotFound)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(nil, repository.ErrNotFound)
+			},
+			expectedError: repository.ErrNotFound,
+		}, {
+			name: "error repository purge",
+			id:   id,
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.repo.EXPECT().
+					Purge(gomock.Any(), id).
+					Return(errRepo)
+			},
+			expectedError: errRepo,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
+
+			err := m.newService().Purge(context.Background(), tc.id)
+
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestUser_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	page := dto.PageRequest{Limit: 2, Offset: 4}
+	entries := []dto.UserAudit{
+		{ID: 6, UserID: id, Operation: dto.AuditOperationUpdate},
+		{ID: 5, UserID: id, Operation: dto.AuditOperationCreate},
+	}
+
+	m := newUserMocks(ctrl)
+	m.audit.EXPECT().
+		ListByUser(gomock.Any(), id, page).
+		Return(entries, int64(6), nil)
+
+	result, err := m.newService().History(context.Background(), id, page)
+
+	require.NoError(t, err)
+	require.Equal(t, &dto.Page[dto.UserAudit]{Items: entries, Total: 6, Limit: 2, Offset: 4}, result)
+}
+
+func TestUserDiff(t *testing.T) {
+	type testCase struct {
+		name         string
+		before       *dto.User
+		after        *dto.User
+		expectedDiff map[string]fieldChange
+	}
+
+	cases := []testCase{
+		{
+			name:   "create",
+			before: nil,
+			after:  testUserWithID,
+			expectedDiff: map[string]fieldChange{
+				"name": {Old: nil, New: "John"},
+				"age":  {Old: nil, New: float64(10)},
+			},
+		}, {
+			name:   "update age",
+			before: testUserWithID,
+			after:  &dto.User{ID: 1, Name: "John", Age: 11},
+			expectedDiff: map[string]fieldChange{
+				"age": {Old: float64(10), New: float64(11)},
+			},
+		}, {
+			name:   "delete",
+			before: testUserWithID,
+			after:  testDeletedUser,
+			expectedDiff: map[string]fieldChange{
+				"deleted_at": {Old: nil, New: "2025-09-17T12:00:00Z"},
+			},
+		}, {
+			name:         "no changes",
+			before:       testUserWithID,
+			after:        testUserWithID,
+			expectedDiff: map[string]fieldChange{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := userDiff(tc.before, tc.after)
+			require.NoError(t, err)
+
+			var diff map[string]fieldChange
+			require.NoError(t, json.Unmarshal(raw, &diff))
+			require.Equal(t, tc.expectedDiff, diff)
+		})
+	}
+}
+
+func TestUser_Revert(t *testing.T) {
+	type testCase struct {
+		name          string
+		setupMocks    func(*userMocks)
+		wantError     bool
+		expectedError error
+	}
+
+	at := time.Date(2025, 9, 16, 0, 0, 0, 0, time.UTC)
+	oldVersion := &dto.User{ID: 1, Name: "Johnny", Age: 9}
+	reverted := &dto.User{Name: "Johnny", Age: 9}
+
+	cases := []testCase{
+		{
+			name: "revert live user",
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetAsOf(gomock.Any(), id, at).
+					Return(oldVersion, nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(nil, repository.ErrNotFound)
+				gomock.InOrder(
+					m.repo.EXPECT().
+						Get(gomock.Any(), id).
+						Return(testUserWithID, nil),
+					m.repo.EXPECT().
+						Get(gomock.Any(), id).
+						Return(oldVersion, nil),
+				)
+				m.validator.EXPECT().
+					Update(gomock.Any(), reverted, id).
+					Return(nil)
+				m.repo.EXPECT().
+					Update(gomock.Any(), reverted, id).
+					Return(nil)
+				m.expectChange(dto.AuditOperationRevert, events.UserUpdated)
+			},
+			wantError:     false,
+			expectedError: nil,
+		}, {
+			name: "revert deleted user",
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetAsOf(gomock.Any(), id, at).
+					Return(oldVersion, nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(testDeletedUser, nil)
+				m.validator.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.repo.EXPECT().
+					Restore(gomock.Any(), id).
+					Return(nil)
+				m.validator.EXPECT().
+					Update(gomock.Any(), reverted, id).
+					Return(nil)
+				m.repo.EXPECT().
+					Update(gomock.Any(), reverted, id).
+					Return(nil)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(oldVersion, nil)
+				m.expectChange(dto.AuditOperationRevert, events.UserUpdated)
+			},
+			wantError:     false,
+			expectedError: nil,
+		}, {
+			name: "error no version at instant",
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetAsOf(gomock.Any(), id, at).
+					Return(nil, repository.ErrNotFound)
+			},
+			wantError:     true,
+			expectedError: errors.New("user with ID 1 did not exist at 2025-09-16T00:00:00Z"),
+		}, {
+			name: "error validator rejects reverted data",
+			setupMocks: func(m *userMocks) {
+				m.repo.EXPECT().
+					GetAsOf(gomock.Any(), id, at).
+					Return(oldVersion, nil)
+				m.repo.EXPECT().
+					GetDeleted(gomock.Any(), id).
+					Return(nil, repository.ErrNotFound)
+				m.repo.EXPECT().
+					Get(gomock.Any(), id).
+					Return(testUserWithID, nil)
+				m.validator.EXPECT().
+					Update(gomock.Any(), reverted, id).
+					Return(errAgeUnreal)
+			},
+			wantError:     true,
+			expectedError: errAgeUnreal,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := newUserMocks(ctrl)
+			tc.setupMocks(m)
+
+			service := m.newService()
+			err := service.Revert(context.Background(), id, at)
+
+			if tc.wantError {
+				require.Error(t, err)
+				require.EqualError(t, err, tc.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
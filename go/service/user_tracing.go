@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"crud_app/dto"
+)
+
+var tracer = otel.Tracer("crud_app/service")
+
+type userWithTracing struct {
+	next User
+}
+
+func NewUserWithTracing(next User) User {
+	return &userWithTracing{next: next}
+}
+
+func (s *userWithTracing) List(ctx context.Context) ([]dto.User, error) {
+	ctx, span := tracer.Start(ctx, "User.List")
+	defer span.End()
+
+	users, err := s.next.List(ctx)
+	span.SetAttributes(attribute.Int("user.count", len(users)))
+	recordSpanError(span, err)
+
+	return users, err
+}
+
+// Export's span covers the whole iteration rather than the call.
+func (s *userWithTracing) Export(ctx context.Context) iter.Seq2[dto.User, error] {
+	return func(yield func(dto.User, error) bool) {
+		ctx, span := tracer.Start(ctx, "User.Export")
+		defer span.End()
+
+		count := 0
+		for user, err := range s.next.Export(ctx) {
+			recordSpanError(span, err)
+			if err == nil {
+				count++
+			}
+			if !yield(user, err) {
+				break
+			}
+		}
+		span.SetAttributes(attribute.Int("user.count", count))
+	}
+}
+
+func (s *userWithTracing) ListAsOf(ctx context.Context, at time.Time) ([]dto.User, error) {
+	ctx, span := tracer.Start(ctx, "User.ListAsOf", trace.WithAttributes(attribute.String("as_of", at.Format(time.RFC3339))))
+	defer span.End()
+
+	users, err := s.next.ListAsOf(ctx, at)
+	span.SetAttributes(attribute.Int("user.count", len(users)))
+	recordSpanError(span, err)
+
+	return users, err
+}
+
+func (s *userWithTracing) LastModified(ctx context.Context) (time.Time, error) {
+	ctx, span := tracer.Start(ctx, "User.LastModified")
+	defer span.End()
+
+	lastModified, err := s.next.LastModified(ctx)
+	recordSpanError(span, err)
+
+	return lastModified, err
+}
+
+func (s *userWithTracing) Find(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+	ctx, span := tracer.Start(ctx, "User.Find", trace.WithAttributes(
+		attribute.Int("page.limit", query.Limit),
+		attribute.Int("page.offset", query.Offset),
+		attribute.Int("filter.count", len(query.Filters)),
+	))
+	defer span.End()
+
+	users, total, err := s.next.Find(ctx, query)
+	span.SetAttributes(attribute.Int("user.count", len(users)), attribute.Int64("user.total", total))
+	recordSpanError(span, err)
+
+	return users, total, err
+}
+
+func (s *userWithTracing) Get(ctx context.Context, id uint) (*dto.User, error) {
+	ctx, span := tracer.Start(ctx, "User.Get", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	user, err := s.next.Get(ctx, id)
+	recordSpanError(span, err)
+
+	return user, err
+}
+
+func (s *userWithTracing) GetAsOf(ctx context.Context, id uint, at time.Time) (*dto.User, error) {
+	ctx, span := tracer.Start(ctx, "User.GetAsOf", trace.WithAttributes(
+		attribute.Int64("user.id", int64(id)),
+		attribute.String("as_of", at.Format(time.RFC3339)),
+	))
+	defer span.End()
+
+	user, err := s.next.GetAsOf(ctx, id, at)
+	recordSpanError(span, err)
+
+	return user, err
+}
+
+func (s *userWithTracing) Create(ctx context.Context, user *dto.User) (*dto.User, error) {
+	ctx, span := tracer.Start(ctx, "User.Create")
+	defer span.End()
+
+	user, err := s.next.Create(ctx, user)
+	if user != nil {
+		span.SetAttributes(attribute.Int64("user.id", int64(user.ID)))
+	}
+	recordSpanError(span, err)
+
+	return user, err
+}
+
+func (s *userWithTracing) Update(ctx context.Context, user *dto.User, id uint) error {
+	ctx, span := tracer.Start(ctx, "User.Update", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := s.next.Update(ctx, user, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (s *userWithTracing) Delete(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "User.Delete", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := s.next.Delete(ctx, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (s *userWithTracing) Restore(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "User.Restore", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := s.next.Restore(ctx, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (s *userWithTracing) History(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.UserAudit], error) {
+	ctx, span := tracer.Start(ctx, "User.History", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	history, err := s.next.History(ctx, id, page)
+	recordSpanError(span, err)
+
+	return history, err
+}
+
+func (s *userWithTracing) Revert(ctx context.Context, id uint, at time.Time) error {
+	ctx, span := tracer.Start(ctx, "User.Revert", trace.WithAttributes(
+		attribute.Int64("user.id", int64(id)),
+		attribute.String("as_of", at.Format(time.RFC3339)),
+	))
+	defer span.End()
+
+	err := s.next.Revert(ctx, id, at)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (s *userWithTracing) Purge(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "User.Purge", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := s.next.Purge(ctx, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+type userValidatorWithTracing struct {
+	next UserValidator
+}
+
+func NewUserValidatorWithTracing(next UserValidator) UserValidator {
+	return &userValidatorWithTracing{next: next}
+}
+
+func (v *userValidatorWithTracing) Create(ctx context.Context, user *dto.User) error {
+	ctx, span := tracer.Start(ctx, "UserValidator.Create")
+	defer span.End()
+
+	err := v.next.Create(ctx, user)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (v *userValidatorWithTracing) Update(ctx context.Context, user *dto.User, id uint) error {
+	ctx, span := tracer.Start(ctx, "UserValidator.Update", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := v.next.Update(ctx, user, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (v *userValidatorWithTracing) Delete(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "UserValidator.Delete", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := v.next.Delete(ctx, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func (v *userValidatorWithTracing) Restore(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "UserValidator.Restore", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := v.next.Restore(ctx, id)
+	recordSpanError(span, err)
+
+	return err
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		span.SetAttributes(attribute.String("validation.rule", validationErr.Rule))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
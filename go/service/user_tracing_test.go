@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	mock_service "crud_app/service/mocks_service"
+)
+
+// spanRecorder installs the recording provider once: the package tracer
+// only delegates to the first global provider that is set.
+var spanRecorder = sync.OnceValue(func() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	return recorder
+})
+
+// recordSpans returns the spans that ended while run ran.
+func recordSpans(t *testing.T, run func()) []sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	recorder := spanRecorder()
+	before := len(recorder.Ended())
+	run()
+
+	return recorder.Ended()[before:]
+}
+
+// requireInSpan checks that the decorator hands the next service its span.
+func requireInSpan(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	require.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+}
+
+func TestUserWithTracing(t *testing.T) {
+	validationErr := &ValidationError{Rule: "user_exists", Message: "user with ID not found"}
+
+	type testCase struct {
+		name      string
+		setup     func(t *testing.T, m *mock_service.MockUser)
+		call      func(s User, ctx context.Context) error
+		wantSpan  string
+		wantAttrs []attribute.KeyValue
+		wantErr   error
+	}
+
+	cases := []testCase{{
+		name: "list counts users",
+		setup: func(t *testing.T, m *mock_service.MockUser) {
+			m.EXPECT().List(gomock.Any()).
+				DoAndReturn(func(ctx context.Context) ([]dto.User, error) {
+					requireInSpan(t, ctx)
+					return testUsers, nil
+				})
+		},
+		call: func(s User, ctx context.Context) error {
+			_, err := s.List(ctx)
+			return err
+		},
+		wantSpan:  "User.List",
+		wantAttrs: []attribute.KeyValue{attribute.Int("user.count", 2)},
+	}, {
+		name: "find records the query and the total",
+		setup: func(t *testing.T, m *mock_service.MockUser) {
+			m.EXPECT().Find(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, query dto.ListQuery) ([]dto.User, int64, error) {
+					requireInSpan(t, ctx)
+					return testUsers[:1], 7, nil
+				})
+		},
+		call: func(s User, ctx context.Context) error {
+			_, _, err := s.Find(ctx, dto.ListQuery{PageRequest: dto.PageRequest{Limit: 1, Offset: 3}, Filters: map[string]string{"name": "John"}})
+			return err
+		},
+		wantSpan: "User.Find",
+		wantAttrs: []attribute.KeyValue{
+			attribute.Int("page.limit", 1),
+			attribute.Int("page.offset", 3),
+			attribute.Int("filter.count", 1),
+			attribute.Int("user.count", 1),
+			attribute.Int64("user.total", 7),
+		},
+	}, {
+		name: "create records the new id",
+		setup: func(t *testing.T, m *mock_service.MockUser) {
+			m.EXPECT().Create(gomock.Any(), testUser).
+				DoAndReturn(func(ctx context.Context, user *dto.User) (*dto.User, error) {
+					requireInSpan(t, ctx)
+					return testUserWithID, nil
+				})
+		},
+		call: func(s User, ctx context.Context) error {
+			_, err := s.Create(ctx, testUser)
+			return err
+		},
+		wantSpan:  "User.Create",
+		wantAttrs: []attribute.KeyValue{attribute.Int64("user.id", 1)},
+	}, {
+		name: "failed get sets the error status",
+		setup: func(t *testing.T, m *mock_service.MockUser) {
+			m.EXPECT().Get(gomock.Any(), id).Return(nil, errRepo)
+		},
+		call: func(s User, ctx context.Context) error {
+			_, err := s.Get(ctx, id)
+			return err
+		},
+		wantSpan:  "User.Get",
+		wantAttrs: []attribute.KeyValue{attribute.Int64("user.id", 1)},
+		wantErr:   errRepo,
+	}, {
+		name: "validation error records its rule",
+		setup: func(t *testing.T, m *mock_service.MockUser) {
+			m.EXPECT().Delete(gomock.Any(), id).Return(validationErr)
+		},
+		call: func(s User, ctx context.Context) error {
+			return s.Delete(ctx, id)
+		},
+		wantSpan: "User.Delete",
+		wantAttrs: []attribute.KeyValue{
+			attribute.Int64("user.id", 1),
+			attribute.String("validation.rule", "user_exists"),
+		},
+		wantErr: validationErr,
+	}, {
+		name: "export spans the whole iteration",
+		setup: func(t *testing.T, m *mock_service.MockUser) {
+			m.EXPECT().Export(gomock.Any()).
+				DoAndReturn(func(ctx context.Context) iter.Seq2[dto.User, error] {
+					requireInSpan(t, ctx)
+					return func(yield func(dto.User, error) bool) {
+						for _, user := range testUsers {
+							if !yield(user, nil) {
+								return
+							}
+						}
+					}
+				})
+		},
+		call: func(s User, ctx context.Context) error {
+			for _, err := range s.Export(ctx) {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		wantSpan:  "User.Export",
+		wantAttrs: []attribute.KeyValue{attribute.Int("user.count", 2)},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			next := mock_service.NewMockUser(ctrl)
+			tc.setup(t, next)
+			s := NewUserWithTracing(next)
+
+			var err error
+			spans := recordSpans(t, func() {
+				err = tc.call(s, context.Background())
+			})
+
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Len(t, spans, 1)
+			span := spans[0]
+			require.Equal(t, tc.wantSpan, span.Name())
+			require.Subset(t, span.Attributes(), tc.wantAttrs)
+
+			if tc.wantErr == nil {
+				require.Equal(t, codes.Unset, span.Status().Code)
+				require.Empty(t, span.Events())
+				return
+			}
+			require.Equal(t, codes.Error, span.Status().Code)
+			require.Equal(t, tc.wantErr.Error(), span.Status().Description)
+			require.Len(t, span.Events(), 1)
+			require.Equal(t, "exception", span.Events()[0].Name)
+		})
+	}
+}
+
+func TestUserValidatorWithTracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	next := mock_service.NewMockUserValidator(ctrl)
+	next.EXPECT().Update(gomock.Any(), testUser, id).Return(&ValidationError{Rule: "name_required", Message: "name is required"})
+	v := NewUserValidatorWithTracing(next)
+
+	spans := recordSpans(t, func() {
+		require.Error(t, v.Update(context.Background(), testUser, id))
+	})
+
+	require.Len(t, spans, 1)
+	require.Equal(t, "UserValidator.Update", spans[0].Name())
+	require.Subset(t, spans[0].Attributes(), []attribute.KeyValue{
+		attribute.Int64("user.id", 1),
+		attribute.String("validation.rule", "name_required"),
+	})
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+}
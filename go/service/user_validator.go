@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,9 +13,7 @@ import (
 //go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
 
 type UserValidator interface {
-	Create(ctx context.Context, user *dto.User) error
-	Update(ctx context.Context, user *dto.User, id uint) error
-	Delete(ctx context.Context, id uint) error
+	Validator[dto.User]
 }
 
 type userValidator struct {
@@ -52,9 +51,17 @@ func (v *userValidator) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+func (v *userValidator) Restore(ctx context.Context, id uint) error {
+	if err := v.validateUserDeleted(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (v *userValidator) validateNewUserData(user *dto.User) error {
 	if user == nil {
-		return fmt.Errorf("user object cannot be nil")
+		return newValidationError("user_not_nil", "user object cannot be nil")
 	}
 
 	if err := v.validateName(user.Name); err != nil {
@@ -72,15 +79,15 @@ func (v *userValidator) validateName(name string) error {
 	name = strings.TrimSpace(name)
 
 	if name == "" {
-		return fmt.Errorf("name is required")
+		return newValidationError("name_required", "name is required")
 	}
 
 	if len(name) < 2 {
-		return fmt.Errorf("name must be at least 2 characters long")
+		return newValidationError("name_min_length", "name must be at least 2 characters long")
 	}
 
 	if len(name) > 100 {
-		return fmt.Errorf("name cannot exceed 100 characters")
+		return newValidationError("name_max_length", "name cannot exceed 100 characters")
 	}
 
 	return nil
@@ -88,11 +95,11 @@ func (v *userValidator) validateName(name string) error {
 
 func (v *userValidator) validateAge(age int) error {
 	if age <= 0 {
-		return fmt.Errorf("age must be positive")
+		return newValidationError("age_positive", "age must be positive")
 	}
 
 	if age > 150 {
-		return fmt.Errorf("age seems unrealistic")
+		return newValidationError("age_realistic", "age seems unrealistic")
 	}
 
 	return nil
@@ -105,7 +112,19 @@ func (v *userValidator) validateUserExists(ctx context.Context, id uint) error {
 	}
 
 	if !exists {
-		return fmt.Errorf("user with ID %d not found", id)
+		return newNotFoundError("user_exists", fmt.Sprintf("user with ID %d not found", id))
+	}
+
+	return nil
+}
+
+func (v *userValidator) validateUserDeleted(ctx context.Context, id uint) error {
+	_, err := v.userRepo.GetDeleted(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return newNotFoundError("user_deleted", fmt.Sprintf("deleted user with ID %d not found", id))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check deleted user: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+type Webhook interface {
+	List(ctx context.Context) ([]dto.WebhookSubscription, error)
+	Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error)
+	Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error
+	Delete(ctx context.Context, id uint) error
+	Deliveries(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.WebhookDelivery], error)
+}
+
+type webhook struct {
+	webhookValidator WebhookValidator
+	subscriptionRepo repository.WebhookSubscriptionRepo
+	deliveryRepo     repository.WebhookDeliveryRepo
+}
+
+func NewWebhook(
+	webhookValidator WebhookValidator,
+	subscriptionRepo repository.WebhookSubscriptionRepo,
+	deliveryRepo repository.WebhookDeliveryRepo,
+) Webhook {
+	return &webhook{
+		webhookValidator: webhookValidator,
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+	}
+}
+
+func (s *webhook) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	subscriptions, err := s.subscriptionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+func (s *webhook) Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	subscription, err := s.subscriptionRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+
+	return subscription, nil
+}
+
+// Create returns the subscription with its secret; this is the only response
+// that carries it, so a generated secret must be stored by the caller.
+func (s *webhook) Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+	if err := s.webhookValidator.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	if subscription.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		subscription.Secret = secret
+	}
+	active := true
+	subscription.Active = &active
+
+	return s.subscriptionRepo.Create(ctx, subscription)
+}
+
+func (s *webhook) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	if err := s.webhookValidator.Update(ctx, subscription, id); err != nil {
+		return err
+	}
+
+	return s.subscriptionRepo.Update(ctx, subscription, id)
+}
+
+func (s *webhook) Delete(ctx context.Context, id uint) error {
+	if _, err := s.subscriptionRepo.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return s.subscriptionRepo.Delete(ctx, id)
+}
+
+func (s *webhook) Deliveries(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.WebhookDelivery], error) {
+	if _, err := s.subscriptionRepo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	items, total, err := s.deliveryRepo.ListBySubscription(ctx, id, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Page[dto.WebhookDelivery]{
+		Items:  items,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	"crud_app/auth"
+	"crud_app/dto"
+)
+
+type webhookWithAuthorization struct {
+	next   Webhook
+	policy *auth.Policy
+}
+
+func NewWebhookWithAuthorization(next Webhook, policy *auth.Policy) Webhook {
+	return &webhookWithAuthorization{next: next, policy: policy}
+}
+
+func (s *webhookWithAuthorization) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	if err := s.authorize(ctx, auth.PermissionWebhooksRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.List(ctx)
+}
+
+func (s *webhookWithAuthorization) Get(ctx context.Context, id uint) (*dto.WebhookSubscription, error) {
+	if err := s.authorize(ctx, auth.PermissionWebhooksRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.Get(ctx, id)
+}
+
+func (s *webhookWithAuthorization) Create(ctx context.Context, subscription *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+	if err := s.authorize(ctx, auth.PermissionWebhooksWrite); err != nil {
+		return nil, err
+	}
+
+	return s.next.Create(ctx, subscription)
+}
+
+func (s *webhookWithAuthorization) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	if err := s.authorize(ctx, auth.PermissionWebhooksWrite); err != nil {
+		return err
+	}
+
+	return s.next.Update(ctx, subscription, id)
+}
+
+func (s *webhookWithAuthorization) Delete(ctx context.Context, id uint) error {
+	if err := s.authorize(ctx, auth.PermissionWebhooksWrite); err != nil {
+		return err
+	}
+
+	return s.next.Delete(ctx, id)
+}
+
+func (s *webhookWithAuthorization) Deliveries(ctx context.Context, id uint, page dto.PageRequest) (*dto.Page[dto.WebhookDelivery], error) {
+	if err := s.authorize(ctx, auth.PermissionWebhooksRead); err != nil {
+		return nil, err
+	}
+
+	return s.next.Deliveries(ctx, id, page)
+}
+
+func (s *webhookWithAuthorization) authorize(ctx context.Context, permission string) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+
+	return s.policy.Authorize(principal, permission)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	mock_repository "crud_app/repository/mocks_repository"
+)
+
+// testHosts stands in for DNS, so the tests never leave the machine.
+var testHosts = map[string][]netip.Addr{
+	"example.com":      {netip.MustParseAddr("93.184.215.14")},
+	"internal.example": {netip.MustParseAddr("10.0.0.7")},
+	"rebound.example":  {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("127.0.0.1")},
+}
+
+func newTestWebhookValidator(subscriptionRepo repository.WebhookSubscriptionRepo) WebhookValidator {
+	return &webhookValidator{
+		subscriptionRepo: subscriptionRepo,
+		lookupHost: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			addrs, ok := testHosts[host]
+			if !ok {
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			}
+			return addrs, nil
+		},
+	}
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	type testCase struct {
+		name         string
+		subscription *dto.WebhookSubscription
+		wantRule     string
+		wantSecret   string
+	}
+
+	cases := []testCase{
+		{
+			name:         "secret is generated",
+			subscription: &dto.WebhookSubscription{URL: "https://example.com/hook"},
+		}, {
+			name:         "secret is kept",
+			subscription: &dto.WebhookSubscription{URL: "https://example.com/hook", Secret: "0123456789abcdef"},
+			wantSecret:   "0123456789abcdef",
+		}, {
+			name:         "relative url",
+			subscription: &dto.WebhookSubscription{URL: "/hook"},
+			wantRule:     "webhook_url",
+		}, {
+			name:         "loopback address",
+			subscription: &dto.WebhookSubscription{URL: "http://127.0.0.1:8080/hook"},
+			wantRule:     "webhook_url_address",
+		}, {
+			name:         "loopback ipv6 address",
+			subscription: &dto.WebhookSubscription{URL: "http://[::1]/hook"},
+			wantRule:     "webhook_url_address",
+		}, {
+			name:         "metadata endpoint",
+			subscription: &dto.WebhookSubscription{URL: "http://169.254.169.254/latest/meta-data/"},
+			wantRule:     "webhook_url_address",
+		}, {
+			name:         "host resolving to a private address",
+			subscription: &dto.WebhookSubscription{URL: "https://internal.example/hook"},
+			wantRule:     "webhook_url_address",
+		}, {
+			name:         "host resolving to a public and a loopback address",
+			subscription: &dto.WebhookSubscription{URL: "https://rebound.example/hook"},
+			wantRule:     "webhook_url_address",
+		}, {
+			name:         "unresolvable host",
+			subscription: &dto.WebhookSubscription{URL: "https://missing.example/hook"},
+			wantRule:     "webhook_url_host",
+		}, {
+			name:         "unknown event type",
+			subscription: &dto.WebhookSubscription{URL: "http://example.com", EventTypes: []string{"UserRenamed"}},
+			wantRule:     "webhook_event_type",
+		}, {
+			name:         "short secret",
+			subscription: &dto.WebhookSubscription{URL: "http://example.com", Secret: "short"},
+			wantRule:     "webhook_secret_length",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			subscriptionRepo := mock_repository.NewMockWebhookSubscriptionRepo(ctrl)
+			deliveryRepo := mock_repository.NewMockWebhookDeliveryRepo(ctrl)
+			service := NewWebhook(newTestWebhookValidator(subscriptionRepo), subscriptionRepo, deliveryRepo)
+
+			if tc.wantRule == "" {
+				subscriptionRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *dto.WebhookSubscription) (*dto.WebhookSubscription, error) {
+						return s, nil
+					})
+			}
+
+			created, err := service.Create(t.Context(), tc.subscription)
+			if tc.wantRule != "" {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				require.Equal(t, tc.wantRule, validationErr.Rule)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, *created.Active)
+			if tc.wantSecret != "" {
+				require.Equal(t, tc.wantSecret, created.Secret)
+			} else {
+				require.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+			}
+		})
+	}
+}
+
+func TestWebhookService_ReadsHideSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	subscriptionRepo := mock_repository.NewMockWebhookSubscriptionRepo(ctrl)
+	service := NewWebhook(newTestWebhookValidator(subscriptionRepo), subscriptionRepo, mock_repository.NewMockWebhookDeliveryRepo(ctrl))
+
+	subscriptionRepo.EXPECT().
+		List(gomock.Any()).
+		Return([]dto.WebhookSubscription{{ID: 1, Secret: "s1"}, {ID: 2, Secret: "s2"}}, nil)
+	subscriptionRepo.EXPECT().
+		Get(gomock.Any(), uint(1)).
+		Return(&dto.WebhookSubscription{ID: 1, Secret: "s1"}, nil)
+
+	subscriptions, err := service.List(t.Context())
+	require.NoError(t, err)
+	for _, s := range subscriptions {
+		require.Empty(t, s.Secret)
+	}
+
+	subscription, err := service.Get(t.Context(), 1)
+	require.NoError(t, err)
+	require.Empty(t, subscription.Secret)
+}
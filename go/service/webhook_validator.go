@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/repository"
+	"crud_app/webhooks"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mocks_$GOPACKAGE/mock_$GOFILE
+
+type WebhookValidator interface {
+	Create(ctx context.Context, subscription *dto.WebhookSubscription) error
+	Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error
+}
+
+type webhookValidator struct {
+	subscriptionRepo repository.WebhookSubscriptionRepo
+	lookupHost       func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+func NewWebhookValidator(subscriptionRepo repository.WebhookSubscriptionRepo) WebhookValidator {
+	return &webhookValidator{subscriptionRepo: subscriptionRepo, lookupHost: webhooks.LookupHost}
+}
+
+func (v *webhookValidator) Create(ctx context.Context, subscription *dto.WebhookSubscription) error {
+	if err := v.validateSubscriptionData(ctx, subscription); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (v *webhookValidator) Update(ctx context.Context, subscription *dto.WebhookSubscription, id uint) error {
+	if err := v.validateSubscriptionData(ctx, subscription); err != nil {
+		return err
+	}
+
+	if _, err := v.subscriptionRepo.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (v *webhookValidator) validateSubscriptionData(ctx context.Context, subscription *dto.WebhookSubscription) error {
+	if subscription == nil {
+		return newValidationError("webhook_not_nil", "webhook subscription cannot be nil")
+	}
+
+	u, err := url.Parse(subscription.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return newValidationError("webhook_url", "url must be an absolute http or https URL")
+	}
+	// The dispatcher checks the address again when it connects, since the
+	// host may resolve differently by then.
+	if err := webhooks.CheckHost(ctx, v.lookupHost, u.Hostname()); errors.Is(err, webhooks.ErrForbiddenAddress) {
+		return newValidationError("webhook_url_address", "url must not point to a loopback, private or link-local address")
+	} else if err != nil {
+		return newValidationError("webhook_url_host", fmt.Sprintf("url host %q cannot be resolved", u.Hostname()))
+	}
+
+	for _, eventType := range subscription.EventTypes {
+		if !slices.Contains(events.UserEventTypes, eventType) {
+			return newValidationError("webhook_event_type", fmt.Sprintf("unknown event type %q", eventType))
+		}
+	}
+
+	if subscription.Secret != "" && len(subscription.Secret) < 16 {
+		return newValidationError("webhook_secret_length", "secret must be at least 16 characters long")
+	}
+
+	return nil
+}
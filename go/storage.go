@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"crud_app/config"
+	"crud_app/metrics"
+	"crud_app/repository"
+	"crud_app/tracing"
+)
+
+const (
+	storageDatabase string = "database"
+	storageMemory   string = "memory"
+	// storagePostgres is what storageDatabase was called before sqlite was
+	// supported; it is still accepted so existing deployments keep starting.
+	storagePostgres string = "postgres"
+)
+
+type repositories struct {
+	transactor           repository.Transactor
+	users                repository.UserRepo
+	userAudit            repository.UserAuditRepo
+	outbox               repository.OutboxRepo
+	apiKeys              repository.APIKeyRepo
+	webhookSubscriptions repository.WebhookSubscriptionRepo
+	webhookDeliveries    repository.WebhookDeliveryRepo
+	jobs                 repository.JobRepo
+}
+
+func newRepositories(storage string) (*repositories, error) {
+	switch storage {
+	case storageDatabase, storagePostgres:
+		if storage == storagePostgres {
+			slog.Warn("--storage=postgres is deprecated, use --storage=database")
+		}
+
+		db, err := config.ConnectDB()
+		if err != nil {
+			return nil, fmt.Errorf("database connection failed: %w", err)
+		}
+
+		if err := metrics.InstrumentGORM(db, os.Getenv("DB_NAME")); err != nil {
+			return nil, fmt.Errorf("database instrumentation failed: %w", err)
+		}
+		if err := db.Use(tracing.NewGORMPlugin()); err != nil {
+			return nil, fmt.Errorf("database instrumentation failed: %w", err)
+		}
+
+		return &repositories{
+			transactor:           repository.NewTransactor(db),
+			users:                repository.NewUserRepo(db),
+			userAudit:            repository.NewUserAuditRepo(db),
+			outbox:               repository.NewOutboxRepo(db),
+			apiKeys:              repository.NewAPIKeyRepo(db),
+			webhookSubscriptions: repository.NewWebhookSubscriptionRepo(db),
+			webhookDeliveries:    repository.NewWebhookDeliveryRepo(db),
+			jobs:                 repository.NewJobRepo(db),
+		}, nil
+	case storageMemory:
+		slog.Warn("using in-memory storage: data is lost on exit and transactions do not roll back")
+
+		return &repositories{
+			transactor:           repository.NewMemoryTransactor(),
+			users:                repository.NewMemoryUserRepo(),
+			userAudit:            repository.NewMemoryUserAuditRepo(),
+			outbox:               repository.NewMemoryOutboxRepo(),
+			apiKeys:              repository.NewMemoryAPIKeyRepo(),
+			webhookSubscriptions: repository.NewMemoryWebhookSubscriptionRepo(),
+			webhookDeliveries:    repository.NewMemoryWebhookDeliveryRepo(),
+			jobs:                 repository.NewMemoryJobRepo(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q, want %s or %s", storage, storageDatabase, storageMemory)
+	}
+}
@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+var (
+	ErrRequired = errors.New("tenant is required")
+	ErrInvalid  = errors.New("tenant id is invalid")
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type ctxKey struct{}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func ID(ctx context.Context) (string, error) {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	if id == "" {
+		return "", ErrRequired
+	}
+
+	return id, nil
+}
+
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
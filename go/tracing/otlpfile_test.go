@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestOTLPFileExporter(t *testing.T) {
+	var buf bytes.Buffer
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewOTLPFileExporter(nopCloser{&buf})))
+	tracer := provider.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(attribute.Int64("user.id", 42))
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var req otlpRequest
+	require.NoError(t, json.Unmarshal(lines[0], &req))
+	require.Len(t, req.ResourceSpans, 1)
+	require.Len(t, req.ResourceSpans[0].ScopeSpans, 1)
+	require.Equal(t, "test", req.ResourceSpans[0].ScopeSpans[0].Scope.Name)
+
+	span := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	require.Equal(t, "child", span.Name)
+	require.Equal(t, parent.SpanContext().SpanID().String(), span.ParentSpanID)
+	require.Equal(t, parent.SpanContext().TraceID().String(), span.TraceID)
+	require.Equal(t, 2, span.Status.Code)
+	require.Equal(t, "user.id", span.Attributes[0].Key)
+	require.Equal(t, "42", *span.Attributes[0].Value.IntValue)
+	require.Len(t, span.Events, 1)
+	require.Equal(t, "exception", span.Events[0].Name)
+}
+
+func TestOTLPFileExporter_ShutdownClosesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exporter, err := newOTLPFileExporter(context.Background(), Config{Exporter: "otlp-file", FilePath: path})
+	require.NoError(t, err)
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	_, span := provider.Tracer("test").Start(context.Background(), "span")
+	span.End()
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	f := exporter.(*otlpFileExporter).w.(*os.File)
+	_, err = f.Write([]byte("x"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(written), `"name":"span"`)
+}
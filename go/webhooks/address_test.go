@@ -0,0 +1,117 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAddress(t *testing.T) {
+	type testCase struct {
+		addr    string
+		allowed bool
+	}
+
+	cases := []testCase{
+		{addr: "93.184.215.14", allowed: true},
+		{addr: "2606:4700:4700::1111", allowed: true},
+		{addr: "127.0.0.1"},
+		{addr: "127.8.8.8"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "fd00::1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "0.0.0.0"},
+		{addr: "::"},
+		{addr: "224.0.0.1"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "::ffff:169.254.169.254"},
+		{addr: "0.1.2.3"},
+		{addr: "100.64.0.1"},
+		{addr: "100.127.255.254"},
+		{addr: "100.128.0.1", allowed: true},
+		{addr: "192.0.0.8"},
+		{addr: "192.0.2.1"},
+		{addr: "198.18.0.1"},
+		{addr: "198.19.255.255"},
+		{addr: "198.20.0.1", allowed: true},
+		{addr: "203.0.113.7"},
+		{addr: "240.0.0.1"},
+		{addr: "255.255.255.255"},
+		{addr: "64:ff9b::a00:1"},
+		{addr: "64:ff9b::7f00:1"},
+		{addr: "64:ff9b:1::1"},
+		{addr: "::7f00:1"},
+		{addr: "2001::1"},
+		{addr: "2001:db8::1"},
+		{addr: "2002:a00:1::1"},
+		{addr: "fe80::1%eth0"},
+		{addr: "fec0::1"},
+		{addr: "ff02::1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.addr, func(t *testing.T) {
+			err := CheckAddress(netip.MustParseAddr(tc.addr))
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrForbiddenAddress)
+			}
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	errLookup := errors.New("lookup failed")
+	hosts := map[string][]netip.Addr{
+		"public.example":  {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("2606:4700:4700::1111")},
+		"private.example": {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.7")},
+	}
+	lookup := func(ctx context.Context, host string) ([]netip.Addr, error) {
+		addrs, ok := hosts[host]
+		if !ok {
+			return nil, errLookup
+		}
+		return addrs, nil
+	}
+
+	type testCase struct {
+		name    string
+		host    string
+		wantErr error
+	}
+
+	cases := []testCase{
+		{name: "public host", host: "public.example"},
+		{name: "one private address", host: "private.example", wantErr: ErrForbiddenAddress},
+		{name: "lookup failure", host: "missing.example", wantErr: errLookup},
+		{name: "public literal", host: "93.184.215.14"},
+		{name: "private literal is not looked up", host: "169.254.169.254", wantErr: ErrForbiddenAddress},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorIs(t, CheckHost(t.Context(), lookup, tc.host), tc.wantErr)
+		})
+	}
+}
+
+func TestNewClient_RefusesForbiddenAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the receiver")
+	}))
+	defer receiver.Close()
+
+	_, err := NewClient().Get(receiver.URL)
+
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+}
@@ -0,0 +1,187 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/repository"
+)
+
+type DispatcherConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	// MaxFailures consecutive failed attempts disable a subscription.
+	MaxFailures int
+}
+
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		BatchSize:    50,
+		PollInterval: time.Second,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		MinBackoff:   5 * time.Second,
+		MaxBackoff:   time.Hour,
+		MaxFailures:  20,
+	}
+}
+
+type Dispatcher struct {
+	subscriptionRepo repository.WebhookSubscriptionRepo
+	deliveryRepo     repository.WebhookDeliveryRepo
+	client           *http.Client
+	cfg              DispatcherConfig
+	now              func() time.Time
+}
+
+func NewDispatcher(
+	subscriptionRepo repository.WebhookSubscriptionRepo,
+	deliveryRepo repository.WebhookDeliveryRepo,
+	client *http.Client,
+	cfg DispatcherConfig,
+) *Dispatcher {
+	return &Dispatcher{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		client:           client,
+		cfg:              cfg,
+		now:              time.Now,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.DispatchBatch(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "webhook dispatch failed", slog.Any("error", err))
+				break
+			}
+			if n < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchBatch sends up to one batch of due deliveries and returns how many
+// were claimed.
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	// A claimed delivery is not retried before its lease ends, so the lease
+	// has to outlast the slowest possible attempt of the whole batch.
+	lease := d.cfg.Timeout*time.Duration(d.cfg.BatchSize) + time.Minute
+
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, d.now(), lease, d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := d.dispatch(ctx, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, delivery dto.WebhookDelivery) error {
+	subscription, err := d.subscriptionRepo.GetForDelivery(ctx, delivery.SubscriptionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return d.deliveryRepo.MarkFailed(ctx, delivery.ID, 0, "subscription deleted")
+	}
+	if err != nil {
+		return err
+	}
+	if subscription.Active == nil || !*subscription.Active {
+		return d.deliveryRepo.MarkFailed(ctx, delivery.ID, 0, "subscription disabled")
+	}
+
+	statusCode, sendErr := d.send(ctx, subscription, delivery)
+	if sendErr == nil {
+		if err := d.deliveryRepo.MarkSucceeded(ctx, delivery.ID, statusCode, d.now()); err != nil {
+			return err
+		}
+		return d.subscriptionRepo.RecordResult(ctx, subscription.ID, true, d.cfg.MaxFailures)
+	}
+
+	slog.WarnContext(ctx, "webhook delivery failed",
+		slog.Uint64("delivery_id", delivery.ID),
+		slog.Uint64("subscription_id", uint64(subscription.ID)),
+		slog.Int("attempts", delivery.Attempts+1),
+		slog.Any("error", sendErr),
+	)
+
+	attempts := delivery.Attempts + 1
+	if attempts >= d.cfg.MaxAttempts {
+		err = d.deliveryRepo.MarkFailed(ctx, delivery.ID, statusCode, sendErr.Error())
+	} else {
+		err = d.deliveryRepo.MarkRetry(ctx, delivery.ID, statusCode, sendErr.Error(), d.now().Add(d.backoff(attempts)))
+	}
+	if err != nil {
+		return err
+	}
+
+	return d.subscriptionRepo.RecordResult(ctx, subscription.ID, false, d.cfg.MaxFailures)
+}
+
+func (d *Dispatcher) send(ctx context.Context, subscription *dto.WebhookSubscription, delivery dto.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "crud_app-webhooks/1")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.MinBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.cfg.MaxBackoff)
+}
@@ -0,0 +1,182 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/repository"
+	mock_repository "crud_app/repository/mocks_repository"
+)
+
+const testSecret string = "test-secret-0123456789"
+
+var (
+	testNow     = time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+	testPayload = json.RawMessage(`{"id":1,"type":"UserCreated"}`)
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, status int) (*httptest.Server, *[]receivedRequest) {
+	t.Helper()
+
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = append(received, receivedRequest{header: r.Header.Clone(), body: body})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &received
+}
+
+func newTestDispatcher(ctrl *gomock.Controller) (*Dispatcher, *mock_repository.MockWebhookSubscriptionRepo, *mock_repository.MockWebhookDeliveryRepo) {
+	subscriptionRepo := mock_repository.NewMockWebhookSubscriptionRepo(ctrl)
+	deliveryRepo := mock_repository.NewMockWebhookDeliveryRepo(ctrl)
+
+	dispatcher := NewDispatcher(subscriptionRepo, deliveryRepo, http.DefaultClient, DispatcherConfig{
+		BatchSize:    10,
+		PollInterval: time.Second,
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		MinBackoff:   time.Second,
+		MaxBackoff:   time.Minute,
+		MaxFailures:  5,
+	})
+	dispatcher.now = func() time.Time { return testNow }
+
+	return dispatcher, subscriptionRepo, deliveryRepo
+}
+
+func TestDispatcher_DispatchBatch(t *testing.T) {
+	type testCase struct {
+		name       string
+		status     int
+		attempts   int
+		active     bool
+		setupMocks func(*mock_repository.MockWebhookSubscriptionRepo, *mock_repository.MockWebhookDeliveryRepo)
+		requests   int
+	}
+
+	cases := []testCase{
+		{
+			name:   "delivered",
+			status: http.StatusNoContent,
+			active: true,
+			setupMocks: func(sr *mock_repository.MockWebhookSubscriptionRepo, dr *mock_repository.MockWebhookDeliveryRepo) {
+				dr.EXPECT().MarkSucceeded(gomock.Any(), uint64(9), http.StatusNoContent, testNow).Return(nil)
+				sr.EXPECT().RecordResult(gomock.Any(), uint(3), true, 5).Return(nil)
+			},
+			requests: 1,
+		}, {
+			name:     "receiver error is retried with backoff",
+			status:   http.StatusBadGateway,
+			attempts: 1,
+			active:   true,
+			setupMocks: func(sr *mock_repository.MockWebhookSubscriptionRepo, dr *mock_repository.MockWebhookDeliveryRepo) {
+				dr.EXPECT().
+					MarkRetry(gomock.Any(), uint64(9), http.StatusBadGateway, "receiver responded with 502 Bad Gateway", testNow.Add(2*time.Second)).
+					Return(nil)
+				sr.EXPECT().RecordResult(gomock.Any(), uint(3), false, 5).Return(nil)
+			},
+			requests: 1,
+		}, {
+			name:     "last attempt fails the delivery",
+			status:   http.StatusInternalServerError,
+			attempts: 2,
+			active:   true,
+			setupMocks: func(sr *mock_repository.MockWebhookSubscriptionRepo, dr *mock_repository.MockWebhookDeliveryRepo) {
+				dr.EXPECT().
+					MarkFailed(gomock.Any(), uint64(9), http.StatusInternalServerError, gomock.Any()).
+					Return(nil)
+				sr.EXPECT().RecordResult(gomock.Any(), uint(3), false, 5).Return(nil)
+			},
+			requests: 1,
+		}, {
+			name:   "disabled subscription is not called",
+			status: http.StatusOK,
+			active: false,
+			setupMocks: func(sr *mock_repository.MockWebhookSubscriptionRepo, dr *mock_repository.MockWebhookDeliveryRepo) {
+				dr.EXPECT().MarkFailed(gomock.Any(), uint64(9), 0, "subscription disabled").Return(nil)
+			},
+			requests: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			receiver, received := newReceiver(t, tc.status)
+			dispatcher, sr, dr := newTestDispatcher(ctrl)
+
+			dr.EXPECT().
+				ClaimDue(gomock.Any(), testNow, gomock.Any(), 10).
+				Return([]dto.WebhookDelivery{{
+					ID:             9,
+					SubscriptionID: 3,
+					EventID:        1,
+					EventType:      "UserCreated",
+					Payload:        testPayload,
+					Attempts:       tc.attempts,
+				}}, nil)
+			sr.EXPECT().
+				GetForDelivery(gomock.Any(), uint(3)).
+				Return(&dto.WebhookSubscription{ID: 3, URL: receiver.URL, Secret: testSecret, Active: &tc.active}, nil)
+			tc.setupMocks(sr, dr)
+
+			n, err := dispatcher.DispatchBatch(t.Context())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+			require.Len(t, *received, tc.requests)
+
+			for _, req := range *received {
+				require.JSONEq(t, string(testPayload), string(req.body))
+				require.Equal(t, "UserCreated", req.header.Get(EventHeader))
+				require.Equal(t, "9", req.header.Get(DeliveryHeader))
+
+				timestamp, err := strconv.ParseInt(req.header.Get(TimestampHeader), 10, 64)
+				require.NoError(t, err)
+				require.True(t, Verify(testSecret, timestamp, req.body, req.header.Get(SignatureHeader)))
+			}
+		})
+	}
+}
+
+func TestDispatcher_DeletedSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dispatcher, sr, dr := newTestDispatcher(ctrl)
+
+	dr.EXPECT().
+		ClaimDue(gomock.Any(), testNow, gomock.Any(), 10).
+		Return([]dto.WebhookDelivery{{ID: 9, SubscriptionID: 3}}, nil)
+	sr.EXPECT().GetForDelivery(gomock.Any(), uint(3)).Return(nil, repository.ErrNotFound)
+	dr.EXPECT().MarkFailed(gomock.Any(), uint64(9), 0, "subscription deleted").Return(nil)
+
+	_, err := dispatcher.DispatchBatch(t.Context())
+	require.NoError(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"id":1}`)
+	signature := Sign(testSecret, 1700000000, payload)
+
+	require.True(t, Verify(testSecret, 1700000000, payload, signature))
+	require.False(t, Verify("other-secret-0123456789", 1700000000, payload, signature))
+	require.False(t, Verify(testSecret, 1700000001, payload, signature))
+	require.False(t, Verify(testSecret, 1700000000, []byte(`{"id":2}`), signature))
+	require.False(t, Verify(testSecret, 1700000000, payload, "bogus"))
+}
@@ -0,0 +1,77 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"crud_app/dto"
+	"crud_app/events"
+	"crud_app/repository"
+)
+
+type payload struct {
+	ID         uint64          `json:"id"`
+	Type       string          `json:"type"`
+	TenantID   string          `json:"tenant_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type enqueuer struct {
+	subscriptionRepo repository.WebhookSubscriptionRepo
+	deliveryRepo     repository.WebhookDeliveryRepo
+}
+
+// NewEnqueuer returns an events.EventPublisher that turns every event into
+// one pending delivery per matching active subscription of its tenant.
+func NewEnqueuer(subscriptionRepo repository.WebhookSubscriptionRepo, deliveryRepo repository.WebhookDeliveryRepo) events.EventPublisher {
+	return &enqueuer{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+	}
+}
+
+func (e *enqueuer) Publish(ctx context.Context, event events.Event) error {
+	subscriptions, err := e.subscriptionRepo.ListActive(ctx, event.TenantID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		TenantID:   event.TenantID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	var deliveries []dto.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !Matches(subscription, event.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, dto.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			TenantID:       event.TenantID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        body,
+			Status:         dto.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now().UTC(),
+		})
+	}
+
+	return e.deliveryRepo.Enqueue(ctx, deliveries)
+}
+
+// Matches reports whether a subscription wants an event type; an empty filter
+// matches everything.
+func Matches(subscription dto.WebhookSubscription, eventType string) bool {
+	return len(subscription.EventTypes) == 0 || slices.Contains(subscription.EventTypes, eventType)
+}
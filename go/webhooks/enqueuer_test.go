@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"crud_app/dto"
+	"crud_app/events"
+	mock_repository "crud_app/repository/mocks_repository"
+)
+
+func TestEnqueuer_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	subscriptionRepo := mock_repository.NewMockWebhookSubscriptionRepo(ctrl)
+	deliveryRepo := mock_repository.NewMockWebhookDeliveryRepo(ctrl)
+
+	event := events.Event{
+		ID:         4,
+		Type:       events.UserDeleted,
+		TenantID:   "acme",
+		Payload:    json.RawMessage(`{"id":7}`),
+		OccurredAt: testNow,
+	}
+
+	subscriptionRepo.EXPECT().
+		ListActive(gomock.Any(), "acme").
+		Return([]dto.WebhookSubscription{
+			{ID: 1},
+			{ID: 2, EventTypes: []string{events.UserCreated}},
+			{ID: 3, EventTypes: []string{events.UserCreated, events.UserDeleted}},
+		}, nil)
+	deliveryRepo.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, deliveries []dto.WebhookDelivery) error {
+			require.Len(t, deliveries, 2)
+			require.Equal(t, uint(1), deliveries[0].SubscriptionID)
+			require.Equal(t, uint(3), deliveries[1].SubscriptionID)
+			for _, delivery := range deliveries {
+				require.Equal(t, dto.WebhookDeliveryPending, delivery.Status)
+				require.Equal(t, uint64(4), delivery.EventID)
+				require.JSONEq(t,
+					`{"id":4,"type":"UserDeleted","tenant_id":"acme","occurred_at":"2025-10-18T12:00:00Z","data":{"id":7}}`,
+					string(delivery.Payload))
+			}
+			return nil
+		})
+
+	err := NewEnqueuer(subscriptionRepo, deliveryRepo).Publish(t.Context(), event)
+	require.NoError(t, err)
+}
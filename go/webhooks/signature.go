@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	SignatureHeader string = "X-Webhook-Signature"
+	TimestampHeader string = "X-Webhook-Timestamp"
+	EventHeader     string = "X-Webhook-Event"
+	DeliveryHeader  string = "X-Webhook-Delivery"
+
+	signaturePrefix string = "sha256="
+)
+
+// Sign returns the X-Webhook-Signature value for a payload. The timestamp is
+// part of the signed message so receivers can reject replays.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}